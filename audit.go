@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/audit"
+	"github.com/malika/osint-master/pkg/lookup"
+)
+
+// auditUsage lists the audit subcommands
+const auditUsage = "usage: osintmaster audit verify [--dir path]"
+
+// runAuditCommand handles "osintmaster audit verify"
+func runAuditCommand(args []string) error {
+	if len(args) == 0 || args[0] != "verify" {
+		return fmt.Errorf(auditUsage)
+	}
+
+	cfg := config.LoadConfig()
+	defaultDir, err := audit.Dir(cfg)
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("audit verify", flag.ContinueOnError)
+	dir := fs.String("dir", defaultDir, "Audit directory to verify")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	report, err := audit.Verify(*dir)
+	var tampered *audit.TamperError
+	if errors.As(err, &tampered) {
+		fmt.Printf("❌ %s: the chain is broken after %d intact entries\n", report.Path, report.Entries)
+		return fmt.Errorf("audit log tampered at %v", tampered)
+	}
+	if err != nil {
+		return err
+	}
+
+	if report.Entries == 0 {
+		fmt.Printf("%s is empty\n", report.Path)
+		return nil
+	}
+	fmt.Printf("✅ %s: %d entries intact, %s to %s\n", report.Path, report.Entries,
+		formatCaseTime(report.First), formatCaseTime(report.Last))
+	fmt.Printf("Head hash: %s\n", report.Head)
+	fmt.Println("Keep the head hash elsewhere, removing the newest entries only shows against it")
+	return nil
+}
+
+// auditLookups records every lookup of the runner in the audit log, attributed to caseName when set
+func auditLookups(cfg *config.Config, runner *lookup.Runner, caseName string) error {
+	dir, err := audit.Dir(cfg)
+	if err != nil {
+		return err
+	}
+	l, err := audit.Open(dir, audit.Operator(cfg), caseName)
+	if err != nil {
+		return err
+	}
+	runner.Audit(l)
+	return nil
+}
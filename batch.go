@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/malika/osint-master/internal/output"
+	"github.com/malika/osint-master/internal/validator"
+	"github.com/malika/osint-master/pkg/lookup"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// typeAuto detects the module of every batch target
+const typeAuto = "auto"
+
+// batchTarget is one line of a batch file
+type batchTarget struct {
+	Index  int // position among the targets, used to name per-target files
+	Line   int // 1-based line number in the batch file
+	Module string
+	Target string
+	Err    error // set when the module could not be determined
+}
+
+// batchOptions controls how a batch is run and where results go
+type batchOptions struct {
+	Workers int
+	OutDir  string // one file per target, empty streams NDJSON
+	Output  string // NDJSON stream file, empty writes to stdout
+	Format  string // format of the per-target files
+	Lookup  lookup.Options
+	DryRun  bool // list the requests of every target instead of sending them
+}
+
+// batchResult is a finished batch target
+type batchResult struct {
+	target batchTarget
+	res    *result.Result
+	err    error
+}
+
+// runBatchCommand reads the targets in path and looks them up
+func runBatchCommand(ctx context.Context, runner *lookup.Runner, path, module string, opts batchOptions) error {
+	if module != typeAuto && !lookup.Supported(module) {
+		return fmt.Errorf("unknown target type: %s (use auto or %s)", module, strings.Join(lookup.Modules(), ", "))
+	}
+
+	in, err := openBatchInput(path)
+	if err != nil {
+		return fmt.Errorf("failed to open batch file: %v", err)
+	}
+	targets, err := readBatchTargets(in, module)
+	in.Close()
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no targets found in %s", path)
+	}
+
+	if opts.DryRun {
+		selectors := make([]selector, 0, len(targets))
+		for _, t := range targets {
+			if t.Err != nil {
+				fmt.Fprintf(os.Stderr, "Skipping line %d: %v\n", t.Line, t.Err)
+				continue
+			}
+			selectors = append(selectors, selector{t.Module, t.Target})
+		}
+		return printPlans(runner, selectors, opts.Lookup, opts.Format)
+	}
+
+	fmt.Fprintf(os.Stderr, "Looking up %d targets with %d workers\n", len(targets), opts.Workers)
+	failed, err := runBatch(ctx, runner, targets, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Batch complete: %d succeeded, %d failed\n", len(targets)-failed, failed)
+	if opts.OutDir != "" {
+		fmt.Fprintf(os.Stderr, "Results saved in %s\n", opts.OutDir)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d targets failed", failed, len(targets))
+	}
+	return nil
+}
+
+// openBatchInput opens the batch file, "-" reads stdin
+func openBatchInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// readBatchTargets reads one target per line, skipping blank lines and # comments
+// A line may name its module as "module:target", otherwise module or auto-detection decides
+func readBatchTargets(r io.Reader, module string) ([]batchTarget, error) {
+	var targets []batchTarget
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		t := batchTarget{Index: len(targets) + 1, Line: lineNo, Module: module, Target: line}
+		if prefix, rest, ok := strings.Cut(line, ":"); ok && lookup.Supported(prefix) {
+			t.Module, t.Target = prefix, strings.TrimSpace(rest)
+		} else if module == typeAuto {
+			d, err := validator.Classify(line)
+			t.Module, t.Err = d.Module, err
+			if err == nil {
+				t.Target = d.Target
+			}
+		}
+		targets = append(targets, t)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read targets: %v", err)
+	}
+	return targets, nil
+}
+
+// runBatch looks up every target with a bounded pool of workers
+// Provider rate limits are enforced by the shared HTTP client, so workers only bound concurrency
+// It returns the number of targets that failed or were skipped
+func runBatch(ctx context.Context, runner *lookup.Runner, targets []batchTarget, opts batchOptions) (int, error) {
+	if opts.Workers < 1 {
+		opts.Workers = 1
+	}
+
+	stream := io.Writer(os.Stdout)
+	if opts.OutDir != "" {
+		if err := os.MkdirAll(opts.OutDir, 0755); err != nil {
+			return 0, fmt.Errorf("failed to create output directory: %v", err)
+		}
+	} else if opts.Output != "" {
+		file, err := os.Create(opts.Output)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create output file: %v", err)
+		}
+		defer file.Close()
+		stream = file
+	}
+
+	jobs := make(chan batchTarget)
+	results := make(chan batchResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				if t.Err != nil {
+					results <- batchResult{target: t, err: t.Err}
+					continue
+				}
+				res, err := runner.Run(ctx, t.Module, t.Target, opts.Lookup)
+				results <- batchResult{target: t, res: res, err: err}
+			}
+		}()
+	}
+
+	// Stop handing out targets once the batch is cancelled
+	go func() {
+		defer close(jobs)
+		for _, t := range targets {
+			select {
+			case jobs <- t:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	done, failed := 0, 0
+	var writeErr error
+	for r := range results {
+		done++
+		res := r.res
+		if r.err != nil {
+			failed++
+			res = result.Failure(r.target.Module, r.target.Target, r.err)
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s: %v\n", done, len(targets), r.target.Target, r.err)
+		} else {
+			fmt.Fprintf(os.Stderr, "[%d/%d] %s (%s) done\n", done, len(targets), r.target.Target, r.target.Module)
+		}
+
+		if writeErr == nil {
+			writeErr = writeBatchResult(stream, r.target, res, opts)
+		}
+	}
+
+	// Targets never started count as failed
+	failed += len(targets) - done
+	return failed, writeErr
+}
+
+// writeBatchResult writes one result to the NDJSON stream or its own file
+func writeBatchResult(stream io.Writer, t batchTarget, res *result.Result, opts batchOptions) error {
+	if opts.OutDir == "" {
+		line, err := output.Format(output.FormatNDJSON, res)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(stream, line)
+		return err
+	}
+
+	module := t.Module
+	if module == "" {
+		module = "unknown"
+	}
+	name := fmt.Sprintf("%04d-%s-%s%s", t.Index, module, safeFileName(t.Target), formatExtension(opts.Format))
+	return output.SaveResults(filepath.Join(opts.OutDir, name), opts.Format, res)
+}
+
+// unsafeFileChars matches characters replaced in per-target file names
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._+-]+`)
+
+// safeFileName turns a target into a file name
+func safeFileName(target string) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(target, "_"), "_.")
+	if len(name) > 80 {
+		name = name[:80]
+	}
+	if name == "" {
+		name = "target"
+	}
+	return name
+}
+
+// formatExtension returns the file extension for an output format
+func formatExtension(format string) string {
+	switch format {
+	case output.FormatJSON:
+		return ".json"
+	case output.FormatNDJSON:
+		return ".ndjson"
+	}
+	return ".txt"
+}
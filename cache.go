@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/cache"
+)
+
+// runCacheCommand handles "osintmaster cache stats|purge"
+func runCacheCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: osintmaster cache stats|purge [--expired]")
+	}
+
+	store, err := openCache(config.LoadConfig())
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "stats":
+		return printCacheStats(store)
+	case "purge":
+		fs := flag.NewFlagSet("cache purge", flag.ContinueOnError)
+		expired := fs.Bool("expired", false, "Only remove entries past their TTL")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		removed, err := store.Purge(*expired)
+		if err != nil {
+			return fmt.Errorf("failed to purge cache: %v", err)
+		}
+		fmt.Printf("Removed %d cached responses from %s\n", removed, store.Dir())
+		return nil
+	default:
+		return fmt.Errorf("unknown cache command: %s (use stats or purge)", args[0])
+	}
+}
+
+// openCache opens the cache directory configured in cfg
+func openCache(cfg *config.Config) (*cache.Store, error) {
+	dir := cfg.CacheDir
+	if dir == "" {
+		var err error
+		if dir, err = cache.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return cache.Open(dir)
+}
+
+// printCacheStats prints the number of entries per provider
+func printCacheStats(store *cache.Store) error {
+	stats, err := store.Stats()
+	if err != nil {
+		return fmt.Errorf("failed to read cache: %v", err)
+	}
+
+	fmt.Printf("Cache directory: %s\n", stats.Dir)
+	fmt.Printf("Entries:         %d (%d expired)\n", stats.Entries, stats.Expired)
+	fmt.Printf("Size:            %.1f KB\n", float64(stats.Bytes)/1024)
+
+	if len(stats.Providers) == 0 {
+		return nil
+	}
+
+	providers := make([]string, 0, len(stats.Providers))
+	for provider := range stats.Providers {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	fmt.Println("\nProviders:")
+	for _, provider := range providers {
+		fmt.Printf("  %-30s %d\n", provider, stats.Providers[provider])
+	}
+	return nil
+}
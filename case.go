@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/casestore"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/output"
+	"github.com/malika/osint-master/pkg/lookup"
+	"github.com/malika/osint-master/pkg/render"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// caseTimeFormat is how case timestamps are printed
+const caseTimeFormat = "2006-01-02 15:04"
+
+// caseUsage lists the case subcommands
+const caseUsage = "usage: osintmaster case list | show <case> [id] [--raw] | search <case> <query> | export <case> [-o file] [--format json|ndjson|text]"
+
+// runCaseCommand handles "osintmaster case list|show|search|export"
+func runCaseCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(caseUsage)
+	}
+
+	cfg := config.LoadConfig()
+	dir, err := caseDir(cfg)
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("case "+args[0], flag.ContinueOnError)
+	raw := fs.Bool("raw", false, "Print the raw provider responses")
+	out := fs.String("o", "", "File to export to (default stdout)")
+	format := fs.String("format", output.FormatJSON, "Export format: json, ndjson or text")
+	params, err := parseArgs(fs, args[1:])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		return printCaseList(dir)
+	case "show", "search", "export":
+	default:
+		return fmt.Errorf("unknown case command: %s (use list, show, search or export)", args[0])
+	}
+
+	if len(params) == 0 {
+		return fmt.Errorf(caseUsage)
+	}
+	c, err := casestore.OpenExisting(dir, params[0])
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	switch args[0] {
+	case "show":
+		if len(params) < 2 {
+			return printCaseLookups(c)
+		}
+		id, err := strconv.ParseUint(params[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid lookup id: %s", params[1])
+		}
+		return printCaseLookup(c, id, *raw)
+	case "search":
+		if len(params) < 2 {
+			return fmt.Errorf(caseUsage)
+		}
+		return printCaseSearch(c, params[1])
+	default:
+		return exportCase(c, *out, *format)
+	}
+}
+
+// caseDir returns the case directory configured in cfg
+func caseDir(cfg *config.Config) (string, error) {
+	if cfg.CaseDir != "" {
+		return cfg.CaseDir, nil
+	}
+	return casestore.DefaultDir()
+}
+
+// openCase opens or creates the case used by --case
+func openCase(cfg *config.Config, name string) (*casestore.Case, error) {
+	dir, err := caseDir(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := casestore.Open(dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := c.Info()
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Recording lookups in case %s (%d earlier lookups)\n", name, info.Lookups)
+	return c, nil
+}
+
+// recordCase stores every lookup the runner makes in c
+// Targets that were looked up before are pointed out so work is not repeated unknowingly
+func recordCase(runner *lookup.Runner, c *casestore.Case) {
+	runner.Observe(func(res *result.Result, responses []httpclient.Response) {
+		if earlier, err := c.Find(res.Module, res.Target); err == nil && len(earlier) > 0 {
+			last := earlier[len(earlier)-1]
+			fmt.Fprintf(os.Stderr, "Note: %s was already looked up in case %s on %s (#%d)\n",
+				res.Target, c.Name(), last.StoredAt.Local().Format(caseTimeFormat), last.ID)
+		}
+		if _, err := c.Add(res, responses); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	})
+}
+
+// printCaseList prints every case with its size and last use
+func printCaseList(dir string) error {
+	infos, err := casestore.List(dir)
+	if err != nil {
+		return err
+	}
+	if len(infos) == 0 {
+		fmt.Printf("No cases in %s\n", dir)
+		return nil
+	}
+
+	fmt.Printf("%-24s %8s  %-16s  %-16s  %s\n", "CASE", "LOOKUPS", "CREATED", "LAST LOOKUP", "SIZE")
+	for _, info := range infos {
+		fmt.Printf("%-24s %8d  %-16s  %-16s  %.1f KB\n", info.Name, info.Lookups,
+			formatCaseTime(info.Created), formatCaseTime(info.Updated), float64(info.Bytes)/1024)
+	}
+	return nil
+}
+
+// printCaseLookups prints one line per lookup of a case
+func printCaseLookups(c *casestore.Case) error {
+	info, err := c.Info()
+	if err != nil {
+		return err
+	}
+	records, err := c.Records()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Case %s: %d lookups, created %s, last lookup %s\n\n", info.Name, info.Lookups,
+		formatCaseTime(info.Created), formatCaseTime(info.Updated))
+	if len(records) == 0 {
+		return nil
+	}
+
+	fmt.Printf("%5s  %-16s  %-8s  %-32s  %s\n", "ID", "STORED", "MODULE", "TARGET", "FINDINGS")
+	for _, r := range records {
+		findings := strconv.Itoa(r.Findings)
+		if r.Failed {
+			findings = "failed: " + r.Result.Errors[0].Message
+		} else if r.Result.Partial {
+			findings += " (partial)"
+		}
+		fmt.Printf("%5d  %-16s  %-8s  %-32s  %s\n", r.ID, formatCaseTime(r.StoredAt), r.Module, r.Target, findings)
+	}
+	return nil
+}
+
+// printCaseLookup prints a stored result and the responses it was built from
+func printCaseLookup(c *casestore.Case, id uint64, raw bool) error {
+	r, err := c.Get(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Lookup #%d of case %s, stored %s\n\n", r.ID, c.Name(), formatCaseTime(r.StoredAt))
+	fmt.Println(render.Text(r.Result))
+
+	fmt.Printf("\nRaw provider responses (%d):\n", len(r.Responses))
+	for _, resp := range r.Responses {
+		if resp.Error != "" {
+			fmt.Printf("  ERR %s %s: %s\n", resp.Method, resp.URL, resp.Error)
+			continue
+		}
+		size := fmt.Sprintf("%.1f KB", float64(len(resp.Body))/1024)
+		if resp.Truncated {
+			size += ", truncated"
+		}
+		fmt.Printf("  %d %s %s (%s)\n", resp.Status, resp.Method, resp.URL, size)
+		if raw {
+			fmt.Printf("%s\n\n", resp.Body)
+		}
+	}
+	return nil
+}
+
+// printCaseSearch prints the lookups matching query and the findings that matched
+func printCaseSearch(c *casestore.Case, query string) error {
+	matches, err := c.Search(query)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		fmt.Printf("Nothing in case %s matches %q\n", c.Name(), query)
+		return nil
+	}
+
+	fmt.Printf("%d lookups in case %s match %q:\n\n", len(matches), c.Name(), query)
+	for _, m := range matches {
+		fmt.Printf("#%d  %s  %s %s\n", m.Record.ID, formatCaseTime(m.Record.StoredAt), m.Record.Module, m.Record.Target)
+		for _, f := range m.Findings {
+			fmt.Printf("      %s: %s (%s)\n", f.Field, f.Value, f.Source)
+		}
+	}
+	return nil
+}
+
+// caseExport is the JSON document written by "case export"
+type caseExport struct {
+	Case    casestore.Info     `json:"case"`
+	Records []casestore.Record `json:"lookups"`
+}
+
+// exportCase writes every lookup of a case to filename, or stdout when it is empty
+// JSON and NDJSON include the raw provider responses, text is the combined report
+func exportCase(c *casestore.Case, filename, format string) error {
+	if format != output.FormatJSON && format != output.FormatNDJSON && format != output.FormatText {
+		return fmt.Errorf("unknown export format: %s (use json, ndjson or text)", format)
+	}
+
+	info, err := c.Info()
+	if err != nil {
+		return err
+	}
+	records, err := c.Export()
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if filename != "" {
+		f, err := os.Create(filename)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case output.FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(caseExport{Case: info, Records: records})
+	case output.FormatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err = enc.Encode(r); err != nil {
+				break
+			}
+		}
+	default:
+		results := make([]*result.Result, 0, len(records))
+		for _, r := range records {
+			results = append(results, r.Result)
+		}
+		var text string
+		if text, err = output.Format(output.FormatText, results...); err == nil {
+			_, err = fmt.Fprintln(w, text)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export case %s: %v", c.Name(), err)
+	}
+
+	if filename != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d lookups of case %s to %s\n", len(records), c.Name(), filename)
+	}
+	return nil
+}
+
+// formatCaseTime formats a case timestamp in local time, "-" when unset
+func formatCaseTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(caseTimeFormat)
+}
@@ -5,23 +5,63 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds application configuration
 type Config struct {
 	// API Keys (load from environment variables or .env file)
-	HIBPAPIKey         string // Have I Been Pwned
-	IPAPIKey           string
-	AbuseIPDBKey       string
-	PiplAPIKey         string
-	SecurityTrailsKey  string
-	NumverifyKey       string // Phone number verification
-	IPQualityScoreKey  string // Phone/IP quality validation
-	AbstractAPIKey     string // Phone validation (AbstractAPI)
-	GoogleAPIKey       string
-	TwitterAPIKey      string
-	TwitterAPISecret   string
+	HIBPAPIKey        string // Have I Been Pwned
+	IPAPIKey          string
+	AbuseIPDBKey      string
+	PiplAPIKey        string
+	SecurityTrailsKey string
+	NumverifyKey      string // Phone number verification
+	IPQualityScoreKey string // Phone/IP quality validation
+	AbstractAPIKey    string // Phone validation (AbstractAPI)
+	GoogleAPIKey      string
+	TwitterAPIKey     string
+	TwitterAPISecret  string
+
+	// IP lookup providers (comma-separated provider names)
+	IPProviders         []string // explicit lookup order
+	IPProvidersDisabled []string // providers to skip
+
+	// Offline geolocation databases (.mmdb files)
+	GeoIPCityDB string // GeoLite2-City or DB-IP City Lite
+	GeoIPASNDB  string // GeoLite2-ASN or DB-IP ASN Lite
+
+	// Shared HTTP client, 0 or unset means "use the default"
+	// A negative HTTPRetries or RateLimit turns retries or rate limiting off
+	HTTPTimeout    time.Duration      // OSINT_HTTP_TIMEOUT, e.g. 15s
+	HTTPRetries    int                // OSINT_HTTP_RETRIES
+	RateLimit      float64            // OSINT_RATE_LIMIT, requests per second per host
+	RateBurst      int                // OSINT_RATE_BURST
+	HostRateLimits map[string]float64 // OSINT_HOST_RATE_LIMITS, e.g. crt.sh=0.5,ipinfo.io=1
+	Proxy          string             // OSINT_PROXY, e.g. socks5://127.0.0.1:9050
+	UserAgent      string             // OSINT_USER_AGENT
+
+	// Response cache
+	CacheDir      string                   // OSINT_CACHE_DIR, default ~/.osintmaster/cache
+	CacheDisabled bool                     // OSINT_CACHE=off
+	CacheTTL      time.Duration            // OSINT_CACHE_TTL, default 24h
+	CacheTTLs     map[string]time.Duration // OSINT_CACHE_TTLS, e.g. crt.sh=12h,apilayer.net=720h
+
+	// Investigation cases
+	CaseDir string // OSINT_CASE_DIR, default ~/.osintmaster/cases
+
+	// Engagement scope
+	ScopeFile string // OSINT_SCOPE_FILE, YAML file of in-scope domains, ranges and selectors
+
+	// Audit log
+	AuditDir string // OSINT_AUDIT_DIR, default ~/.osintmaster/audit
+	Operator string // OSINT_OPERATOR, default the OS user name
+
+	// HTTP fixtures, set by --record and --replay
+	RecordDir string // every exchange of the shared client is saved here
+	ReplayDir string // every request is answered from the exchanges saved here
 }
 
 // LoadConfig loads configuration from environment variables and .env file
@@ -41,11 +81,160 @@ func LoadConfig() *Config {
 		GoogleAPIKey:      os.Getenv("GOOGLE_API_KEY"),
 		TwitterAPIKey:     os.Getenv("TWITTER_API_KEY"),
 		TwitterAPISecret:  os.Getenv("TWITTER_API_SECRET"),
+
+		IPProviders:         splitList(os.Getenv("IP_PROVIDERS")),
+		IPProvidersDisabled: splitList(os.Getenv("IP_PROVIDERS_DISABLED")),
+
+		GeoIPCityDB: os.Getenv("GEOIP_CITY_DB"),
+		GeoIPASNDB:  os.Getenv("GEOIP_ASN_DB"),
+
+		HTTPTimeout:    parseDuration("OSINT_HTTP_TIMEOUT", os.Getenv("OSINT_HTTP_TIMEOUT")),
+		HTTPRetries:    parseInt("OSINT_HTTP_RETRIES", os.Getenv("OSINT_HTTP_RETRIES")),
+		RateLimit:      parseFloat("OSINT_RATE_LIMIT", os.Getenv("OSINT_RATE_LIMIT")),
+		RateBurst:      parseInt("OSINT_RATE_BURST", os.Getenv("OSINT_RATE_BURST")),
+		HostRateLimits: parseRates(os.Getenv("OSINT_HOST_RATE_LIMITS")),
+		Proxy:          os.Getenv("OSINT_PROXY"),
+		UserAgent:      os.Getenv("OSINT_USER_AGENT"),
+		CacheDir:       os.Getenv("OSINT_CACHE_DIR"),
+		CacheDisabled:  strings.EqualFold(os.Getenv("OSINT_CACHE"), "off"),
+		CacheTTL:       parseDuration("OSINT_CACHE_TTL", os.Getenv("OSINT_CACHE_TTL")),
+		CacheTTLs:      parseDurations("OSINT_CACHE_TTLS", os.Getenv("OSINT_CACHE_TTLS")),
+		CaseDir:        os.Getenv("OSINT_CASE_DIR"),
+		ScopeFile:      os.Getenv("OSINT_SCOPE_FILE"),
+		AuditDir:       os.Getenv("OSINT_AUDIT_DIR"),
+		Operator:       os.Getenv("OSINT_OPERATOR"),
 	}
 
 	return config
 }
 
+// Secrets returns the configured API key values, empty ones left out
+func (c *Config) Secrets() []string {
+	var secrets []string
+	for _, v := range []string{
+		c.HIBPAPIKey, c.IPAPIKey, c.AbuseIPDBKey, c.PiplAPIKey, c.SecurityTrailsKey, c.NumverifyKey,
+		c.IPQualityScoreKey, c.AbstractAPIKey, c.GoogleAPIKey, c.TwitterAPIKey, c.TwitterAPISecret,
+	} {
+		if v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+	return secrets
+}
+
+// BaseURL returns the base URL override for a provider, or defaultURL when none is set
+// The override is read from <NAME>_BASE_URL, e.g. IP_API_COM_BASE_URL for "ip-api.com"
+func (c *Config) BaseURL(name, defaultURL string) string {
+	if url := os.Getenv(envName(name) + "_BASE_URL"); url != "" {
+		return strings.TrimRight(url, "/")
+	}
+	return defaultURL
+}
+
+// envName converts a provider name to an environment variable prefix
+func envName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseDuration reads a duration such as "15s", a bare number is taken as seconds
+// It returns 0 when unset and -1 with a warning naming the variable when invalid
+func parseDuration(name, value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(value)
+	if seconds, serr := strconv.ParseFloat(value, 64); serr == nil {
+		d, err = time.Duration(seconds*float64(time.Second)), nil
+	}
+	if err != nil || d < 0 {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid duration %q in %s\n", value, name)
+		return -1
+	}
+	return d
+}
+
+// parseInt reads an integer, negative values are kept to turn a setting off
+// It returns 0 when unset and when invalid, with a warning naming the variable
+func parseInt(name, value string) int {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid number %q in %s\n", value, name)
+		return 0
+	}
+	return n
+}
+
+// parseFloat reads a number, negative values are kept to turn a setting off
+// It returns 0 when unset and when invalid, with a warning naming the variable
+func parseFloat(name, value string) float64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid number %q in %s\n", value, name)
+		return 0
+	}
+	return f
+}
+
+// parseRates reads a comma-separated list of host=rate pairs
+func parseRates(value string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, item := range splitList(value) {
+		host, rate, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		if r, err := strconv.ParseFloat(strings.TrimSpace(rate), 64); err == nil && r >= 0 {
+			rates[strings.ToLower(strings.TrimSpace(host))] = r
+		}
+	}
+	return rates
+}
+
+// parseDurations reads a comma-separated list of host=duration pairs
+// Invalid pairs are left out with a warning, so a typo does not turn into 0
+func parseDurations(name, value string) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for _, item := range splitList(value) {
+		host, d, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(d) == "" {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring %q in %s, want host=duration\n", item, name)
+			continue
+		}
+		if duration := parseDuration(name, d); duration >= 0 {
+			durations[strings.ToLower(strings.TrimSpace(host))] = duration
+		}
+	}
+	return durations
+}
+
 // loadEnvFile loads environment variables from .env file
 func loadEnvFile() {
 	// Get user's home directory
@@ -188,6 +377,66 @@ TWITTER_API_SECRET=your_twitter_api_secret_here
 # Enables: Enhanced Google account lookup
 GOOGLE_API_KEY=your_google_api_key_here
 
+# IP Lookup Providers (Optional)
+# Providers: mmdb, ip-api.com, ipinfo.io, ipapi.co, ipwhois.app
+# IP_PROVIDERS sets the lookup order, IP_PROVIDERS_DISABLED skips providers
+# IP_PROVIDERS=ipinfo.io,ip-api.com
+# IP_PROVIDERS_DISABLED=ipwhois.app
+
+# Offline IP Geolocation Databases (Optional)
+# Download GeoLite2-City/ASN from https://dev.maxmind.com/geoip/geolite2-free-geolocation-data
+# or DB-IP lite from https://db-ip.com/db/lite.php
+# Enables: the "mmdb" provider, tried before the online providers
+# For air-gapped use, also disable the online providers with IP_PROVIDERS_DISABLED
+# GEOIP_CITY_DB=/path/to/GeoLite2-City.mmdb
+# GEOIP_ASN_DB=/path/to/GeoLite2-ASN.mmdb
+
+# HTTP Client (Optional)
+# Timeout covers a whole request including retries (default 15s)
+# Requests are retried on 429/5xx with jittered backoff, honouring Retry-After
+# Rate limits are requests per second per host (default 2, ip-api.com 0.75)
+# Unset or 0 keeps the default, -1 turns retries or rate limiting off
+# OSINT_HTTP_TIMEOUT=15s
+# OSINT_HTTP_RETRIES=2
+# OSINT_RATE_LIMIT=2
+# OSINT_RATE_BURST=4
+# OSINT_HOST_RATE_LIMITS=crt.sh=0.5,ipinfo.io=1
+# OSINT_PROXY=socks5://127.0.0.1:9050
+# OSINT_USER_AGENT=OSINT-Master/1.0 (educational OSINT tool)
+
+# Response Cache (Optional)
+# Responses are kept under ~/.osintmaster/cache and reused until their TTL expires
+# (default 24h, Numverify 30 days, other phone APIs 7 days). A host TTL of 0
+# disables caching for that host. Use --no-cache or --refresh to skip it per run
+# OSINT_CACHE=off
+# OSINT_CACHE_DIR=/path/to/cache
+# OSINT_CACHE_TTL=24h
+# OSINT_CACHE_TTLS=crt.sh=12h,apilayer.net=720h
+
+# Investigation Cases (Optional)
+# Lookups run with --case <name> are kept in ~/.osintmaster/cases/<name>.db
+# OSINT_CASE_DIR=/path/to/cases
+
+# Engagement Scope (Optional)
+# Active checks (TLS handshakes, takeover probes, browser automation) are refused
+# for targets outside the scope file, and every check is logged. Same as --scope
+# OSINT_SCOPE_FILE=/path/to/scope.yaml
+
+# Audit Log
+# Every lookup and outbound request is appended to a hash-chained log in
+# ~/.osintmaster/audit, check it with "osintmaster audit verify"
+# OSINT_AUDIT_DIR=/path/to/audit
+# OSINT_OPERATOR=jdoe
+
+# Provider Base URLs (Optional)
+# Override any provider endpoint with <PROVIDER NAME>_BASE_URL, where the name
+# is uppercased and non-alphanumeric characters become underscores
+# IP_API_COM_BASE_URL=http://ip-api.com
+# IPINFO_IO_BASE_URL=https://ipinfo.io
+# CRT_SH_BASE_URL=https://crt.sh
+# HAVEIBEENPWNED_COM_BASE_URL=https://haveibeenpwned.com
+# APILAYER_NET_BASE_URL=http://apilayer.net
+
 # Instructions:
 # 1. Copy this file to ~/.osintmaster/.env
 # 2. Replace "your_*_key_here" with actual API keys
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"15s", 15 * time.Second},
+		{"2.5", 2500 * time.Millisecond},
+		{" 1h ", time.Hour},
+		{"0", 0},
+		{"15 s", -1},
+		{"soon", -1},
+		{"-5s", -1},
+	}
+
+	for _, tc := range tests {
+		if got := parseDuration("OSINT_HTTP_TIMEOUT", tc.value); got != tc.want {
+			t.Errorf("parseDuration(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestParseNumbers(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantInt   int
+		wantFloat float64
+	}{
+		{"", 0, 0},
+		{"3", 3, 3},
+		{" 0 ", 0, 0},
+		{"-1", -1, -1},
+		{"0.5", 0, 0.5},
+		{"many", 0, 0},
+	}
+
+	for _, tc := range tests {
+		if got := parseInt("OSINT_HTTP_RETRIES", tc.value); got != tc.wantInt {
+			t.Errorf("parseInt(%q) = %d, want %d", tc.value, got, tc.wantInt)
+		}
+		if got := parseFloat("OSINT_RATE_LIMIT", tc.value); got != tc.wantFloat {
+			t.Errorf("parseFloat(%q) = %v, want %v", tc.value, got, tc.wantFloat)
+		}
+	}
+}
+
+func TestParseDurations(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]time.Duration
+	}{
+		{"empty", "", map[string]time.Duration{}},
+		{"valid", "crt.sh=1h, IPINFO.io=0", map[string]time.Duration{"crt.sh": time.Hour, "ipinfo.io": 0}},
+		// A typo must not disable the cache of a host
+		{"invalid duration", "crt.sh=1hr,ipinfo.io=30m", map[string]time.Duration{"ipinfo.io": 30 * time.Minute}},
+		{"missing duration", "crt.sh=,ipinfo.io", map[string]time.Duration{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseDurations("OSINT_CACHE_TTLS", tc.value); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseDurations(%q) = %v, want %v", tc.value, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/casestore"
+	"github.com/malika/osint-master/internal/output"
+	"github.com/malika/osint-master/pkg/diff"
+)
+
+// diffUsage describes the diff subcommand
+const diffUsage = "usage: osintmaster diff <target> [--case name] [--module name] [--with id] [--format text|json]"
+
+// storedRun is a successful lookup kept in a case
+type storedRun struct {
+	caseName string
+	record   casestore.Record
+}
+
+// runDiffCommand handles "osintmaster diff <target>"
+// The latest stored run of the target is compared with the one before it, or with --with
+func runDiffCommand(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	caseName := fs.String("case", "", "Only compare runs kept in this case (default every case)")
+	module := fs.String("module", "", "Module of the runs when the target was looked up with several")
+	with := fs.Uint64("with", 0, "ID of the earlier run to compare with, needs --case")
+	format := fs.String("format", output.FormatText, "Output format: text or json")
+	params, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(params) != 1 {
+		return fmt.Errorf(diffUsage)
+	}
+	if *format != output.FormatText && *format != output.FormatJSON {
+		return fmt.Errorf("unknown diff format: %s (use text or json)", *format)
+	}
+	if *with != 0 && *caseName == "" {
+		return fmt.Errorf("--with needs --case, lookup IDs are numbered per case")
+	}
+	target := strings.TrimSpace(params[0])
+
+	runs, err := findRuns(config.LoadConfig(), *caseName, *module, target)
+	if err != nil {
+		return err
+	}
+	if len(runs) < 2 {
+		return fmt.Errorf("%s needs at least two stored runs to compare, found %d (use --case when looking it up)", target, len(runs))
+	}
+
+	latest, earlier := runs[len(runs)-1], runs[len(runs)-2]
+	if *with != 0 {
+		found := false
+		for _, r := range runs {
+			if r.record.ID == *with {
+				earlier, found = r, true
+			}
+		}
+		if !found {
+			return fmt.Errorf("case %s has no successful %s run #%d of %s", *caseName, latest.record.Module, *with, target)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Comparing run #%d of case %s with run #%d of case %s\n",
+		latest.record.ID, latest.caseName, earlier.record.ID, earlier.caseName)
+	d := diff.Compare(earlier.record.Result, latest.record.Result)
+
+	if *format == output.FormatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+	fmt.Print(diff.Text(d))
+	return nil
+}
+
+// findRuns returns the successful stored runs of target, oldest first
+// Runs of several modules are an error unless module picks one
+func findRuns(cfg *config.Config, caseName, module, target string) ([]storedRun, error) {
+	dir, err := caseDir(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{caseName}
+	if caseName == "" {
+		infos, err := casestore.List(dir)
+		if err != nil {
+			return nil, err
+		}
+		names = names[:0]
+		for _, info := range infos {
+			names = append(names, info.Name)
+		}
+	}
+
+	var runs []storedRun
+	modules := make(map[string]bool)
+	for _, name := range names {
+		c, err := casestore.OpenExisting(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		records, err := c.Find(module, target)
+		c.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range records {
+			if !r.Failed {
+				runs = append(runs, storedRun{caseName: name, record: r})
+				modules[r.Module] = true
+			}
+		}
+	}
+
+	if len(modules) > 1 {
+		found := make([]string, 0, len(modules))
+		for m := range modules {
+			found = append(found, m)
+		}
+		sort.Strings(found)
+		return nil, fmt.Errorf("%s was looked up as %s, choose one with --module", target, strings.Join(found, " and "))
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].record.StoredAt.Before(runs[j].record.StoredAt) })
+	return runs, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/malika/osint-master/internal/opsec"
+	"github.com/malika/osint-master/internal/output"
+	"github.com/malika/osint-master/pkg/lookup"
+)
+
+// plannedLookup is the --dry-run report of one target
+type plannedLookup struct {
+	Module   string          `json:"module"`
+	Target   string          `json:"target"`
+	Requests []opsec.Request `json:"requests"`
+}
+
+// printPlans lists the requests every selector's lookup would make without sending any
+func printPlans(runner *lookup.Runner, selectors []selector, opts lookup.Options, format string) error {
+	var plans []plannedLookup
+	for _, sel := range selectors {
+		if sel.target == "" {
+			continue
+		}
+		requests, err := runner.Plan(sel.module, sel.target, opts)
+		if err != nil {
+			return err
+		}
+		plans = append(plans, plannedLookup{Module: sel.module, Target: sel.target, Requests: requests})
+	}
+
+	if format != output.FormatText {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		if format == output.FormatJSON {
+			enc.SetIndent("", "  ")
+			return enc.Encode(plans)
+		}
+		for _, p := range plans {
+			if err := enc.Encode(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	fmt.Println("Dry run: nothing below is sent")
+	for _, p := range plans {
+		printPlan(p, opts.Passive)
+	}
+	return nil
+}
+
+// printPlan prints the requests of one lookup as a table
+func printPlan(p plannedLookup, passive bool) {
+	fmt.Printf("\n%s %s: %d requests\n", p.Module, p.Target, len(p.Requests))
+	if len(p.Requests) == 0 {
+		fmt.Println("  No outbound requests, only search links are built")
+		return
+	}
+
+	fmt.Printf("  %-7s  %-28s  %-13s  %-7s  %s\n", "METHOD", "HOST", "CLASS", "API KEY", "URL")
+	for _, r := range p.Requests {
+		class := string(r.Class)
+		if passive && r.Class == opsec.Active {
+			class += " (skip)"
+		}
+		key := "no"
+		if r.APIKey {
+			key = "yes"
+		}
+		fmt.Printf("  %-7s  %-28s  %-13s  %-7s  %s\n", r.Method, r.Host(), class, key, r.URL)
+		if r.When != "" {
+			fmt.Printf("  %-7s  %-28s  %-13s  %-7s  %s\n", "", "", "", "", "↳ "+r.When)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+// Package audit keeps the record of who looked up what
+// Every lookup, and every request it sends, is appended to a JSON lines file; each entry carries
+// the hash of the entry before it, so an edited, removed or reordered entry breaks the chain
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/cache"
+	"github.com/malika/osint-master/internal/httpclient"
+)
+
+// FileName is the name of the log in the audit directory
+const FileName = "audit.jsonl"
+
+// Entry events
+const (
+	EventLookup  = "lookup"  // a finished lookup
+	EventRequest = "request" // an outbound request made by a lookup
+)
+
+// Lookup statuses, requests use the HTTP status code or "error"
+const (
+	StatusOK      = "ok"
+	StatusPartial = "partial"
+	StatusFailed  = "failed"
+)
+
+// Entry is one line of the audit log
+type Entry struct {
+	Seq      uint64    `json:"seq"`
+	Time     time.Time `json:"time"`
+	Operator string    `json:"operator"`
+	Case     string    `json:"case,omitempty"`
+	Event    string    `json:"event"`
+	Module   string    `json:"module"`
+	Target   string    `json:"target"`
+	Method   string    `json:"method,omitempty"` // HTTP method, or DNS and TLS for direct connections
+	Host     string    `json:"host,omitempty"`
+	URL      string    `json:"url,omitempty"` // API keys are redacted
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Prev     string    `json:"prev"` // hash of the previous entry, empty for the first
+	Hash     string    `json:"hash"` // SHA-256 of the entry with an empty hash
+}
+
+// Log appends entries to the audit log of one directory
+type Log struct {
+	path     string
+	operator string
+	caseName string
+
+	mu sync.Mutex
+}
+
+// DefaultDir returns the default audit directory, ~/.osintmaster/audit
+func DefaultDir() (string, error) {
+	configDir, err := config.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "audit"), nil
+}
+
+// Dir returns the audit directory of cfg
+func Dir(cfg *config.Config) (string, error) {
+	if cfg.AuditDir != "" {
+		return cfg.AuditDir, nil
+	}
+	return DefaultDir()
+}
+
+// Operator returns the operator named in cfg, or the name of the OS user
+func Operator(cfg *config.Config) string {
+	if cfg.Operator != "" {
+		return cfg.Operator
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// Open opens the audit log in dir, creating it when needed
+// Entries are attributed to operator and, when it is not empty, to the case caseName
+func Open(dir, operator, caseName string) (*Log, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %v", err)
+	}
+	l := &Log{path: filepath.Join(dir, FileName), operator: operator, caseName: caseName}
+
+	// Fail now rather than on the first lookup when the log cannot be written
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	f.Close()
+	return l, nil
+}
+
+// Path returns the path of the log file
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append chains e to the last entry of the log and writes it
+// Seq, Time, Case, Prev and Hash are filled in, and Operator when it is empty
+func (l *Log) Append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	unlock, err := lockFile(l.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	last, err := lastEntry(f)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		e.Seq = last.Seq + 1
+		e.Prev = last.Hash
+	} else {
+		e.Seq = 1
+		e.Prev = ""
+	}
+	e.Time = time.Now().UTC()
+	if e.Operator == "" {
+		e.Operator = l.operator
+	}
+	e.Case = l.caseName
+	if e.Hash, err = hash(e); err != nil {
+		return err
+	}
+
+	line, err := marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %v", err)
+	}
+	return f.Sync()
+}
+
+// Lookup records a finished lookup, err is what the lookup returned
+func (l *Log) Lookup(ctx context.Context, module, target string, partial bool, err error) {
+	e := Entry{Operator: operatorFrom(ctx), Event: EventLookup, Module: module, Target: target, Status: StatusOK}
+	switch {
+	case err != nil:
+		e.Status, e.Error = StatusFailed, cache.RedactSecrets(err.Error())
+	case partial:
+		e.Status = StatusPartial
+	}
+	l.record(e)
+}
+
+// record appends an entry, a failed write is reported on stderr and never stops a lookup
+func (l *Log) record(e Entry) {
+	if err := l.Append(e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write audit log: %v\n", err)
+	}
+}
+
+// marshal encodes an entry the same way every time, the hash depends on it
+func marshal(e Entry) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(e); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// hash returns the hex SHA-256 of an entry encoded with an empty hash
+func hash(e Entry) (string, error) {
+	e.Hash = ""
+	data, err := marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// lastEntry reads the last entry of the log, nil when it is empty
+// Only the end of the file is read, widening the window until it holds the whole last line
+func lastEntry(f *os.File) (*Entry, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+
+	for window := int64(4096); ; window *= 2 {
+		if window > size {
+			window = size
+		}
+		buf := make([]byte, window)
+		if _, err := f.ReadAt(buf, size-window); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read audit log: %v", err)
+		}
+
+		buf = bytes.TrimRight(buf, "\n")
+		if len(buf) == 0 && window == size {
+			return nil, nil
+		}
+		i := bytes.LastIndexByte(buf, '\n')
+		if i < 0 && window < size {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(buf[i+1:], &e); err != nil || e.Hash == "" {
+			return nil, fmt.Errorf("the last entry of %s is damaged, run \"osintmaster audit verify\"", f.Name())
+		}
+		return &e, nil
+	}
+}
+
+// staleLock is how old a lock file must be before it is taken to be left over from a crash
+const staleLock = 30 * time.Second
+
+// lockFile takes the lock shared by every process writing the log and returns its release
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock audit log: %v", err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("audit log is locked by %s", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// operatorKey is the context key for the operator of a request
+type operatorKey struct{}
+
+// WithOperator returns a context whose entries are attributed to operator instead of the log's
+// The web server uses it to name the client a lookup came from
+func WithOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// operatorFrom returns the operator set on ctx, empty when there is none
+func operatorFrom(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
+
+// lookupKey is the context key for the lookup being audited
+type lookupKey struct{}
+
+// auditedLookup is the lookup requests made on a context belong to
+type auditedLookup struct {
+	log      *Log
+	operator string
+	module   string
+	target   string
+}
+
+// WithLookup returns a context whose outbound requests are recorded in l as part of a lookup
+func WithLookup(ctx context.Context, l *Log, module, target string) context.Context {
+	lookup := &auditedLookup{log: l, operator: operatorFrom(ctx), module: module, target: target}
+	ctx = context.WithValue(ctx, lookupKey{}, lookup)
+	return httpclient.ObserveRequests(ctx, func(r httpclient.Response) {
+		status := fmt.Sprint(r.Status)
+		if r.Error != "" {
+			status = "error"
+		}
+		lookup.log.record(Entry{
+			Operator: lookup.operator,
+			Event:    EventRequest,
+			Module:   lookup.module,
+			Target:   lookup.target,
+			Method:   r.Method,
+			Host:     r.Provider,
+			URL:      r.URL,
+			Status:   status,
+			Error:    r.Error,
+		})
+	})
+}
+
+// Connection records a connection made outside the HTTP client, such as a DNS query or TLS handshake
+// address is a host or URL, err is the outcome; nothing is recorded without a lookup on ctx
+func Connection(ctx context.Context, method, address string, err error) {
+	lookup, ok := ctx.Value(lookupKey{}).(*auditedLookup)
+	if !ok {
+		return
+	}
+
+	e := Entry{
+		Operator: lookup.operator,
+		Event:    EventRequest,
+		Module:   lookup.module,
+		Target:   lookup.target,
+		Method:   method,
+		Host:     address,
+		Status:   StatusOK,
+	}
+	if u, perr := url.Parse(address); perr == nil && u.Host != "" {
+		e.Host = u.Hostname()
+		e.URL = cache.RedactURL(address)
+	} else if host, _, serr := net.SplitHostPort(address); serr == nil {
+		e.Host = host
+	}
+	if err != nil {
+		e.Status, e.Error = "error", cache.RedactSecrets(err.Error())
+	}
+	lookup.log.record(e)
+}
+
+// TamperError describes the first entry that breaks the chain
+type TamperError struct {
+	Line   int
+	Seq    uint64
+	Reason string
+}
+
+// Error implements error
+func (e *TamperError) Error() string {
+	if e.Seq == 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+	}
+	return fmt.Sprintf("line %d (entry %d): %s", e.Line, e.Seq, e.Reason)
+}
+
+// Report summarises a verified log
+type Report struct {
+	Path    string    `json:"path"`
+	Entries int       `json:"entries"`
+	First   time.Time `json:"first,omitempty"`
+	Last    time.Time `json:"last,omitempty"`
+	Head    string    `json:"head,omitempty"` // hash of the last entry
+}
+
+// Verify recomputes the hash chain of the log in dir
+// It returns a *TamperError for the first entry that was changed, removed, inserted or reordered;
+// removing entries from the end is only caught by comparing Head with a copy kept elsewhere
+func Verify(dir string) (*Report, error) {
+	path := filepath.Join(dir, FileName)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	defer f.Close()
+
+	report := &Report{Path: path}
+	var prev *Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := scanner.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			return report, &TamperError{Line: line, Reason: "empty line"}
+		}
+
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return report, &TamperError{Line: line, Reason: "not a valid entry: " + err.Error()}
+		}
+		if err := check(e, prev); err != "" {
+			return report, &TamperError{Line: line, Seq: e.Seq, Reason: err}
+		}
+
+		if report.Entries == 0 {
+			report.First = e.Time
+		}
+		report.Entries++
+		report.Last = e.Time
+		report.Head = e.Hash
+		prev = &e
+	}
+	if err := scanner.Err(); err != nil {
+		return report, fmt.Errorf("failed to read audit log: %v", err)
+	}
+	return report, nil
+}
+
+// check verifies one entry against the one before it, returning why it does not fit
+func check(e Entry, prev *Entry) string {
+	sum, err := hash(e)
+	if err != nil {
+		return err.Error()
+	}
+	if sum != e.Hash {
+		return "hash does not match the entry, it was modified"
+	}
+
+	switch {
+	case prev == nil && (e.Seq != 1 || e.Prev != ""):
+		return fmt.Sprintf("the log starts at entry %d, earlier entries were removed", e.Seq)
+	case prev == nil:
+		return ""
+	case e.Seq != prev.Seq+1:
+		return fmt.Sprintf("expected entry %d, entries were removed or reordered", prev.Seq+1)
+	case e.Prev != prev.Hash:
+		return fmt.Sprintf("previous hash does not match entry %d", prev.Seq)
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/internal/httpclient"
+)
+
+// ipqsKey is a configured key that IPQualityScore takes in the URL path
+const ipqsKey = "Zq8pL2mN4xR7"
+
+// useSecretClient configures the shared client to redact ipqsKey until the test ends
+func useSecretClient(t *testing.T) {
+	t.Helper()
+
+	opts := httpclient.DefaultOptions()
+	opts.MaxRetries = 0
+	opts.RateLimit = 0
+	opts.CacheDir = ""
+	opts.Secrets = []string{ipqsKey}
+	if err := httpclient.Configure(opts); err != nil {
+		t.Fatalf("failed to configure the HTTP client: %v", err)
+	}
+	t.Cleanup(func() {
+		httpclient.Configure(httpclient.DefaultOptions())
+	})
+}
+
+func TestRequestsAreLoggedRedacted(t *testing.T) {
+	useSecretClient(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer server.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	dir := t.TempDir()
+	l, err := Open(dir, "tester", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := WithLookup(context.Background(), l, "phone", "+14155552671")
+
+	path := "/api/json/phone/" + ipqsKey + "/14155552671"
+	resp, err := httpclient.Get(ctx, server.URL+path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	// The error of a failed request quotes the URL
+	if _, err := httpclient.Get(ctx, closed.URL+path); err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	Connection(ctx, "TLS", server.URL+path, errors.New("handshake failed for "+ipqsKey))
+	l.Lookup(ctx, "phone", "+14155552671", false, errors.New("ipqualityscore key "+ipqsKey+" rejected"))
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if strings.Contains(string(data), ipqsKey) {
+		t.Errorf("audit log holds the API key:\n%s", data)
+	}
+	if n := strings.Count(string(data), "/api/json/phone/REDACTED/14155552671"); n < 3 {
+		t.Errorf("found %d redacted request URLs, want 3:\n%s", n, data)
+	}
+	if _, err := Verify(dir); err != nil {
+		t.Errorf("Verify: %v", err)
+	}
+}
+
+// writeLog appends n lookups to a new log and returns its directory and lines
+func writeLog(t *testing.T, n int) (string, []string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	l, err := Open(dir, "tester", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 1; i <= n; i++ {
+		e := Entry{Event: EventLookup, Module: "ip", Target: fmt.Sprintf("192.0.2.%d", i), Status: StatusOK}
+		if err := l.Append(e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	return dir, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+// rehashed returns line with its target changed and its own hash recomputed
+func rehashed(t *testing.T, line, target string) string {
+	t.Helper()
+
+	var e Entry
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		t.Fatalf("invalid entry: %v", err)
+	}
+	e.Target = target
+	var err error
+	if e.Hash, err = hash(e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := marshal(e)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(data)
+}
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(t *testing.T, lines []string) []string
+		wantLine   int
+		wantReason string
+		wantCount  int // entries verified before the first broken one
+	}{
+		{
+			name:      "intact",
+			tamper:    func(t *testing.T, lines []string) []string { return lines },
+			wantCount: 4,
+		},
+		{
+			name: "edited",
+			tamper: func(t *testing.T, lines []string) []string {
+				lines[1] = strings.Replace(lines[1], "192.0.2.2", "192.0.2.99", 1)
+				return lines
+			},
+			wantLine:   2,
+			wantReason: "it was modified",
+			wantCount:  1,
+		},
+		{
+			name: "edited and rehashed",
+			tamper: func(t *testing.T, lines []string) []string {
+				lines[1] = rehashed(t, lines[1], "192.0.2.99")
+				return lines
+			},
+			wantLine:   3,
+			wantReason: "previous hash does not match entry 2",
+			wantCount:  2,
+		},
+		{
+			name: "removed",
+			tamper: func(t *testing.T, lines []string) []string {
+				return append(lines[:1], lines[2:]...)
+			},
+			wantLine:   2,
+			wantReason: "expected entry 2",
+			wantCount:  1,
+		},
+		{
+			name: "first removed",
+			tamper: func(t *testing.T, lines []string) []string {
+				return lines[1:]
+			},
+			wantLine:   1,
+			wantReason: "the log starts at entry 2",
+		},
+		{
+			name: "reordered",
+			tamper: func(t *testing.T, lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			wantLine:   2,
+			wantReason: "expected entry 2",
+			wantCount:  1,
+		},
+		{
+			name: "duplicated",
+			tamper: func(t *testing.T, lines []string) []string {
+				return append(lines[:2], lines[1:]...)
+			},
+			wantLine:   3,
+			wantReason: "expected entry 3",
+			wantCount:  2,
+		},
+		{
+			name: "blank line",
+			tamper: func(t *testing.T, lines []string) []string {
+				return append(lines[:2], append([]string{""}, lines[2:]...)...)
+			},
+			wantLine:   3,
+			wantReason: "empty line",
+			wantCount:  2,
+		},
+		{
+			name: "truncated",
+			tamper: func(t *testing.T, lines []string) []string {
+				lines[3] = lines[3][:len(lines[3])/2]
+				return lines
+			},
+			wantLine:   4,
+			wantReason: "not a valid entry",
+			wantCount:  3,
+		},
+		{
+			// Only Head, compared with a copy kept elsewhere, shows the missing tail
+			name: "last removed",
+			tamper: func(t *testing.T, lines []string) []string {
+				return lines[:3]
+			},
+			wantCount: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, lines := writeLog(t, 4)
+			lines = tc.tamper(t, lines)
+			if err := os.WriteFile(filepath.Join(dir, FileName), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+				t.Fatalf("failed to write audit log: %v", err)
+			}
+
+			report, err := Verify(dir)
+			if tc.wantReason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else {
+				var tamper *TamperError
+				if !errors.As(err, &tamper) {
+					t.Fatalf("error = %v, want a *TamperError", err)
+				}
+				if tamper.Line != tc.wantLine || !strings.Contains(tamper.Reason, tc.wantReason) {
+					t.Errorf("error = %v, want line %d: %s", err, tc.wantLine, tc.wantReason)
+				}
+			}
+			if report.Entries != tc.wantCount {
+				t.Errorf("verified %d entries, want %d", report.Entries, tc.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+// Package cache stores provider responses on disk so repeated lookups do not spend API quota
+// Entries live under ~/.osintmaster/cache/<provider>/<key>.json, one file per response
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/malika/osint-master/config"
+)
+
+// Entry is a stored response
+type Entry struct {
+	Provider  string      `json:"provider"`
+	Method    string      `json:"method"`
+	URL       string      `json:"url"` // API keys are redacted
+	StoredAt  time.Time   `json:"stored_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	Status    int         `json:"status"`
+	Header    http.Header `json:"header,omitempty"`
+	Body      []byte      `json:"body"`
+}
+
+// Expired reports whether the entry is past its TTL
+func (e *Entry) Expired() bool {
+	return time.Now().After(e.ExpiresAt)
+}
+
+// Response rebuilds the stored response as the answer to req
+func (e *Entry) Response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// Stats summarises the cache contents
+type Stats struct {
+	Dir       string         `json:"dir"`
+	Entries   int            `json:"entries"`
+	Expired   int            `json:"expired"`
+	Bytes     int64          `json:"bytes"`
+	Providers map[string]int `json:"providers"`
+}
+
+// Store is a directory of cached responses
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns ~/.osintmaster/cache
+func DefaultDir() (string, error) {
+	configDir, err := config.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "cache"), nil
+}
+
+// Open returns the store in dir, creating the directory if needed
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the store directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Key identifies a request by provider and query
+func Key(method, rawURL string) string {
+	sum := sha256.Sum256([]byte(strings.ToUpper(method) + " " + rawURL))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the entry stored for provider and key, including expired ones
+func (s *Store) Get(provider, key string) (*Entry, bool) {
+	return readEntry(s.path(provider, key))
+}
+
+// Put stores an entry under key, replacing any previous one
+func (s *Store) Put(key string, entry *Entry) error {
+	entry.URL = RedactURL(entry.URL)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	path := s.path(entry.Provider, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so readers never see half an entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Stats walks the store and counts entries per provider
+func (s *Store) Stats() (*Stats, error) {
+	stats := &Stats{Dir: s.dir, Providers: make(map[string]int)}
+
+	err := s.walk(func(path string, info os.FileInfo) error {
+		stats.Entries++
+		stats.Bytes += info.Size()
+		stats.Providers[filepath.Base(filepath.Dir(path))]++
+
+		if entry, ok := readEntry(path); !ok || entry.Expired() {
+			stats.Expired++
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Purge removes entries, only expired or unreadable ones when expiredOnly is set
+// It returns the number of entries removed
+func (s *Store) Purge(expiredOnly bool) (int, error) {
+	removed := 0
+
+	err := s.walk(func(path string, info os.FileInfo) error {
+		if expiredOnly {
+			if entry, ok := readEntry(path); ok && !entry.Expired() {
+				return nil
+			}
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// walk calls fn for every entry file in the store
+func (s *Store) walk(fn func(path string, info os.FileInfo) error) error {
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		return fn(path, info)
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// path returns the file holding key for provider
+func (s *Store) path(provider, key string) string {
+	return filepath.Join(s.dir, safeName(provider), key+".json")
+}
+
+// readEntry decodes the entry stored at path
+func readEntry(path string) (*Entry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// unsafeChars matches characters not allowed in a provider directory name
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// safeName turns a provider name into a directory name
+func safeName(provider string) string {
+	name := unsafeChars.ReplaceAllString(strings.ToLower(provider), "_")
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+// secretParams are query parameters that carry API keys
+var secretParams = []string{"key", "token", "access_key", "apikey", "api_key", "secret"}
+
+// minSecretLen is the length below which a configured value is too likely to match ordinary URL text
+const minSecretLen = 6
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// SetSecrets replaces the configured values hidden by RedactURL and RedactSecrets wherever they appear,
+// which catches keys sent in a URL path such as IPQualityScore's
+func SetSecrets(values ...string) {
+	var kept []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) < minSecretLen || seen[v] {
+			continue
+		}
+		seen[v] = true
+		kept = append(kept, v)
+	}
+	// Longer values first, so a key containing another is hidden whole
+	sort.Slice(kept, func(i, j int) bool { return len(kept[i]) > len(kept[j]) })
+
+	secretsMu.Lock()
+	secrets = kept
+	secretsMu.Unlock()
+}
+
+// RedactSecrets hides every configured value in s, as written or URL-escaped
+func RedactSecrets(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+
+	for _, secret := range secrets {
+		for _, form := range []string{secret, url.PathEscape(secret), url.QueryEscape(secret)} {
+			s = strings.ReplaceAll(s, form, "REDACTED")
+		}
+	}
+	return s
+}
+
+// RedactURL hides API keys passed in the query string and any configured value elsewhere in the URL
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return RedactSecrets(rawURL)
+	}
+
+	query := u.Query()
+	changed := false
+	for name := range query {
+		for _, secret := range secretParams {
+			if strings.EqualFold(name, secret) {
+				query.Set(name, "REDACTED")
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return RedactSecrets(rawURL)
+	}
+
+	u.RawQuery = query.Encode()
+	return RedactSecrets(u.String())
+}
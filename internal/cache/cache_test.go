@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ipqsKey is a configured key that IPQualityScore takes in the URL path
+const ipqsKey = "Zq8pL2mN4xR7"
+
+func TestRedactURL(t *testing.T) {
+	SetSecrets(ipqsKey, "short", "p+k/ey==", "")
+	t.Cleanup(func() { SetSecrets() })
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"query parameter", "http://apilayer.net/api/validate?access_key=abc&number=1", "http://apilayer.net/api/validate?access_key=REDACTED&number=1"},
+		{"key in path", "https://ipqualityscore.com/api/json/phone/" + ipqsKey + "/14155552671", "https://ipqualityscore.com/api/json/phone/REDACTED/14155552671"},
+		{"key in path and query", "https://ipqualityscore.com/api/json/phone/" + ipqsKey + "/1?strictness=" + ipqsKey, "https://ipqualityscore.com/api/json/phone/REDACTED/1?strictness=REDACTED"},
+		{"escaped key", "https://example.com/v1/?auth=p%2Bk%2Fey%3D%3D", "https://example.com/v1/?auth=REDACTED"},
+		{"short values are kept", "https://example.com/short", "https://example.com/short"},
+		{"nothing to redact", "https://crt.sh/?q=%25.example.com&output=json", "https://crt.sh/?q=%25.example.com&output=json"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RedactURL(tc.url); got != tc.want {
+				t.Errorf("RedactURL(%q) = %q, want %q", tc.url, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRedactSecretsLongestFirst(t *testing.T) {
+	SetSecrets("abcdef", "abcdefghij")
+	t.Cleanup(func() { SetSecrets() })
+
+	if got := RedactSecrets("key=abcdefghij"); got != "key=REDACTED" {
+		t.Errorf("RedactSecrets = %q, want the longer key hidden whole", got)
+	}
+}
+
+func TestPutRedactsPathKey(t *testing.T) {
+	SetSecrets(ipqsKey)
+	t.Cleanup(func() { SetSecrets() })
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rawURL := "https://ipqualityscore.com/api/json/phone/" + ipqsKey + "/14155552671"
+	entry := &Entry{
+		Provider:  "ipqualityscore.com",
+		Method:    http.MethodGet,
+		URL:       rawURL,
+		StoredAt:  time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+		Status:    http.StatusOK,
+		Body:      []byte(`{"success": true}`),
+	}
+	key := Key(http.MethodGet, rawURL)
+	if err := store.Put(key, entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(store.Dir(), "ipqualityscore.com", key+".json"))
+	if err != nil {
+		t.Fatalf("failed to read entry: %v", err)
+	}
+	if strings.Contains(string(data), ipqsKey) {
+		t.Errorf("stored entry holds the API key: %s", data)
+	}
+	if got, ok := store.Get("ipqualityscore.com", key); !ok || got.Status != http.StatusOK {
+		t.Errorf("Get = %+v, %v, want the stored entry", got, ok)
+	}
+}
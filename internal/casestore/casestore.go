@@ -0,0 +1,377 @@
+// Package casestore keeps the lookups of an investigation case in an embedded database
+// Each case is a bbolt file under ~/.osintmaster/cases/<name>.db holding every result
+// with the raw provider responses it was built from
+package casestore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// fileExt is the extension of case database files
+const fileExt = ".db"
+
+// lockTimeout is how long Open waits for a case used by another process
+const lockTimeout = 2 * time.Second
+
+// Buckets of a case database
+var (
+	metaBucket      = []byte("meta")
+	recordsBucket   = []byte("records")
+	responsesBucket = []byte("responses")
+)
+
+// Meta keys
+var (
+	nameKey    = []byte("name")
+	createdKey = []byte("created")
+)
+
+// nameRegex matches valid case names, they are used as file names
+var nameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Record is one lookup kept in a case
+type Record struct {
+	ID        uint64                `json:"id"`
+	Module    string                `json:"module"`
+	Target    string                `json:"target"`
+	StoredAt  time.Time             `json:"stored_at"`
+	Findings  int                   `json:"findings"`
+	Failed    bool                  `json:"failed,omitempty"`
+	Result    *result.Result        `json:"result"`
+	Responses []httpclient.Response `json:"responses,omitempty"` // only filled by Case.Get
+}
+
+// Info describes a case
+type Info struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"` // time of the last lookup, zero when there is none
+	Lookups int       `json:"lookups"`
+	Bytes   int64     `json:"bytes"`
+}
+
+// Case is an open case database
+type Case struct {
+	name string
+	db   *bolt.DB
+}
+
+// DefaultDir returns ~/.osintmaster/cases
+func DefaultDir() (string, error) {
+	configDir, err := config.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "cases"), nil
+}
+
+// ValidateName checks that name can be used for a case
+func ValidateName(name string) error {
+	if !nameRegex.MatchString(name) {
+		return fmt.Errorf("invalid case name %q: use letters, digits, dots, dashes and underscores", name)
+	}
+	return nil
+}
+
+// Open opens the case called name in dir, creating it when it does not exist
+func Open(dir, name string) (*Case, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create case directory: %v", err)
+	}
+	return open(dir, name)
+}
+
+// OpenExisting opens the case called name in dir, failing when it does not exist
+func OpenExisting(dir, name string) (*Case, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(casePath(dir, name)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("case %s does not exist", name)
+		}
+		return nil, err
+	}
+	return open(dir, name)
+}
+
+// open opens or creates the database file of a case
+func open(dir, name string) (*Case, error) {
+	db, err := bolt.Open(casePath(dir, name), 0600, &bolt.Options{Timeout: lockTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("case %s is in use by another osintmaster process", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open case %s: %v", name, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if meta.Get(createdKey) == nil {
+			created, _ := time.Now().UTC().MarshalText()
+			if err := meta.Put(nameKey, []byte(name)); err != nil {
+				return err
+			}
+			if err := meta.Put(createdKey, created); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.CreateBucketIfNotExists(recordsBucket); err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(responsesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise case %s: %v", name, err)
+	}
+
+	return &Case{name: name, db: db}, nil
+}
+
+// casePath returns the database file of a case
+func casePath(dir, name string) string {
+	return filepath.Join(dir, name+fileExt)
+}
+
+// List returns the cases in dir sorted by name
+func List(dir string) ([]Info, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read case directory: %v", err)
+	}
+
+	var infos []Info
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), fileExt)
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), fileExt) || ValidateName(name) != nil {
+			continue
+		}
+
+		c, err := OpenExisting(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		info, err := c.Info()
+		c.Close()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Name returns the case name
+func (c *Case) Name() string {
+	return c.name
+}
+
+// Close closes the database
+func (c *Case) Close() error {
+	return c.db.Close()
+}
+
+// Info returns a summary of the case
+func (c *Case) Info() (Info, error) {
+	info := Info{Name: c.name}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		info.Created.UnmarshalText(tx.Bucket(metaBucket).Get(createdKey))
+		info.Bytes = tx.Size()
+
+		records := tx.Bucket(recordsBucket)
+		info.Lookups = records.Stats().KeyN
+		if _, last := records.Cursor().Last(); last != nil {
+			var r Record
+			if err := json.Unmarshal(last, &r); err != nil {
+				return err
+			}
+			info.Updated = r.StoredAt
+		}
+		return nil
+	})
+	if err != nil {
+		return info, fmt.Errorf("failed to read case %s: %v", c.name, err)
+	}
+	return info, nil
+}
+
+// Add stores a lookup result and the raw responses it was built from
+// It is safe for concurrent use
+func (c *Case) Add(res *result.Result, responses []httpclient.Response) (Record, error) {
+	record := Record{
+		Module:   res.Module,
+		Target:   res.Target,
+		StoredAt: time.Now().UTC(),
+		Findings: len(res.Findings),
+		Failed:   res.Failed(),
+		Result:   res,
+	}
+
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		records := tx.Bucket(recordsBucket)
+		id, err := records.NextSequence()
+		if err != nil {
+			return err
+		}
+		record.ID = id
+
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if err := records.Put(itob(id), data); err != nil {
+			return err
+		}
+
+		if len(responses) == 0 {
+			return nil
+		}
+		raw, err := json.Marshal(responses)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(responsesBucket).Put(itob(id), raw)
+	})
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to store %s lookup of %s in case %s: %v", res.Module, res.Target, c.name, err)
+	}
+	return record, nil
+}
+
+// Records returns every lookup in the order it was stored, without the raw responses
+func (c *Case) Records() ([]Record, error) {
+	var records []Record
+	err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(recordsBucket).ForEach(func(_, data []byte) error {
+			var r Record
+			if err := json.Unmarshal(data, &r); err != nil {
+				return err
+			}
+			records = append(records, r)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read case %s: %v", c.name, err)
+	}
+	return records, nil
+}
+
+// Find returns the lookups of target with module, or with any module when it is empty, oldest first
+func (c *Case) Find(module, target string) ([]Record, error) {
+	records, err := c.Records()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Record
+	for _, r := range records {
+		if (module == "" || r.Module == module) && strings.EqualFold(r.Target, target) {
+			found = append(found, r)
+		}
+	}
+	return found, nil
+}
+
+// Get returns one lookup with its raw responses
+func (c *Case) Get(id uint64) (Record, error) {
+	var r Record
+	err := c.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(recordsBucket).Get(itob(id))
+		if data == nil {
+			return fmt.Errorf("case %s has no lookup #%d", c.name, id)
+		}
+		if err := json.Unmarshal(data, &r); err != nil {
+			return err
+		}
+		if raw := tx.Bucket(responsesBucket).Get(itob(id)); raw != nil {
+			return json.Unmarshal(raw, &r.Responses)
+		}
+		return nil
+	})
+	return r, err
+}
+
+// Match is a lookup that matched a search, with the findings that matched
+type Match struct {
+	Record   Record
+	Findings []result.Finding // empty when only the target matched
+}
+
+// Search returns the lookups whose target or findings contain query, ignoring case
+func (c *Case) Search(query string) ([]Match, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+
+	records, err := c.Records()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, r := range records {
+		m := Match{Record: r}
+		for _, f := range r.Result.Findings {
+			if strings.Contains(strings.ToLower(f.Value), query) || strings.Contains(strings.ToLower(f.Field), query) {
+				m.Findings = append(m.Findings, f)
+			}
+		}
+		if len(m.Findings) > 0 || strings.Contains(strings.ToLower(r.Target), query) {
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
+
+// Export returns every lookup with its raw responses, for archiving or handing over a case
+func (c *Case) Export() ([]Record, error) {
+	records, err := c.Records()
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		full, err := c.Get(records[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		records[i] = full
+	}
+	return records, nil
+}
+
+// itob encodes a record ID as a key that sorts in insertion order
+func itob(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
@@ -0,0 +1,277 @@
+package casestore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// testKey is an API key configured for the redaction test
+const testKey = "k3y-5ecret-0123456789abcdef"
+
+// testCase opens a new case in a temporary directory
+func testCase(t *testing.T) (*Case, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	c, err := Open(dir, "acme-2026")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c, dir
+}
+
+// lookupResult builds a result with findings given as field, value pairs
+func lookupResult(module, target string, pairs ...string) *result.Result {
+	res := result.New(module, target)
+	for i := 0; i < len(pairs); i += 2 {
+		res.Add(pairs[i], pairs[i+1], "test", result.ConfidenceHigh, nil)
+	}
+	return res
+}
+
+// fillCase stores a few lookups and returns their records
+func fillCase(t *testing.T, c *Case) []Record {
+	t.Helper()
+
+	var records []Record
+	for _, res := range []*result.Result{
+		lookupResult(result.ModuleIP, "8.8.8.8", "city", "Mountain View", "isp", "Google LLC"),
+		lookupResult(result.ModuleDomain, "acme.com", "subdomain", "www.acme.com", "subdomain", "vpn.acme.com"),
+		lookupResult(result.ModuleIP, "8.8.8.8", "city", "Mountain View"),
+		result.Failure(result.ModuleEmail, "jane@acme.com", errors.New("emailrep.io returned status: 429")),
+	} {
+		r, err := c.Add(res, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+// ids returns the record IDs in order
+func ids(records []Record) []uint64 {
+	var out []uint64
+	for _, r := range records {
+		out = append(out, r.ID)
+	}
+	return out
+}
+
+func TestAdd(t *testing.T) {
+	c, _ := testCase(t)
+	added := fillCase(t, c)
+
+	if got, want := ids(added), []uint64{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ids = %v, want %v", got, want)
+	}
+	if added[0].Findings != 2 || added[0].Failed || !added[3].Failed {
+		t.Errorf("records = %+v, want 2 findings on the first and the last failed", added)
+	}
+
+	records, err := c.Records()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 4 || records[1].Target != "acme.com" || len(records[1].Result.Findings) != 2 {
+		t.Errorf("records = %+v, want the stored lookups in order", records)
+	}
+
+	info, err := c.Info()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Name != "acme-2026" || info.Lookups != 4 || !info.Updated.Equal(added[3].StoredAt) || info.Created.IsZero() {
+		t.Errorf("info = %+v, want 4 lookups updated at %v", info, added[3].StoredAt)
+	}
+}
+
+func TestFind(t *testing.T) {
+	c, _ := testCase(t)
+	fillCase(t, c)
+
+	tests := []struct {
+		module string
+		target string
+		want   []uint64
+	}{
+		{result.ModuleIP, "8.8.8.8", []uint64{1, 3}},
+		{"", "ACME.com", []uint64{2}},
+		{result.ModuleEmail, "jane@acme.com", []uint64{4}},
+		{result.ModuleDomain, "8.8.8.8", nil},
+		{"", "example.com", nil},
+	}
+
+	for _, tc := range tests {
+		found, err := c.Find(tc.module, tc.target)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := ids(found); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Find(%q, %q) = %v, want %v", tc.module, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	c, _ := testCase(t)
+	fillCase(t, c)
+
+	tests := []struct {
+		query        string
+		want         []uint64
+		wantFindings []int // matching findings of each match
+	}{
+		{"mountain", []uint64{1, 3}, []int{1, 1}},
+		{" VPN ", []uint64{2}, []int{1}},
+		{"subdomain", []uint64{2}, []int{2}},
+		{"acme.com", []uint64{2, 4}, []int{2, 0}}, // the email only matches on its target
+		{"nothing here", nil, nil},
+	}
+
+	for _, tc := range tests {
+		matches, err := c.Search(tc.query)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var got []uint64
+		var findings []int
+		for _, m := range matches {
+			got = append(got, m.Record.ID)
+			findings = append(findings, len(m.Findings))
+		}
+		if !reflect.DeepEqual(got, tc.want) || !reflect.DeepEqual(findings, tc.wantFindings) {
+			t.Errorf("Search(%q) = %v with %v findings, want %v with %v", tc.query, got, findings, tc.want, tc.wantFindings)
+		}
+	}
+
+	if _, err := c.Search("  "); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+}
+
+func TestExport(t *testing.T) {
+	c, _ := testCase(t)
+	res := lookupResult(result.ModuleIP, "8.8.8.8", "city", "Mountain View")
+	responses := []httpclient.Response{{Provider: "ip-api.com", Method: "GET", URL: "https://ip-api.com/json/8.8.8.8", Status: 200, Body: []byte(`{"city":"Mountain View"}`)}}
+	if _, err := c.Add(res, responses); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Add(lookupResult(result.ModuleDomain, "acme.com"), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	records, err := c.Export()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("exported %d records, want 2", len(records))
+	}
+	if got := records[0].Responses; len(got) != 1 || got[0].URL != responses[0].URL || string(got[0].Body) != string(responses[0].Body) {
+		t.Errorf("responses = %+v, want the stored response", got)
+	}
+	if records[1].Responses != nil {
+		t.Errorf("responses = %+v, want none for a lookup stored without any", records[1].Responses)
+	}
+
+	// Records leaves the raw responses out
+	listed, _ := c.Records()
+	if listed[0].Responses != nil {
+		t.Errorf("Records returned responses: %+v", listed[0].Responses)
+	}
+	if _, err := c.Get(99); err == nil {
+		t.Error("expected an error for a missing lookup")
+	}
+}
+
+func TestExportRedactsConfiguredKey(t *testing.T) {
+	// The provider echoes the key back in its body and a header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Echo-Key", r.URL.Query().Get("key"))
+		w.Write([]byte(`{"error": "key ` + r.URL.Query().Get("key") + ` is over quota"}`))
+	}))
+	defer server.Close()
+
+	opts := httpclient.DefaultOptions()
+	opts.MaxRetries = 0
+	opts.RateLimit = 0
+	opts.Secrets = []string{testKey}
+	if err := httpclient.Configure(opts); err != nil {
+		t.Fatalf("failed to configure the HTTP client: %v", err)
+	}
+	t.Cleanup(func() { httpclient.Configure(httpclient.DefaultOptions()) })
+
+	ctx, log := httpclient.CaptureResponses(context.Background())
+	resp, err := httpclient.Get(ctx, server.URL+"/json/8.8.8.8?key="+testKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	c, dir := testCase(t)
+	if _, err := c.Add(lookupResult(result.ModuleIP, "8.8.8.8", "city", "Mountain View"), log.Responses()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Close()
+
+	// Read back from disk, as a handed over case would be
+	reopened, err := OpenExisting(dir, "acme-2026")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer reopened.Close()
+	records, err := reopened.Export()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(records)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), testKey) {
+		t.Errorf("exported case holds the API key: %s", data)
+	}
+	if len(records) != 1 || len(records[0].Responses) != 1 || !strings.Contains(string(data), "REDACTED") {
+		t.Errorf("records = %s, want one response with the key redacted", data)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Open(dir, "../escape"); err == nil || !strings.Contains(err.Error(), "invalid case name") {
+		t.Errorf("error = %v, want an invalid case name", err)
+	}
+	if _, err := OpenExisting(dir, "missing"); err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("error = %v, want a missing case", err)
+	}
+
+	for _, name := range []string{"zeta", "alpha"} {
+		c, err := Open(dir, name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c.Close()
+	}
+	infos, err := List(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(infos) != 2 || infos[0].Name != "alpha" || infos[1].Name != "zeta" {
+		t.Errorf("cases = %+v, want alpha and zeta", infos)
+	}
+}
@@ -0,0 +1,112 @@
+// Package countries provides ISO 3166 country data embedded in the binary:
+// alpha-2, alpha-3 and numeric codes, names, calling codes, currencies and timezones
+package countries
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"sync"
+)
+
+//go:embed countries.json
+var countriesJSON []byte
+
+// Country holds the reference data for one country or territory
+type Country struct {
+	Alpha2       string   `json:"alpha2"`
+	Alpha3       string   `json:"alpha3"`
+	Numeric      string   `json:"numeric"`
+	Name         string   `json:"name"`
+	OfficialName string   `json:"official_name"`
+	CallingCodes []string `json:"calling_codes"`
+	Currencies   []string `json:"currencies"`
+	Timezones    []string `json:"timezones"`
+}
+
+// primaryCallingCodes picks the country reported for calling codes shared by several countries
+var primaryCallingCodes = map[string]string{
+	"1": "US", "7": "RU", "39": "IT", "44": "GB", "47": "NO", "61": "AU", "64": "NZ",
+	"212": "MA", "262": "RE", "290": "SH", "358": "FI", "500": "FK", "590": "GP",
+	"599": "CW", "672": "NF",
+}
+
+var (
+	loadOnce      sync.Once
+	all           []Country
+	byCode        map[string]*Country
+	byCallingCode map[string]*Country
+)
+
+// load parses the embedded dataset and builds the indexes
+func load() {
+	if err := json.Unmarshal(countriesJSON, &all); err != nil {
+		panic("countries: invalid embedded dataset: " + err.Error())
+	}
+
+	byCode = make(map[string]*Country, len(all)*3)
+	byCallingCode = make(map[string]*Country)
+
+	for i := range all {
+		c := &all[i]
+		byCode[c.Alpha2] = c
+		byCode[c.Alpha3] = c
+		if c.Numeric != "" {
+			byCode[c.Numeric] = c
+		}
+
+		for _, code := range c.CallingCodes {
+			if _, exists := byCallingCode[code]; !exists || primaryCallingCodes[code] == c.Alpha2 {
+				byCallingCode[code] = c
+			}
+		}
+	}
+}
+
+// All returns every country in alpha-2 order
+func All() []Country {
+	loadOnce.Do(load)
+	return append([]Country(nil), all...)
+}
+
+// Lookup finds a country by alpha-2, alpha-3 or numeric code, case-insensitively
+func Lookup(code string) (Country, bool) {
+	loadOnce.Do(load)
+	c, ok := byCode[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return Country{}, false
+	}
+	return *c, true
+}
+
+// Name returns the common name for an ISO code, or the code itself when unknown
+func Name(code string) string {
+	if c, ok := Lookup(code); ok {
+		return c.Name
+	}
+	return code
+}
+
+// ByCallingCode finds the country for an international calling code such as "254" or "+254"
+func ByCallingCode(code string) (Country, bool) {
+	loadOnce.Do(load)
+	c, ok := byCallingCode[strings.TrimPrefix(strings.TrimSpace(code), "+")]
+	if !ok {
+		return Country{}, false
+	}
+	return *c, true
+}
+
+// MatchCallingCode finds the calling code at the start of an E.164 number
+// Calling codes are prefix-free, so at most one of the 1-3 digit prefixes matches
+func MatchCallingCode(number string) (string, Country, bool) {
+	digits := strings.TrimPrefix(strings.TrimSpace(number), "+")
+
+	for n := 1; n <= 3 && n <= len(digits); n++ {
+		if c, ok := ByCallingCode(digits[:n]); ok {
+			return digits[:n], c, true
+		}
+	}
+
+	return "", Country{}, false
+}
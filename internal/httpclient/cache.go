@@ -0,0 +1,179 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/malika/osint-master/internal/cache"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// maxCachedBody is the largest response body written to the cache
+const maxCachedBody = 5 << 20
+
+// CacheMode controls how a request uses the cache
+type CacheMode int
+
+const (
+	CacheDefault CacheMode = iota // serve fresh entries, store new responses
+	CacheRefresh                  // always fetch, store the new response
+	CacheBypass                   // neither read nor write the cache
+)
+
+// cacheModeKey is the context key for the cache mode
+type cacheModeKey struct{}
+
+// WithCacheMode returns a context whose requests use the cache in mode
+func WithCacheMode(ctx context.Context, mode CacheMode) context.Context {
+	return context.WithValue(ctx, cacheModeKey{}, mode)
+}
+
+// cacheModeFrom returns the cache mode set on ctx
+func cacheModeFrom(ctx context.Context) CacheMode {
+	mode, _ := ctx.Value(cacheModeKey{}).(CacheMode)
+	return mode
+}
+
+// CacheLog collects the responses served from the cache during a lookup
+type CacheLog struct {
+	mu        sync.Mutex
+	seen      map[string]bool
+	responses []result.CachedResponse
+}
+
+// cacheLogKey is the context key for the cache log
+type cacheLogKey struct{}
+
+// TrackCache returns a context that records cache hits in the returned log
+func TrackCache(ctx context.Context) (context.Context, *CacheLog) {
+	log := &CacheLog{seen: make(map[string]bool)}
+	return context.WithValue(ctx, cacheLogKey{}, log), log
+}
+
+// Responses returns the cache hits recorded so far
+func (l *CacheLog) Responses() []result.CachedResponse {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]result.CachedResponse(nil), l.responses...)
+}
+
+// add records a hit once per URL
+func (l *CacheLog) add(entry *cache.Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.seen[entry.URL] {
+		return
+	}
+	l.seen[entry.URL] = true
+	l.responses = append(l.responses, result.CachedResponse{
+		Provider:  entry.Provider,
+		URL:       entry.URL,
+		StoredAt:  entry.StoredAt,
+		ExpiresAt: entry.ExpiresAt,
+	})
+}
+
+// cacheTransport answers GET requests from the store while entries are fresh
+type cacheTransport struct {
+	next  http.RoundTripper
+	store *cache.Store
+	ttl   time.Duration
+	hosts map[string]time.Duration
+}
+
+// newCacheTransport wraps next, returning it unchanged when there is no store
+func newCacheTransport(next http.RoundTripper, store *cache.Store, ttl time.Duration, hosts map[string]time.Duration) http.RoundTripper {
+	if store == nil || ttl <= 0 {
+		return next
+	}
+	return &cacheTransport{next: next, store: store, ttl: ttl, hosts: hosts}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	mode := cacheModeFrom(req.Context())
+	provider := strings.ToLower(req.URL.Hostname())
+	ttl := t.ttlFor(provider)
+	if req.Method != http.MethodGet || mode == CacheBypass || ttl <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	key := cache.Key(req.Method, req.URL.String())
+	if mode == CacheDefault {
+		if entry, ok := t.store.Get(provider, key); ok && !entry.Expired() {
+			if log, ok := req.Context().Value(cacheLogKey{}).(*CacheLog); ok {
+				log.add(entry)
+			}
+			return entry.Response(req), nil
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !cacheable(resp.StatusCode) {
+		return resp, err
+	}
+
+	// Read the body once, keeping it for the caller whether or not it fits the cache
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBody {
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Cookies belong to the session that received them
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	now := time.Now().UTC()
+	// A cache that cannot be written only costs the next lookup a request
+	_ = t.store.Put(key, &cache.Entry{
+		Provider:  provider,
+		Method:    req.Method,
+		URL:       req.URL.String(),
+		StoredAt:  now,
+		ExpiresAt: now.Add(ttl),
+		Status:    resp.StatusCode,
+		Header:    header,
+		Body:      body,
+	})
+	return resp, nil
+}
+
+// ttlFor returns the TTL for host, matching overrides on the host or any parent domain
+func (t *cacheTransport) ttlFor(host string) time.Duration {
+	for h := host; h != ""; {
+		if ttl, ok := t.hosts[h]; ok {
+			return ttl
+		}
+		i := strings.Index(h, ".")
+		if i < 0 {
+			break
+		}
+		h = h[i+1:]
+	}
+	return t.ttl
+}
+
+// cacheable reports whether a response status is worth keeping
+// 404 is kept because providers use it for "not found" answers
+func cacheable(status int) bool {
+	return status == http.StatusOK || status == http.StatusNotFound
+}
+
+// readCloser pairs a reader with the closer of the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
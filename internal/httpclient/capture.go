@@ -0,0 +1,119 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/malika/osint-master/internal/cache"
+)
+
+// maxCapturedBody is the largest response body kept by a capture, longer bodies are truncated
+const maxCapturedBody = 1 << 20
+
+// Response is a raw provider response recorded by CaptureResponses
+type Response struct {
+	Provider  string      `json:"provider"`
+	Method    string      `json:"method"`
+	URL       string      `json:"url"` // API keys are redacted
+	Status    int         `json:"status"`
+	FetchedAt time.Time   `json:"fetched_at"`
+	Header    http.Header `json:"header,omitempty"`
+	Body      []byte      `json:"body,omitempty"`
+	Truncated bool        `json:"truncated,omitempty"`
+	Error     string      `json:"error,omitempty"` // set when no response was received
+}
+
+// ResponseLog collects the raw responses of the requests made during a lookup
+type ResponseLog struct {
+	mu        sync.Mutex
+	responses []Response
+}
+
+// responseLogKey is the context key for the response log
+type responseLogKey struct{}
+
+// CaptureResponses returns a context whose requests are recorded in the returned log
+func CaptureResponses(ctx context.Context) (context.Context, *ResponseLog) {
+	log := &ResponseLog{}
+	return context.WithValue(ctx, responseLogKey{}, log), log
+}
+
+// Responses returns the responses recorded so far, in the order they completed
+func (l *ResponseLog) Responses() []Response {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Response(nil), l.responses...)
+}
+
+// add records one response
+func (l *ResponseLog) add(r Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.responses = append(l.responses, r)
+}
+
+// captureTransport records responses for requests whose context carries a ResponseLog
+// It sits outside the cache so answers served from the cache are recorded too
+type captureTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log, ok := req.Context().Value(responseLogKey{}).(*ResponseLog)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	record := Response{
+		Provider:  strings.ToLower(req.URL.Hostname()),
+		Method:    req.Method,
+		URL:       cache.RedactURL(req.URL.String()),
+		FetchedAt: time.Now().UTC(),
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		record.Error = cache.RedactSecrets(err.Error())
+		log.add(record)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCapturedBody+1))
+	if err != nil {
+		resp.Body.Close()
+		record.Error = cache.RedactSecrets(err.Error())
+		log.add(record)
+		return nil, err
+	}
+	if len(body) > maxCapturedBody {
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		body, record.Truncated = body[:maxCapturedBody], true
+	} else {
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	record.Status = resp.StatusCode
+	record.Header = redactHeader(resp.Header)
+	record.Body = []byte(cache.RedactSecrets(string(body)))
+	log.add(record)
+	return resp, nil
+}
+
+// redactHeader returns a copy of h without cookies and with configured API keys redacted
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	h.Del("Set-Cookie")
+	for _, values := range h {
+		for i, v := range values {
+			values[i] = cache.RedactSecrets(v)
+		}
+	}
+	return h
+}
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCaptureRedactsPathKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Url", r.URL.Path)
+		w.Write([]byte(`{"success": false, "message": "key ` + ipqsKey + ` is over quota"}`))
+	}))
+	defer server.Close()
+	useFixtures(t, "", "", ipqsKey)
+
+	ctx, log := CaptureResponses(context.Background())
+	resp, err := Get(ctx, server.URL+"/api/json/phone/"+ipqsKey+"/14155552671")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), ipqsKey) {
+		t.Errorf("body = %s, the lookup must still see the response as sent", body)
+	}
+
+	responses := log.Responses()
+	if len(responses) != 1 {
+		t.Fatalf("captured %d responses, want 1", len(responses))
+	}
+	r := responses[0]
+	if !strings.HasSuffix(r.URL, "/api/json/phone/REDACTED/14155552671") {
+		t.Errorf("url = %q, want the key redacted", r.URL)
+	}
+	if strings.Contains(string(r.Body), ipqsKey) || strings.Contains(r.Header.Get("X-Request-Url"), ipqsKey) {
+		t.Errorf("captured response holds the API key: %s %v", r.Body, r.Header)
+	}
+}
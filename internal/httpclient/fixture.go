@@ -0,0 +1,258 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/malika/osint-master/internal/cache"
+)
+
+// Exchange is one recorded request and its response, kept as a JSON file in a fixture directory
+// Request headers are not kept and configured API keys are redacted wherever they appear, so fixtures can be shared
+type Exchange struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`                   // API keys are redacted
+	BodySHA256 string      `json:"body_sha256,omitempty"` // of the request body, when there is one
+	Status     int         `json:"status,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	Base64     bool        `json:"base64,omitempty"` // Body is base64, the response was not UTF-8 text
+	Error      string      `json:"error,omitempty"`  // set when no response was received
+	RecordedAt time.Time   `json:"recorded_at"`
+}
+
+// key identifies the request an exchange answers
+func (e *Exchange) key() string {
+	return e.Method + " " + e.URL + " " + e.BodySHA256
+}
+
+// requestKey returns the key of req, reading and restoring its body
+func requestKey(req *http.Request) (string, string, error) {
+	sum := ""
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", "", err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if len(body) > 0 {
+			h := sha256.Sum256(body)
+			sum = hex.EncodeToString(h[:])
+		}
+	}
+	e := Exchange{Method: req.Method, URL: cache.RedactURL(req.URL.String()), BodySHA256: sum}
+	return e.key(), sum, nil
+}
+
+// fixtureName matches the files written by a recording
+var fixtureName = regexp.MustCompile(`^(\d+)-.*\.json$`)
+
+// unsafeFileChars are replaced in the host part of a fixture file name
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// recordTransport saves every exchange that goes through it to a fixture directory
+// It sits under the retries, so each attempt is kept in the order it was made
+type recordTransport struct {
+	next http.RoundTripper
+	dir  string
+
+	mu  sync.Mutex
+	seq int
+}
+
+// newRecordTransport records the exchanges of next in dir, after any fixtures already there
+func newRecordTransport(next http.RoundTripper, dir string) (http.RoundTripper, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %v", err)
+	}
+	names, err := fixtureFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &recordTransport{next: next, dir: dir}
+	if len(names) > 0 {
+		fmt.Sscanf(names[len(names)-1], "%d-", &t.seq)
+	}
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	_, sum, err := requestKey(req)
+	if err != nil {
+		return nil, err
+	}
+	exchange := Exchange{
+		Method:     req.Method,
+		URL:        cache.RedactURL(req.URL.String()),
+		BodySHA256: sum,
+		RecordedAt: time.Now().UTC(),
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		exchange.Error = cache.RedactSecrets(err.Error())
+		t.save(req.URL.Hostname(), exchange)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	exchange.Status = resp.StatusCode
+	exchange.Header = redactHeader(resp.Header)
+	if utf8.Valid(body) {
+		// Providers may echo the key back, e.g. in an error message
+		exchange.Body = cache.RedactSecrets(string(body))
+	} else {
+		exchange.Body, exchange.Base64 = base64.StdEncoding.EncodeToString(body), true
+	}
+	t.save(req.URL.Hostname(), exchange)
+	return resp, nil
+}
+
+// save writes an exchange to the next numbered file, a failed write is reported and never stops a lookup
+func (t *recordTransport) save(host string, e Exchange) {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record %s: %v\n", e.URL, err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	name := fmt.Sprintf("%04d-%s.json", t.seq, unsafeFileChars.ReplaceAllString(strings.ToLower(host), "_"))
+	if err := os.WriteFile(filepath.Join(t.dir, name), append(data, '\n'), 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record %s: %v\n", e.URL, err)
+	}
+}
+
+// replayTransport answers requests from a fixture directory and never uses the network
+// Exchanges of the same request are served in the order they were recorded, the last one repeats
+type replayTransport struct {
+	dir string
+
+	mu        sync.Mutex
+	exchanges map[string][]*Exchange
+	served    map[string]int
+}
+
+// newReplayTransport loads the fixtures of dir
+func newReplayTransport(dir string) (http.RoundTripper, error) {
+	names, err := fixtureFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no recorded exchanges in %s", dir)
+	}
+
+	t := &replayTransport{dir: dir, exchanges: make(map[string][]*Exchange), served: make(map[string]int)}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %v", err)
+		}
+		var e Exchange
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %v", name, err)
+		}
+		t.exchanges[e.key()] = append(t.exchanges[e.key()], &e)
+	}
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	key, _, err := requestKey(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	recorded := t.exchanges[key]
+	n := t.served[key]
+	t.served[key]++
+	t.mu.Unlock()
+
+	if len(recorded) == 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s in %s", req.Method, cache.RedactURL(req.URL.String()), t.dir)
+	}
+	if n >= len(recorded) {
+		n = len(recorded) - 1
+	}
+	e := recorded[n]
+
+	if e.Error != "" {
+		return nil, errors.New(e.Error)
+	}
+	body := []byte(e.Body)
+	if e.Base64 {
+		if body, err = base64.StdEncoding.DecodeString(e.Body); err != nil {
+			return nil, fmt.Errorf("invalid recorded body for %s: %v", e.URL, err)
+		}
+	}
+
+	header := e.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// fixtureFiles returns the fixture file names of dir in recording order
+func fixtureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture directory: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && fixtureName.MatchString(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		var a, b int
+		fmt.Sscanf(names[i], "%d-", &a)
+		fmt.Sscanf(names[j], "%d-", &b)
+		return a < b
+	})
+	return names, nil
+}
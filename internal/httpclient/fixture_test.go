@@ -0,0 +1,119 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// ipqsKey is a configured key that IPQualityScore takes in the URL path
+const ipqsKey = "Zq8pL2mN4xR7"
+
+// useFixtures configures the shared client to record to or replay from a directory until the test ends
+func useFixtures(t *testing.T, record, replay string, secrets ...string) {
+	t.Helper()
+
+	opts := DefaultOptions()
+	opts.MaxRetries = 0
+	opts.RateLimit = 0
+	opts.RecordDir = record
+	opts.ReplayDir = replay
+	opts.Secrets = secrets
+	if err := Configure(opts); err != nil {
+		t.Fatalf("failed to configure the HTTP client: %v", err)
+	}
+	t.Cleanup(func() {
+		Configure(DefaultOptions())
+	})
+}
+
+// fetch returns the status and body of a GET with the shared client
+func fetch(t *testing.T, rawURL string) (int, string) {
+	t.Helper()
+
+	resp, err := Get(context.Background(), rawURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestRecordRedactsPathKeyAndReplays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success": true, "carrier": "AT&T"}`))
+	}))
+	defer server.Close()
+	rawURL := server.URL + "/api/json/phone/" + ipqsKey + "/14155552671"
+	dir := t.TempDir()
+
+	useFixtures(t, dir, "", ipqsKey)
+	_, recorded := fetch(t, rawURL)
+
+	names, err := fixtureFiles(dir)
+	if err != nil || len(names) != 1 {
+		t.Fatalf("fixtures = %v, %v, want one", names, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, names[0]))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if strings.Contains(string(data), ipqsKey) {
+		t.Fatalf("fixture holds the API key:\n%s", data)
+	}
+	if !strings.Contains(string(data), "/api/json/phone/REDACTED/14155552671") {
+		t.Errorf("fixture URL is not redacted:\n%s", data)
+	}
+	server.Close()
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"same key", ipqsKey},
+		// Fixtures shared with another operator replay under their own key
+		{"other key", "Other9Key42x"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			useFixtures(t, "", dir, tc.key)
+
+			status, body := fetch(t, strings.Replace(rawURL, ipqsKey, tc.key, 1))
+			if status != http.StatusOK || body != recorded {
+				t.Errorf("replayed %d %q, want 200 %q", status, body, recorded)
+			}
+		})
+	}
+}
+
+func TestReplayMissErrorIsRedacted(t *testing.T) {
+	dir := t.TempDir()
+	exchange := `{"method": "GET", "url": "https://ipqualityscore.com/api/json/phone/REDACTED/1", "status": 200, "body": "{}"}`
+	if err := os.WriteFile(filepath.Join(dir, "0001-ipqualityscore.com.json"), []byte(exchange), 0600); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	useFixtures(t, "", dir, ipqsKey)
+
+	_, err := Get(context.Background(), "https://ipqualityscore.com/api/json/phone/"+ipqsKey+"/2")
+	if err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Fatalf("error = %v, want a missing fixture", err)
+	}
+	// net/http quotes the URL it was given, the message of the replay must not add the key again
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || strings.Contains(urlErr.Err.Error(), ipqsKey) {
+		t.Errorf("error holds the API key: %v", err)
+	}
+}
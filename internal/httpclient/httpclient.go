@@ -0,0 +1,257 @@
+// Package httpclient is the HTTP layer shared by every lookup module
+// It applies one timeout, User-Agent, proxy, retry, per-host rate limit and cache policy
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/cache"
+)
+
+// DefaultUserAgent is sent when no User-Agent is configured
+const DefaultUserAgent = "OSINT-Master/1.0 (educational OSINT tool)"
+
+// Options controls the behaviour of the shared client
+type Options struct {
+	Timeout        time.Duration      // whole request including retries
+	MaxRetries     int                // retries after the first attempt
+	RetryBaseDelay time.Duration      // first backoff delay, doubled on every retry
+	RetryMaxDelay  time.Duration      // longest backoff or Retry-After we wait for
+	RateLimit      float64            // requests per second per host, 0 disables
+	RateBurst      int                // requests allowed at once before throttling
+	HostRateLimits map[string]float64 // per-host overrides of RateLimit
+	Proxy          string             // proxy URL, empty uses HTTP_PROXY/HTTPS_PROXY
+	UserAgent      string             // sent on every request without its own User-Agent
+
+	CacheDir  string                   // response cache directory, empty disables the cache
+	CacheTTL  time.Duration            // how long responses are reused, 0 disables the cache
+	CacheTTLs map[string]time.Duration // per-host overrides of CacheTTL, 0 disables for that host
+
+	RecordDir string // saves every exchange as a fixture, the cache is not used
+	ReplayDir string // answers every request from recorded fixtures without the network or the cache
+
+	Secrets []string // configured API keys, redacted from every URL the client stores or reports
+}
+
+// DefaultOptions returns the options used when nothing is configured
+func DefaultOptions() Options {
+	return Options{
+		Timeout:        15 * time.Second,
+		MaxRetries:     2,
+		RetryBaseDelay: 500 * time.Millisecond,
+		RetryMaxDelay:  10 * time.Second,
+		RateLimit:      2,
+		RateBurst:      4,
+		HostRateLimits: map[string]float64{
+			"ip-api.com": 0.75, // 45 requests per minute
+		},
+		UserAgent: DefaultUserAgent,
+		CacheTTL:  24 * time.Hour,
+		CacheTTLs: map[string]time.Duration{
+			"apilayer.net":       30 * 24 * time.Hour, // Numverify, 100 requests a month
+			"veriphone.io":       7 * 24 * time.Hour,
+			"abstractapi.com":    7 * 24 * time.Hour,
+			"ipqualityscore.com": 7 * 24 * time.Hour,
+		},
+	}
+}
+
+// OptionsFromConfig applies the HTTP settings of cfg on top of DefaultOptions
+func OptionsFromConfig(cfg *config.Config) Options {
+	opts := DefaultOptions()
+	if cfg == nil {
+		return opts
+	}
+
+	if cfg.HTTPTimeout > 0 {
+		opts.Timeout = cfg.HTTPTimeout
+	}
+	// 0 keeps the default, a negative value turns the feature off
+	if cfg.HTTPRetries > 0 {
+		opts.MaxRetries = cfg.HTTPRetries
+	} else if cfg.HTTPRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if cfg.RateLimit > 0 {
+		opts.RateLimit = cfg.RateLimit
+	} else if cfg.RateLimit < 0 {
+		opts.RateLimit = 0
+	}
+	if cfg.RateBurst > 0 {
+		opts.RateBurst = cfg.RateBurst
+	}
+	for host, rate := range cfg.HostRateLimits {
+		opts.HostRateLimits[host] = rate
+	}
+	if cfg.Proxy != "" {
+		opts.Proxy = cfg.Proxy
+	}
+	if cfg.UserAgent != "" {
+		opts.UserAgent = cfg.UserAgent
+	}
+
+	if !cfg.CacheDisabled {
+		opts.CacheDir = cfg.CacheDir
+		if opts.CacheDir == "" {
+			opts.CacheDir, _ = cache.DefaultDir()
+		}
+	}
+	if cfg.CacheTTL > 0 {
+		opts.CacheTTL = cfg.CacheTTL
+	}
+	for host, ttl := range cfg.CacheTTLs {
+		opts.CacheTTLs[host] = ttl
+	}
+
+	opts.RecordDir = cfg.RecordDir
+	opts.ReplayDir = cfg.ReplayDir
+	opts.Secrets = cfg.Secrets()
+
+	return opts
+}
+
+// New builds a client from opts
+// Requests pass through the capture, cache, observer, User-Agent, retry and rate limit layers in that order
+// A recording saves what reaches the network; a replay stands in for the network and the rate limits,
+// and leaves out the cache and the observer since nothing leaves the process
+func New(opts Options) (*http.Client, error) {
+	if opts.RecordDir != "" && opts.ReplayDir != "" {
+		return nil, fmt.Errorf("cannot record and replay at the same time")
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %v", err)
+		}
+		base.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	var transport http.RoundTripper = base
+	var err error
+	switch {
+	case opts.ReplayDir != "":
+		if transport, err = newReplayTransport(opts.ReplayDir); err != nil {
+			return nil, err
+		}
+	case opts.RecordDir != "":
+		if transport, err = newRecordTransport(transport, opts.RecordDir); err != nil {
+			return nil, err
+		}
+		fallthrough
+	default:
+		transport = newRateLimiter(transport, opts.RateLimit, opts.RateBurst, opts.HostRateLimits)
+	}
+	transport = &retryTransport{
+		next:       transport,
+		maxRetries: opts.MaxRetries,
+		baseDelay:  opts.RetryBaseDelay,
+		maxDelay:   opts.RetryMaxDelay,
+	}
+	transport = &userAgentTransport{next: transport, userAgent: opts.UserAgent}
+	if opts.ReplayDir == "" {
+		transport = &observeTransport{next: transport}
+	}
+
+	if opts.CacheDir != "" && opts.CacheTTL > 0 && opts.RecordDir == "" && opts.ReplayDir == "" {
+		store, err := cache.Open(opts.CacheDir)
+		if err != nil {
+			return nil, err
+		}
+		transport = newCacheTransport(transport, store, opts.CacheTTL, opts.CacheTTLs)
+	}
+	transport = &captureTransport{next: transport}
+
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+	}, nil
+}
+
+var (
+	mu            sync.RWMutex
+	defaultClient = mustNew(DefaultOptions())
+)
+
+// mustNew builds a client from options that are known to be valid
+func mustNew(opts Options) *http.Client {
+	client, err := New(opts)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// Configure replaces the shared client and the secrets redacted from stored URLs
+func Configure(opts Options) error {
+	client, err := New(opts)
+	if err != nil {
+		return err
+	}
+	cache.SetSecrets(opts.Secrets...)
+
+	mu.Lock()
+	defaultClient = client
+	mu.Unlock()
+	return nil
+}
+
+// Default returns the shared client
+func Default() *http.Client {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultClient
+}
+
+// WithTimeout returns a client sharing the default transport with a different timeout
+func WithTimeout(timeout time.Duration) *http.Client {
+	client := *Default()
+	client.Timeout = timeout
+	return &client
+}
+
+// NoRedirects returns a client sharing the default transport that does not follow redirects
+func NoRedirects() *http.Client {
+	client := *Default()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &client
+}
+
+// Get sends a GET request with the shared client, cancelled when ctx ends
+func Get(ctx context.Context, rawURL string) (*http.Response, error) {
+	return Do(ctx, Default(), "GET", rawURL)
+}
+
+// Do sends a request without a body using client, cancelled when ctx ends
+func Do(ctx context.Context, client *http.Client, method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+// userAgentTransport sets the configured User-Agent on requests that have none
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.next.RoundTrip(req)
+}
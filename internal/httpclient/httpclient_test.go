@@ -0,0 +1,30 @@
+package httpclient
+
+import (
+	"testing"
+
+	"github.com/malika/osint-master/config"
+)
+
+func TestOptionsFromConfig(t *testing.T) {
+	defaults := DefaultOptions()
+
+	tests := []struct {
+		name          string
+		cfg           *config.Config
+		wantRetries   int
+		wantRateLimit float64
+	}{
+		{"no config", nil, defaults.MaxRetries, defaults.RateLimit},
+		{"zero value config keeps the defaults", &config.Config{CacheDisabled: true}, defaults.MaxRetries, defaults.RateLimit},
+		{"explicit values", &config.Config{HTTPRetries: 5, RateLimit: 0.5, CacheDisabled: true}, 5, 0.5},
+		{"negative values turn retries and rate limiting off", &config.Config{HTTPRetries: -1, RateLimit: -1, CacheDisabled: true}, 0, 0},
+	}
+
+	for _, tc := range tests {
+		opts := OptionsFromConfig(tc.cfg)
+		if opts.MaxRetries != tc.wantRetries || opts.RateLimit != tc.wantRateLimit {
+			t.Errorf("%s: retries = %d, rate limit = %v, want %d and %v", tc.name, opts.MaxRetries, opts.RateLimit, tc.wantRetries, tc.wantRateLimit)
+		}
+	}
+}
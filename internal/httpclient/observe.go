@@ -0,0 +1,52 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/malika/osint-master/internal/cache"
+)
+
+// RequestFunc is told about a request that left the process, once its response or error is known
+// The Response it receives has no header or body
+type RequestFunc func(r Response)
+
+// requestFuncKey is the context key for the request observer
+type requestFuncKey struct{}
+
+// ObserveRequests returns a context whose outbound requests are reported to fn
+// Answers served from the cache never leave the process and are not reported
+func ObserveRequests(ctx context.Context, fn RequestFunc) context.Context {
+	return context.WithValue(ctx, requestFuncKey{}, fn)
+}
+
+// observeTransport reports requests whose context carries a RequestFunc
+// It sits inside the cache and outside the retries, so a request is reported once with its final status
+type observeTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *observeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fn, ok := req.Context().Value(requestFuncKey{}).(RequestFunc)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	record := Response{
+		Provider:  strings.ToLower(req.URL.Hostname()),
+		Method:    req.Method,
+		URL:       cache.RedactURL(req.URL.String()),
+		FetchedAt: time.Now().UTC(),
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		record.Error = cache.RedactSecrets(err.Error())
+	} else {
+		record.Status = resp.StatusCode
+	}
+	fn(record)
+	return resp, err
+}
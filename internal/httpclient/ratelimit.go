@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimiter throttles requests with one token bucket per host
+type rateLimiter struct {
+	next  http.RoundTripper
+	rate  float64
+	burst int
+	hosts map[string]float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// bucket is a token bucket refilled at rate tokens per second
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter wraps next, returning it unchanged when every limit is disabled
+func newRateLimiter(next http.RoundTripper, rate float64, burst int, hosts map[string]float64) http.RoundTripper {
+	if rate <= 0 && len(hosts) == 0 {
+		return next
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &rateLimiter{
+		next:    next,
+		rate:    rate,
+		burst:   burst,
+		hosts:   hosts,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (l *rateLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if wait := l.reserve(req.URL.Hostname()); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	return l.next.RoundTrip(req)
+}
+
+// reserve takes a token for host and returns how long to wait until it is valid
+func (l *rateLimiter) reserve(host string) time.Duration {
+	host = strings.ToLower(host)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[host]
+	if !ok {
+		rate := l.rateFor(host)
+		if rate <= 0 {
+			return 0
+		}
+		b = &bucket{rate: rate, burst: float64(l.burst), tokens: float64(l.burst), last: time.Now()}
+		l.buckets[host] = b
+	}
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	// Tokens may go negative, later callers queue behind earlier ones
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// rateFor returns the limit for host, matching overrides on the host or any parent domain
+func (l *rateLimiter) rateFor(host string) float64 {
+	for h := host; h != ""; {
+		if rate, ok := l.hosts[h]; ok {
+			return rate
+		}
+		i := strings.Index(h, ".")
+		if i < 0 {
+			break
+		}
+		h = h[i+1:]
+	}
+	return l.rate
+}
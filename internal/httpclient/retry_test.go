@@ -0,0 +1,66 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryOnlyIdempotentMethods(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryTransport{
+		next:       http.DefaultTransport,
+		maxRetries: 2,
+		baseDelay:  time.Millisecond,
+		maxDelay:   time.Millisecond,
+	}}
+
+	tests := []struct {
+		method   string
+		body     string
+		wantHits int32
+	}{
+		{http.MethodGet, "", 3},
+		{http.MethodHead, "", 3},
+		{http.MethodOptions, "", 3},
+		{http.MethodPut, `{"name": "acme"}`, 3},
+		{http.MethodDelete, "", 3},
+		{http.MethodPost, `{"name": "acme"}`, 1},
+		{http.MethodPost, "", 1},
+		{http.MethodPatch, `{"name": "acme"}`, 1},
+	}
+
+	for _, tc := range tests {
+		atomic.StoreInt32(&hits, 0)
+
+		var req *http.Request
+		var err error
+		if tc.body != "" {
+			req, err = http.NewRequest(tc.method, server.URL, strings.NewReader(tc.body))
+		} else {
+			req, err = http.NewRequest(tc.method, server.URL, nil)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.method, err)
+		}
+		resp.Body.Close()
+
+		if got := atomic.LoadInt32(&hits); got != tc.wantHits {
+			t.Errorf("%s with body %q: sent %d times, want %d", tc.method, tc.body, got, tc.wantHits)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Package opsec classifies every check as passive or active
+// Passive checks only ask third parties about a target, active checks contact the target's own
+// infrastructure or accounts and can tip it off; passive mode skips them and records what was skipped
+package opsec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/malika/osint-master/internal/scope"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Class is how a check touches its target
+type Class string
+
+// Check classes
+const (
+	Passive Class = "passive" // third-party data only
+	Active  Class = "active"  // contacts the target's infrastructure or accounts
+)
+
+// Check is a named step of a lookup module
+type Check struct {
+	Name        string   `json:"name"`
+	Modules     []string `json:"modules"`
+	Class       Class    `json:"class"`
+	Description string   `json:"description"`
+}
+
+// checks lists the active checks, anything not listed only talks to third parties
+var checks = map[string]Check{
+	"dns_resolve": {
+		Modules:     []string{result.ModuleDomain},
+		Class:       Active,
+		Description: "resolves subdomains and their CNAMEs through the local resolver",
+	},
+	"tls_certificate": {
+		Modules:     []string{result.ModuleDomain},
+		Class:       Active,
+		Description: "dials port 443 of each subdomain to read its certificate",
+	},
+	"takeover_probe": {
+		Modules:     []string{result.ModuleDomain},
+		Class:       Active,
+		Description: "sends GET requests to subdomains with a dangling CNAME",
+	},
+	"github_profile": {
+		Modules:     []string{result.ModuleEmail},
+		Class:       Active,
+		Description: "fetches the GitHub account of the email's local part",
+	},
+	"instagram_profile": {
+		Modules:     []string{result.ModuleEmail},
+		Class:       Active,
+		Description: "fetches the Instagram profile page of the email's local part",
+	},
+	"profile_check": {
+		Modules:     []string{result.ModuleUsername},
+		Class:       Active,
+		Description: "fetches the username's profile page on every platform",
+	},
+	"browser_automation": {
+		Modules:     []string{result.ModuleUsername, result.ModulePhone},
+		Class:       Active,
+		Description: "drives a browser to profile and caller ID pages (--advanced)",
+	},
+}
+
+// Checks returns the classified checks sorted by module and name
+func Checks() []Check {
+	list := make([]Check, 0, len(checks))
+	for name, c := range checks {
+		c.Name = name
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Modules[0] != list[j].Modules[0] {
+			return list[i].Modules[0] < list[j].Modules[0]
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Classify returns the class of a check, unknown checks are passive
+func Classify(name string) Class {
+	if c, ok := checks[name]; ok {
+		return c.Class
+	}
+	return Passive
+}
+
+// Request is an outbound call a lookup makes, declared up front by each provider
+// Targets are filled into URL, API keys appear as a <NAME> placeholder and never in clear
+type Request struct {
+	Provider string `json:"provider"`
+	Check    string `json:"check,omitempty"` // name of the check classifying the request
+	Method   string `json:"method"`          // HTTP method, or DNS and TLS for direct connections
+	URL      string `json:"url"`
+	APIKey   bool   `json:"api_key"`
+	Class    Class  `json:"class"`
+	When     string `json:"when,omitempty"` // condition of a fallback or repeated request
+}
+
+// Declare describes a request made by check, an empty check is a passive provider call
+func Declare(provider, check, method, rawURL string) Request {
+	return Request{Provider: provider, Check: check, Method: method, URL: rawURL, Class: Classify(check)}
+}
+
+// WithKey marks a request as sending an API key
+func (r Request) WithKey() Request {
+	r.APIKey = true
+	return r
+}
+
+// If sets the condition under which the request is made
+func (r Request) If(when string) Request {
+	r.When = when
+	return r
+}
+
+// Host returns the host the request goes to
+func (r Request) Host() string {
+	if u, err := url.Parse(r.URL); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+
+	// Templates such as tls://{subdomain}:443 do not parse as URLs
+	host := r.URL
+	if _, rest, ok := strings.Cut(host, "://"); ok {
+		host = rest
+	}
+	if i := strings.IndexAny(host, "/?"); i >= 0 {
+		host = host[:i]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return host
+}
+
+// SkippedError is returned by Allow for an active check in passive mode
+type SkippedError struct {
+	Check  string
+	Target string
+}
+
+// Error implements error
+func (e *SkippedError) Error() string {
+	return fmt.Sprintf("%s of %s skipped in passive mode", e.Check, e.Target)
+}
+
+// Skipped reports whether err comes from passive mode rather than the engagement scope
+func Skipped(err error) bool {
+	var skipped *SkippedError
+	return errors.As(err, &skipped)
+}
+
+// passiveKey is the context key for passive mode
+type passiveKey struct{}
+
+// WithPassive returns a context in which active checks are skipped
+func WithPassive(ctx context.Context) context.Context {
+	return context.WithValue(ctx, passiveKey{}, true)
+}
+
+// PassiveOnly reports whether active checks are skipped on ctx
+func PassiveOnly(ctx context.Context) bool {
+	passive, _ := ctx.Value(passiveKey{}).(bool)
+	return passive
+}
+
+// SkipLog collects the active checks skipped during a lookup
+type SkipLog struct {
+	mu      sync.Mutex
+	skipped []result.SkippedCheck
+}
+
+// skipLogKey is the context key for the skip log
+type skipLogKey struct{}
+
+// TrackSkipped returns a context whose skipped checks are recorded in the returned log
+func TrackSkipped(ctx context.Context) (context.Context, *SkipLog) {
+	log := &SkipLog{}
+	return context.WithValue(ctx, skipLogKey{}, log), log
+}
+
+// Checks returns the skipped checks in the order they were first skipped
+func (l *SkipLog) Checks() []result.SkippedCheck {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	checks := make([]result.SkippedCheck, len(l.skipped))
+	for i, s := range l.skipped {
+		s.Targets = append([]string(nil), s.Targets...)
+		checks[i] = s
+	}
+	return checks
+}
+
+// add records a skipped check of target, each target is listed once per check
+func (l *SkipLog) add(name, target string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range l.skipped {
+		s := &l.skipped[i]
+		if s.Check != name {
+			continue
+		}
+		for _, t := range s.Targets {
+			if t == target {
+				return
+			}
+		}
+		s.Targets = append(s.Targets, target)
+		return
+	}
+	l.skipped = append(l.skipped, result.SkippedCheck{
+		Check:       name,
+		Description: checks[name].Description,
+		Targets:     []string{target},
+	})
+}
+
+// Allow is called by a check before it touches target, kind is the module the target belongs to
+// Active checks are skipped in passive mode and held to the engagement scope otherwise;
+// the error is a *SkippedError or a *scope.OutOfScopeError
+func Allow(ctx context.Context, name, kind, target string) error {
+	c, ok := checks[name]
+	if !ok || c.Class == Passive {
+		return nil
+	}
+
+	if PassiveOnly(ctx) {
+		if log, ok := ctx.Value(skipLogKey{}).(*SkipLog); ok {
+			log.add(name, target)
+		}
+		return &SkippedError{Check: name, Target: target}
+	}
+	return scope.Check(ctx, name, kind, target)
+}
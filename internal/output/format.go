@@ -0,0 +1,162 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/malika/osint-master/pkg/pivot"
+	"github.com/malika/osint-master/pkg/render"
+	"github.com/malika/osint-master/pkg/report"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Supported output formats
+const (
+	FormatText   = "text"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// SchemaVersion is bumped whenever a field is renamed or removed from the JSON output
+const SchemaVersion = "1.0"
+
+// Document is the JSON document written for -format json
+// Summary is only set when the document holds more than one result, Graph only for pivot runs
+type Document struct {
+	SchemaVersion string           `json:"schema_version"`
+	Tool          string           `json:"tool"`
+	ToolVersion   string           `json:"tool_version"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+	Summary       *report.Summary  `json:"summary,omitempty"`
+	Graph         *pivot.Graph     `json:"graph,omitempty"`
+	Results       []*result.Result `json:"results"`
+}
+
+// Record is one line of -format ndjson output
+type Record struct {
+	SchemaVersion string `json:"schema_version"`
+	*result.Result
+}
+
+// ToolVersion is reported in JSON documents, set by main
+var ToolVersion = "dev"
+
+// ValidateFormat checks that a format name is supported
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatText, FormatJSON, FormatNDJSON:
+		return nil
+	}
+	return fmt.Errorf("unsupported output format: %s (use text, json or ndjson)", format)
+}
+
+// Format renders results in the requested output format
+func Format(format string, results ...*result.Result) (string, error) {
+	switch format {
+	case FormatText, "":
+		// Several targets make one investigation report
+		if len(results) > 1 {
+			return report.Text(results), nil
+		}
+		texts := make([]string, 0, len(results))
+		for _, res := range results {
+			texts = append(texts, render.Text(res))
+		}
+		return strings.Join(texts, "\n"), nil
+
+	case FormatJSON:
+		doc := Document{
+			SchemaVersion: SchemaVersion,
+			Tool:          "osintmaster",
+			ToolVersion:   ToolVersion,
+			GeneratedAt:   time.Now().UTC(),
+			Results:       results,
+		}
+		if len(results) > 1 {
+			doc.Summary = report.Summarize(results)
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode JSON: %v", err)
+		}
+		return string(data) + "\n", nil
+
+	case FormatNDJSON:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, res := range results {
+			if err := enc.Encode(Record{SchemaVersion: SchemaVersion, Result: res}); err != nil {
+				return "", fmt.Errorf("failed to encode NDJSON: %v", err)
+			}
+		}
+		return buf.String(), nil
+	}
+
+	return "", ValidateFormat(format)
+}
+
+// FormatGraph renders a pivot run: the looked up results followed by the entity graph
+func FormatGraph(format string, g *pivot.Graph) (string, error) {
+	switch format {
+	case FormatText, "":
+		return report.Text(g.Results) + "\n" + pivot.Text(g), nil
+
+	case FormatJSON:
+		doc := Document{
+			SchemaVersion: SchemaVersion,
+			Tool:          "osintmaster",
+			ToolVersion:   ToolVersion,
+			GeneratedAt:   time.Now().UTC(),
+			Summary:       report.Summarize(g.Results),
+			Graph:         g,
+			Results:       g.Results,
+		}
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode JSON: %v", err)
+		}
+		return string(data) + "\n", nil
+	}
+
+	return Format(format, g.Results...)
+}
+
+// SaveResults writes results to a file in the requested format
+// Text output keeps the "Generated:" header, JSON output is written as-is
+func SaveResults(filename, format string, results ...*result.Result) error {
+	content, err := Format(format, results...)
+	if err != nil {
+		return err
+	}
+	return save(filename, format, content)
+}
+
+// SaveGraph writes a pivot run to a file in the requested format
+func SaveGraph(filename, format string, g *pivot.Graph) error {
+	content, err := FormatGraph(format, g)
+	if err != nil {
+		return err
+	}
+	return save(filename, format, content)
+}
+
+// save writes formatted content, adding the "Generated:" header to text
+func save(filename, format, content string) error {
+	if format == FormatText || format == "" {
+		return SaveToFile(filename, content)
+	}
+
+	if filename == "" {
+		return fmt.Errorf("filename cannot be empty")
+	}
+
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+// Package providertest runs local stand-ins for provider APIs in tests
+// A stand-in answers each request path with a canned response, usually a payload kept in testdata,
+// so provider functions can be checked against real schemas, errors and rate limits without the network
+package providertest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/malika/osint-master/internal/httpclient"
+)
+
+// Response is the canned answer to one path
+type Response struct {
+	Status int               // default 200
+	Body   string            // sent as is, malformed payloads included
+	Header map[string]string // Content-Type defaults to application/json
+}
+
+// Stand is a running stand-in server
+type Stand struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+// New starts a stand-in answering request paths from routes, other paths get 404
+// The server is closed when the test ends
+func New(t testing.TB, routes map[string]Response) *Stand {
+	t.Helper()
+
+	s := &Stand{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Clone(r.Context()))
+		s.mu.Unlock()
+
+		resp, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		for name, value := range resp.Header {
+			w.Header().Set(name, value)
+		}
+		if resp.Status != 0 {
+			w.WriteHeader(resp.Status)
+		}
+		w.Write([]byte(resp.Body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Requests returns the requests received so far
+func (s *Stand) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// Payload reads a file from the testdata directory of the package under test
+func Payload(t testing.TB, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read payload: %v", err)
+	}
+	return string(data)
+}
+
+// TooManyRequests is the answer of a rate limited provider
+// Its Retry-After is longer than the test client waits, so the 429 reaches the provider function
+var TooManyRequests = Response{
+	Status: http.StatusTooManyRequests,
+	Body:   `{"error":"rate limit exceeded"}`,
+	Header: map[string]string{"Retry-After": "60"},
+}
+
+var (
+	clientOnce sync.Once
+	clientErr  error
+)
+
+// UseTestClient replaces the shared client with one that has no cache or rate limit and retries once without delay
+// It is configured once for the test binary and left in place, so tests using it can run in parallel
+func UseTestClient(t testing.TB) {
+	t.Helper()
+
+	clientOnce.Do(func() {
+		opts := httpclient.DefaultOptions()
+		opts.Timeout = 5 * time.Second
+		opts.MaxRetries = 1
+		opts.RetryBaseDelay = time.Millisecond
+		opts.RetryMaxDelay = 10 * time.Millisecond
+		opts.RateLimit = 0
+		opts.HostRateLimits = nil
+		opts.CacheDir = ""
+		clientErr = httpclient.Configure(opts)
+	})
+	if clientErr != nil {
+		t.Fatalf("failed to configure the HTTP client: %v", clientErr)
+	}
+}
@@ -0,0 +1,377 @@
+// Package scope enforces the engagement scope of an investigation
+// Active checks, anything that touches a target's own systems, call Check before they run;
+// every decision is appended to the scope log so staying in scope can be shown afterwards
+package scope
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Enforcement modes
+const (
+	ModeEnforce = "enforce" // out-of-scope active checks are refused
+	ModeWarn    = "warn"    // out-of-scope active checks run with a warning
+)
+
+// Decisions recorded in the log
+const (
+	DecisionAllowed = "allowed"
+	DecisionBlocked = "blocked"
+	DecisionWarned  = "warned"
+)
+
+// Scope is an engagement scope file
+type Scope struct {
+	Name         string   `yaml:"name"`
+	ValidFrom    string   `yaml:"valid_from"`  // date or RFC 3339 time
+	ValidUntil   string   `yaml:"valid_until"` // a date includes the whole day
+	Mode         string   `yaml:"mode"`        // enforce (default) or warn
+	Domains      []string `yaml:"domains"`     // "example.com" exactly, "*.example.com" its subdomains
+	CIDRs        []string `yaml:"cidrs"`       // ranges or single addresses
+	EmailDomains []string `yaml:"email_domains"`
+	Usernames    []string `yaml:"usernames"`
+	Phones       []string `yaml:"phones"`
+	Names        []string `yaml:"names"`
+	Log          string   `yaml:"log"` // default ~/.osintmaster/scope/<name>.jsonl
+
+	from, until time.Time
+	networks    []*net.IPNet
+
+	mu      sync.Mutex
+	logFile *os.File
+}
+
+// Entry is one recorded scope decision
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Scope    string    `json:"scope"`
+	Check    string    `json:"check"` // e.g. tls_certificate, takeover_probe, browser_automation
+	Kind     string    `json:"kind"`
+	Target   string    `json:"target"`
+	Active   bool      `json:"active"`
+	Decision string    `json:"decision"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// OutOfScopeError is returned by Check for a refused check
+type OutOfScopeError struct {
+	Check  string
+	Target string
+	Reason string
+}
+
+// Error implements error
+func (e *OutOfScopeError) Error() string {
+	return fmt.Sprintf("%s of %s refused: %s", e.Check, e.Target, e.Reason)
+}
+
+// Load reads and validates a scope file
+func Load(path string) (*Scope, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scope file: %v", err)
+	}
+
+	var s Scope
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &s, nil
+}
+
+// validate checks the scope and fills in defaults
+func (s *Scope) validate() error {
+	switch s.Mode {
+	case "":
+		s.Mode = ModeEnforce
+	case ModeEnforce, ModeWarn:
+	default:
+		return fmt.Errorf("unknown mode %q (use enforce or warn)", s.Mode)
+	}
+
+	var err error
+	if s.from, err = parseTime(s.ValidFrom, false); err != nil {
+		return fmt.Errorf("invalid valid_from: %v", err)
+	}
+	if s.until, err = parseTime(s.ValidUntil, true); err != nil {
+		return fmt.Errorf("invalid valid_until: %v", err)
+	}
+	if !s.from.IsZero() && !s.until.IsZero() && !s.until.After(s.from) {
+		return fmt.Errorf("valid_until must be after valid_from")
+	}
+
+	for _, entry := range s.CIDRs {
+		cidr := strings.TrimSpace(entry)
+		if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+			cidr += "/32"
+		} else if ip != nil {
+			cidr += "/128"
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid CIDR %q", entry)
+		}
+		s.networks = append(s.networks, network)
+	}
+
+	if s.Log == "" {
+		configDir, err := config.GetConfigPath()
+		if err != nil {
+			return err
+		}
+		s.Log = filepath.Join(configDir, "scope", unsafeChars.ReplaceAllString(s.Name, "_")+".jsonl")
+	}
+	return nil
+}
+
+// unsafeChars are replaced in the default log file name
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// parseTime parses a date or RFC 3339 time, a date used as an end covers the whole day
+func parseTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a date (2006-01-02) or RFC 3339 time", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Summary describes the scope in one line
+func (s *Scope) Summary() string {
+	period := "no validity period"
+	switch {
+	case s.ValidFrom != "" && s.ValidUntil != "":
+		period = fmt.Sprintf("valid %s to %s", s.ValidFrom, s.ValidUntil)
+	case s.ValidUntil != "":
+		period = "valid until " + s.ValidUntil
+	case s.ValidFrom != "":
+		period = "valid from " + s.ValidFrom
+	}
+	return fmt.Sprintf("%s (%s, %s mode, log %s)", s.Name, period, s.Mode, s.Log)
+}
+
+// Valid reports whether the scope applies at t, with the reason when it does not
+func (s *Scope) Valid(t time.Time) (bool, string) {
+	if !s.from.IsZero() && t.Before(s.from) {
+		return false, "the engagement starts " + s.from.Format("2006-01-02 15:04")
+	}
+	if !s.until.IsZero() && !t.Before(s.until) {
+		return false, "the engagement ended " + s.until.Format("2006-01-02 15:04")
+	}
+	return true, ""
+}
+
+// Contains reports whether a target of the given kind is in scope
+func (s *Scope) Contains(kind, target string) bool {
+	target = strings.ToLower(strings.TrimSpace(target))
+
+	switch kind {
+	case result.ModuleDomain:
+		return matchDomain(s.Domains, strings.TrimSuffix(target, "."))
+	case result.ModuleIP:
+		ip := net.ParseIP(target)
+		for _, network := range s.networks {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	case result.ModuleEmail:
+		_, host, ok := strings.Cut(target, "@")
+		if !ok {
+			return false
+		}
+		for _, d := range s.EmailDomains {
+			if strings.EqualFold(strings.TrimSpace(d), target) {
+				return true
+			}
+		}
+		return matchDomain(s.EmailDomains, host)
+	case result.ModuleUsername:
+		target = strings.TrimPrefix(target, "@")
+		for _, u := range s.Usernames {
+			if strings.EqualFold(strings.TrimPrefix(strings.TrimSpace(u), "@"), target) {
+				return true
+			}
+		}
+		return false
+	case result.ModulePhone:
+		digits := phoneDigits(target)
+		for _, p := range s.Phones {
+			if digits != "" && phoneDigits(p) == digits {
+				return true
+			}
+		}
+		return false
+	case result.ModuleName:
+		for _, n := range s.Names {
+			if strings.EqualFold(strings.Join(strings.Fields(n), " "), strings.Join(strings.Fields(target), " ")) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// matchDomain matches exact domains and *.domain wildcards, which cover every level of subdomain
+func matchDomain(patterns []string, domain string) bool {
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if parent, ok := strings.CutPrefix(p, "*."); ok {
+			if strings.HasSuffix(domain, "."+parent) {
+				return true
+			}
+		} else if p == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// nonDigits are removed when comparing phone numbers
+var nonDigits = regexp.MustCompile(`\D`)
+
+// phoneDigits returns the digits of a phone number
+func phoneDigits(phone string) string {
+	return nonDigits.ReplaceAllString(phone, "")
+}
+
+// decide evaluates a check, returning the decision and why
+func (s *Scope) decide(kind, target string, active bool, now time.Time) (string, string) {
+	reason := ""
+	if ok, why := s.Valid(now); !ok {
+		reason = why
+	} else if !s.Contains(kind, target) {
+		reason = fmt.Sprintf("%s %s is not in scope %s", kind, target, s.Name)
+	}
+
+	switch {
+	case reason == "":
+		return DecisionAllowed, ""
+	case active && s.Mode == ModeEnforce:
+		return DecisionBlocked, reason
+	}
+	return DecisionWarned, reason
+}
+
+// record appends an entry to the scope log
+// A log that cannot be written is reported once per failure on stderr, it never stops a check
+func (s *Scope) record(e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.logFile == nil {
+		if err := os.MkdirAll(filepath.Dir(s.Log), 0700); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to create scope log directory: %v\n", err)
+			return
+		}
+		f, err := os.OpenFile(s.Log, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to open scope log: %v\n", err)
+			return
+		}
+		s.logFile = f
+	}
+
+	if err := json.NewEncoder(s.logFile).Encode(e); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write scope log: %v\n", err)
+	}
+}
+
+// Close closes the scope log
+func (s *Scope) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.logFile == nil {
+		return nil
+	}
+	err := s.logFile.Close()
+	s.logFile = nil
+	return err
+}
+
+// scopeKey is the context key for the scope
+type scopeKey struct{}
+
+// WithScope returns a context whose checks are held to s
+func WithScope(ctx context.Context, s *Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, s)
+}
+
+// FromContext returns the scope set on ctx, nil when there is none
+func FromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+// Check is called by an active check before it touches target
+// It records the decision and returns an *OutOfScopeError when the check must not run
+// Without a scope on ctx every check is allowed and nothing is recorded
+func Check(ctx context.Context, check, kind, target string) error {
+	return evaluate(ctx, check, kind, target, true)
+}
+
+// Note records a passive lookup of target, warning when it is out of scope
+func Note(ctx context.Context, check, kind, target string) {
+	evaluate(ctx, check, kind, target, false)
+}
+
+// evaluate decides, records and reports a check
+func evaluate(ctx context.Context, check, kind, target string, active bool) error {
+	s := FromContext(ctx)
+	if s == nil {
+		return nil
+	}
+
+	now := time.Now()
+	decision, reason := s.decide(kind, target, active, now)
+	s.record(Entry{
+		Time:     now.UTC(),
+		Scope:    s.Name,
+		Check:    check,
+		Kind:     kind,
+		Target:   target,
+		Active:   active,
+		Decision: decision,
+		Reason:   reason,
+	})
+
+	switch decision {
+	case DecisionBlocked:
+		return &OutOfScopeError{Check: check, Target: target, Reason: reason}
+	case DecisionWarned:
+		fmt.Fprintf(os.Stderr, "⚠️  Out of scope: %s of %s (%s)\n", check, target, reason)
+	}
+	return nil
+}
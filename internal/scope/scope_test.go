@@ -0,0 +1,226 @@
+package scope
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// testScope returns a validated scope logging to a temporary file
+func testScope(t *testing.T, mode string) *Scope {
+	t.Helper()
+
+	s := &Scope{
+		Name:         "acme",
+		Mode:         mode,
+		Domains:      []string{"acme.com", "*.acme.net", " *.Corp.Example "},
+		CIDRs:        []string{"203.0.113.0/24", "198.51.100.7", "2001:db8::/32", " 2001:db8:ffff::1 "},
+		EmailDomains: []string{"acme.com", "*.acme.org", "ceo@partner.com"},
+		Usernames:    []string{"@acme_sec"},
+		Phones:       []string{"+1 (415) 555-2671"},
+		Names:        []string{"Jane  Doe"},
+		Log:          filepath.Join(t.TempDir(), "scope.jsonl"),
+	}
+	if err := s.validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestContains(t *testing.T) {
+	s := testScope(t, "")
+
+	tests := []struct {
+		kind   string
+		target string
+		want   bool
+	}{
+		{result.ModuleDomain, "acme.com", true},
+		{result.ModuleDomain, "ACME.com.", true},
+		{result.ModuleDomain, "www.acme.com", false}, // no wildcard for acme.com
+		{result.ModuleDomain, "notacme.com", false},
+		{result.ModuleDomain, "www.acme.net", true},
+		{result.ModuleDomain, "a.b.acme.net", true},
+		{result.ModuleDomain, "acme.net", false}, // a wildcard covers subdomains only
+		{result.ModuleDomain, "evilacme.net", false},
+		{result.ModuleDomain, "acme.net.evil.com", false},
+		{result.ModuleDomain, "vpn.corp.example", true},
+
+		{result.ModuleIP, "203.0.113.0", true},
+		{result.ModuleIP, "203.0.113.255", true},
+		{result.ModuleIP, "203.0.114.1", false},
+		{result.ModuleIP, "198.51.100.7", true},
+		{result.ModuleIP, "198.51.100.8", false},
+		{result.ModuleIP, "2001:db8::1", true},
+		{result.ModuleIP, "2001:db9::1", false},
+		{result.ModuleIP, "2001:db8:ffff::1", true},
+		{result.ModuleIP, "::ffff:203.0.113.9", true},
+		{result.ModuleIP, "not-an-ip", false},
+		{result.ModuleIP, "", false},
+
+		{result.ModuleEmail, "bob@acme.com", true},
+		{result.ModuleEmail, "bob@mail.acme.org", true},
+		{result.ModuleEmail, "bob@acme.org", false},
+		{result.ModuleEmail, "CEO@Partner.com", true},
+		{result.ModuleEmail, "cfo@partner.com", false},
+		{result.ModuleEmail, "acme.com", false},
+
+		{result.ModuleUsername, "acme_sec", true},
+		{result.ModuleUsername, "@ACME_sec", true},
+		{result.ModuleUsername, "acme", false},
+
+		{result.ModulePhone, "14155552671", true},
+		{result.ModulePhone, "+1-415-555-2671", true},
+		{result.ModulePhone, "+1 415 555 2672", false},
+		{result.ModulePhone, "call me", false},
+
+		{result.ModuleName, "jane doe", true},
+		{result.ModuleName, "Jane Q Doe", false},
+
+		{"vehicle", "acme.com", false},
+	}
+
+	for _, tc := range tests {
+		if got := s.Contains(tc.kind, tc.target); got != tc.want {
+			t.Errorf("Contains(%s, %q) = %v, want %v", tc.kind, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   *Scope
+		wantErr string
+	}{
+		{"bad CIDR", &Scope{CIDRs: []string{"203.0.113.0/33"}}, "invalid CIDR"},
+		{"hostname as CIDR", &Scope{CIDRs: []string{"acme.com"}}, "invalid CIDR"},
+		{"unknown mode", &Scope{Mode: "audit"}, "unknown mode"},
+		{"bad date", &Scope{ValidFrom: "16/10/2026"}, "invalid valid_from"},
+		{"empty period", &Scope{ValidFrom: "2026-10-16", ValidUntil: "2026-10-15"}, "must be after"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.scope
+			s.Log = filepath.Join(t.TempDir(), "scope.jsonl")
+			if err := s.validate(); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckOutOfScope(t *testing.T) {
+	tests := []struct {
+		name         string
+		mode         string
+		until        string
+		active       bool
+		target       string
+		wantBlocked  bool
+		wantDecision string
+	}{
+		{name: "in scope", active: true, target: "www.acme.net", wantDecision: DecisionAllowed},
+		{name: "enforce blocks active", active: true, target: "www.other.com", wantBlocked: true, wantDecision: DecisionBlocked},
+		{name: "warn runs active", mode: ModeWarn, active: true, target: "www.other.com", wantDecision: DecisionWarned},
+		{name: "passive only warns", target: "www.other.com", wantDecision: DecisionWarned},
+		{name: "expired blocks in scope target", until: "2020-01-01", active: true, target: "www.acme.net", wantBlocked: true, wantDecision: DecisionBlocked},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := testScope(t, tc.mode)
+			if tc.until != "" {
+				s.ValidUntil = tc.until
+				if err := s.validate(); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			ctx := WithScope(context.Background(), s)
+
+			var err error
+			if tc.active {
+				err = Check(ctx, "takeover_probe", result.ModuleDomain, tc.target)
+			} else {
+				Note(ctx, "crt.sh", result.ModuleDomain, tc.target)
+			}
+			var outOfScope *OutOfScopeError
+			if blocked := errors.As(err, &outOfScope); blocked != tc.wantBlocked {
+				t.Errorf("error = %v, want blocked %v", err, tc.wantBlocked)
+			}
+
+			entries := readLog(t, s)
+			if len(entries) != 1 {
+				t.Fatalf("logged %d entries, want 1", len(entries))
+			}
+			e := entries[0]
+			if e.Decision != tc.wantDecision || e.Target != tc.target || e.Active != tc.active {
+				t.Errorf("entry = %+v, want decision %s for %s", e, tc.wantDecision, tc.target)
+			}
+			if (e.Reason == "") != (tc.wantDecision == DecisionAllowed) {
+				t.Errorf("reason = %q for decision %s", e.Reason, e.Decision)
+			}
+		})
+	}
+}
+
+func TestCheckWithoutScope(t *testing.T) {
+	if err := Check(context.Background(), "takeover_probe", result.ModuleDomain, "www.other.com"); err != nil {
+		t.Errorf("unexpected error without a scope: %v", err)
+	}
+}
+
+func TestValid(t *testing.T) {
+	s := &Scope{ValidFrom: "2026-10-01T09:00:00Z", ValidUntil: "2026-10-31T18:00:00Z", Log: "unused"}
+	if err := s.validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 10, 1, 8, 59, 0, 0, time.UTC), false},
+		{time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 31, 17, 59, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 31, 18, 0, 0, 0, time.UTC), false},
+	}
+	for _, tc := range tests {
+		if got, reason := s.Valid(tc.at); got != tc.want || (reason == "") != tc.want {
+			t.Errorf("Valid(%v) = %v, %q, want %v", tc.at, got, reason, tc.want)
+		}
+	}
+}
+
+// readLog returns the entries written to the scope log
+func readLog(t *testing.T, s *Scope) []Entry {
+	t.Helper()
+
+	s.Close()
+	f, err := os.Open(s.Log)
+	if err != nil {
+		t.Fatalf("failed to open scope log: %v", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
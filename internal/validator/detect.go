@@ -0,0 +1,172 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Kinds of target recognised by Detect
+const (
+	KindIP       = "ip"
+	KindCIDR     = "cidr"
+	KindASN      = "asn"
+	KindEmail    = "email"
+	KindPhone    = "phone"
+	KindURL      = "url"
+	KindDomain   = "domain"
+	KindHash     = "hash"
+	KindUsername = "username"
+	KindName     = "name"
+)
+
+// Detection is one interpretation of a target
+type Detection struct {
+	Kind   string // what the target looks like, e.g. "url"
+	Module string // lookup module to run, empty when no module handles the kind
+	Target string // value passed to the module, e.g. the host of a URL
+	Detail string // extra information such as the hash algorithm
+}
+
+// AmbiguousError is returned when a target matches several modules
+type AmbiguousError struct {
+	Target     string
+	Candidates []Detection
+}
+
+// Error implements error
+func (e *AmbiguousError) Error() string {
+	kinds := make([]string, 0, len(e.Candidates))
+	for _, c := range e.Candidates {
+		kinds = append(kinds, c.Kind)
+	}
+	return fmt.Sprintf("ambiguous target %q: could be a %s, use an explicit flag or type", e.Target, strings.Join(kinds, " or "))
+}
+
+// Detect returns every plausible interpretation of target
+// Unambiguous formats (IP, CIDR, email, URL, E.164, ASN, hash, domain) return a single detection
+func Detect(target string) ([]Detection, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, fmt.Errorf("target cannot be empty")
+	}
+
+	// A leading @ always means a username
+	if strings.HasPrefix(target, "@") {
+		if err := ValidateUsername(target); err != nil {
+			return nil, err
+		}
+		return []Detection{{Kind: KindUsername, Module: result.ModuleUsername, Target: target}}, nil
+	}
+
+	if d, ok := detectExact(target); ok {
+		return []Detection{d}, nil
+	}
+
+	// National numbers without separators are also valid usernames
+	var candidates []Detection
+	if ValidatePhone(target) == nil {
+		candidates = append(candidates, Detection{Kind: KindPhone, Module: result.ModulePhone, Target: target})
+	}
+	if ValidateUsername(target) == nil && !strings.Contains(target, ".") {
+		candidates = append(candidates, Detection{Kind: KindUsername, Module: result.ModuleUsername, Target: target})
+	}
+	if strings.Contains(target, " ") && hasLetter(target) && ValidateName(target) == nil {
+		candidates = append(candidates, Detection{Kind: KindName, Module: result.ModuleName, Target: target})
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("cannot detect the type of target: %s", target)
+	}
+	return candidates, nil
+}
+
+// detectExact matches the formats that cannot be mistaken for anything else
+func detectExact(target string) (Detection, bool) {
+	switch {
+	case ValidateIP(target) == nil:
+		return Detection{Kind: KindIP, Module: result.ModuleIP, Target: target}, true
+	case strings.Contains(target, "/") && ValidateCIDR(target) == nil:
+		return Detection{Kind: KindCIDR, Target: target}, true
+	case ValidateASN(target) == nil:
+		return Detection{Kind: KindASN, Target: strings.ToUpper(target)}, true
+	case ValidateEmail(target) == nil:
+		return Detection{Kind: KindEmail, Module: result.ModuleEmail, Target: target}, true
+	case strings.HasPrefix(target, "+") && ValidateE164(target) == nil:
+		return Detection{Kind: KindPhone, Module: result.ModulePhone, Target: target, Detail: "E.164"}, true
+	case ValidateURL(target) == nil:
+		return detectURL(target), true
+	}
+
+	if hash, err := HashType(target); err == nil {
+		return Detection{Kind: KindHash, Target: strings.ToLower(target), Detail: hash}, true
+	}
+	if ValidateDomain(target) == nil {
+		return Detection{Kind: KindDomain, Module: result.ModuleDomain, Target: target}, true
+	}
+	return Detection{}, false
+}
+
+// detectURL looks up the host of a URL, as an IP or a domain
+func detectURL(rawURL string) Detection {
+	u, _ := url.Parse(rawURL)
+	host := u.Hostname()
+
+	d := Detection{Kind: KindURL, Target: host, Detail: rawURL}
+	switch {
+	case ValidateIP(host) == nil:
+		d.Module = result.ModuleIP
+	case ValidateDomain(host) == nil:
+		d.Module = result.ModuleDomain
+	}
+	return d
+}
+
+// Classify returns the single module and target to use for target
+// Ambiguous targets return an *AmbiguousError, kinds without a module an explanation
+func Classify(target string) (Detection, error) {
+	candidates, err := Detect(target)
+	if err != nil {
+		return Detection{}, err
+	}
+	if len(candidates) > 1 {
+		return Detection{}, &AmbiguousError{Target: strings.TrimSpace(target), Candidates: candidates}
+	}
+
+	d := candidates[0]
+	if d.Module == "" {
+		return d, fmt.Errorf("%s looks like %s, which no lookup module handles", d.Target, describe(d))
+	}
+	return d, nil
+}
+
+// describe names the kind of a detection for messages, e.g. "an MD5 hash"
+func describe(d Detection) string {
+	switch d.Kind {
+	case KindCIDR:
+		return "a CIDR range"
+	case KindASN:
+		return "an autonomous system number"
+	case KindHash:
+		if d.Detail == "md5" {
+			return "an MD5 hash"
+		}
+		return "a " + strings.ToUpper(d.Detail) + " hash"
+	case KindURL:
+		return "a URL without a valid host"
+	}
+	return "a " + d.Kind
+}
+
+// hasLetter reports whether s contains a letter
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
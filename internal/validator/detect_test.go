@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+func TestDetect(t *testing.T) {
+	md5 := "d41d8cd98f00b204e9800998ecf8427e"
+	sha256 := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	tests := []struct {
+		target string
+		want   []Detection
+	}{
+		{"8.8.8.8", []Detection{{Kind: KindIP, Module: result.ModuleIP, Target: "8.8.8.8"}}},
+		{" 2001:4860:4860::8888 ", []Detection{{Kind: KindIP, Module: result.ModuleIP, Target: "2001:4860:4860::8888"}}},
+		{"192.0.2.0/24", []Detection{{Kind: KindCIDR, Target: "192.0.2.0/24"}}},
+		{"as15169", []Detection{{Kind: KindASN, Target: "AS15169"}}},
+		{"bill@microsoft.com", []Detection{{Kind: KindEmail, Module: result.ModuleEmail, Target: "bill@microsoft.com"}}},
+		{"+1 415 555 2671", []Detection{{Kind: KindPhone, Module: result.ModulePhone, Target: "+1 415 555 2671", Detail: "E.164"}}},
+		{"https://www.example.com/login?next=/", []Detection{{Kind: KindURL, Module: result.ModuleDomain, Target: "www.example.com", Detail: "https://www.example.com/login?next=/"}}},
+		{"http://93.184.216.34:8080/", []Detection{{Kind: KindURL, Module: result.ModuleIP, Target: "93.184.216.34", Detail: "http://93.184.216.34:8080/"}}},
+		{"http://intranet/", []Detection{{Kind: KindURL, Target: "intranet", Detail: "http://intranet/"}}},
+		{strings.ToUpper(md5), []Detection{{Kind: KindHash, Target: md5, Detail: "md5"}}},
+		{sha256, []Detection{{Kind: KindHash, Target: sha256, Detail: "sha256"}}},
+		{"example.com", []Detection{{Kind: KindDomain, Module: result.ModuleDomain, Target: "example.com"}}},
+		{"sub.example.co.uk", []Detection{{Kind: KindDomain, Module: result.ModuleDomain, Target: "sub.example.co.uk"}}},
+
+		// A leading @ is always a username, dots included
+		{"@john.doe", []Detection{{Kind: KindUsername, Module: result.ModuleUsername, Target: "@john.doe"}}},
+		{"john_doe", []Detection{{Kind: KindUsername, Module: result.ModuleUsername, Target: "john_doe"}}},
+		{"(415) 555-2671", []Detection{{Kind: KindPhone, Module: result.ModulePhone, Target: "(415) 555-2671"}}},
+		{"Jane Doe", []Detection{{Kind: KindName, Module: result.ModuleName, Target: "Jane Doe"}}},
+
+		// National numbers are valid usernames as well
+		{"4155552671", []Detection{
+			{Kind: KindPhone, Module: result.ModulePhone, Target: "4155552671"},
+			{Kind: KindUsername, Module: result.ModuleUsername, Target: "4155552671"},
+		}},
+		{"415-555-2671", []Detection{
+			{Kind: KindPhone, Module: result.ModulePhone, Target: "415-555-2671"},
+			{Kind: KindUsername, Module: result.ModuleUsername, Target: "415-555-2671"},
+		}},
+	}
+
+	for _, tc := range tests {
+		got, err := Detect(tc.target)
+		if err != nil {
+			t.Errorf("Detect(%q): unexpected error: %v", tc.target, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Detect(%q) = %+v, want %+v", tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestDetectErrors(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr string
+	}{
+		{"", "cannot be empty"},
+		{"   ", "cannot be empty"},
+		{"@bad name!", "invalid characters"},
+		{"!!!", "cannot detect"},
+		{"ftp://example.com", "cannot detect"},
+		{"999.1.1.1/24", "cannot detect"},
+	}
+
+	for _, tc := range tests {
+		if _, err := Detect(tc.target); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("Detect(%q) error = %v, want one containing %q", tc.target, err, tc.wantErr)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		target     string
+		wantModule string
+		wantTarget string
+		wantErr    string
+	}{
+		{target: "8.8.8.8", wantModule: result.ModuleIP, wantTarget: "8.8.8.8"},
+		{target: "https://example.com/", wantModule: result.ModuleDomain, wantTarget: "example.com"},
+		{target: "@john_doe", wantModule: result.ModuleUsername, wantTarget: "@john_doe"},
+		{target: "192.0.2.0/24", wantErr: "looks like a CIDR range, which no lookup module handles"},
+		{target: "AS15169", wantErr: "looks like an autonomous system number"},
+		{target: "d41d8cd98f00b204e9800998ecf8427e", wantErr: "looks like an MD5 hash"},
+		{target: "da39a3ee5e6b4b0d3255bfef95601890afd80709", wantErr: "looks like a SHA1 hash"},
+		{target: "http://intranet/", wantErr: "looks like a URL without a valid host"},
+		{target: "4155552671", wantErr: "could be a phone or username"},
+	}
+
+	for _, tc := range tests {
+		d, err := Classify(tc.target)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Classify(%q) error = %v, want one containing %q", tc.target, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Classify(%q): unexpected error: %v", tc.target, err)
+		} else if d.Module != tc.wantModule || d.Target != tc.wantTarget {
+			t.Errorf("Classify(%q) = %s %q, want %s %q", tc.target, d.Module, d.Target, tc.wantModule, tc.wantTarget)
+		}
+	}
+}
+
+func TestClassifyAmbiguous(t *testing.T) {
+	_, err := Classify(" 4155552671 ")
+
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("error = %v, want an *AmbiguousError", err)
+	}
+	if ambiguous.Target != "4155552671" || len(ambiguous.Candidates) != 2 {
+		t.Errorf("target = %q, candidates = %+v, want the trimmed target and 2 candidates", ambiguous.Target, ambiguous.Candidates)
+	}
+}
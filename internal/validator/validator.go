@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -46,8 +47,8 @@ func ValidateUsername(username string) error {
 		return fmt.Errorf("username too long (max 50 characters)")
 	}
 
-	// Basic alphanumeric check with underscores and hyphens
-	usernameRegex := regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	// Basic alphanumeric check with underscores, hyphens and dots
+	usernameRegex := regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
 	if !usernameRegex.MatchString(username) {
 		return fmt.Errorf("username contains invalid characters")
 	}
@@ -67,3 +68,88 @@ func ValidateName(name string) error {
 
 	return nil
 }
+
+// emailRegex matches a plain email address
+var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@([a-zA-Z0-9\-]+\.)+[a-zA-Z]{2,}$`)
+
+// ValidateEmail validates if a string is a valid email address
+func ValidateEmail(email string) error {
+	if !emailRegex.MatchString(email) {
+		return fmt.Errorf("invalid email address format: %s", email)
+	}
+	return nil
+}
+
+// phoneSeparators are stripped from phone numbers before validation
+var phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// e164Regex matches an E.164 number: a plus sign, a country code and up to 15 digits
+var e164Regex = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// localPhoneRegex matches a national number without the country code
+var localPhoneRegex = regexp.MustCompile(`^[0-9]{7,15}$`)
+
+// ValidatePhone validates if a string is a phone number, with or without the country code
+func ValidatePhone(phone string) error {
+	clean := phoneSeparators.Replace(phone)
+	if !e164Regex.MatchString(clean) && !localPhoneRegex.MatchString(clean) {
+		return fmt.Errorf("invalid phone number format: %s", phone)
+	}
+	return nil
+}
+
+// ValidateE164 validates if a string is an international number in E.164 format
+func ValidateE164(phone string) error {
+	if !e164Regex.MatchString(phoneSeparators.Replace(phone)) {
+		return fmt.Errorf("invalid E.164 phone number: %s (expected e.g. +14155552671)", phone)
+	}
+	return nil
+}
+
+// ValidateURL validates if a string is an absolute http or https URL
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL: %s", rawURL)
+	}
+	return nil
+}
+
+// ValidateCIDR validates if a string is an IP network such as 192.0.2.0/24
+func ValidateCIDR(cidr string) error {
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return fmt.Errorf("invalid CIDR range: %s", cidr)
+	}
+	return nil
+}
+
+// asnRegex matches an autonomous system number such as AS15169
+var asnRegex = regexp.MustCompile(`^(?i)AS[0-9]{1,10}$`)
+
+// ValidateASN validates if a string is an autonomous system number
+func ValidateASN(asn string) error {
+	if !asnRegex.MatchString(asn) {
+		return fmt.Errorf("invalid ASN: %s (expected e.g. AS15169)", asn)
+	}
+	return nil
+}
+
+// hashTypes maps hex digest lengths to hash names
+var hashTypes = map[int]string{
+	32:  "md5",
+	40:  "sha1",
+	64:  "sha256",
+	128: "sha512",
+}
+
+// hexRegex matches a hexadecimal string
+var hexRegex = regexp.MustCompile(`^[a-fA-F0-9]+$`)
+
+// HashType returns the hash algorithm matching a hex digest, such as "sha256"
+func HashType(hash string) (string, error) {
+	name, ok := hashTypes[len(hash)]
+	if !ok || !hexRegex.MatchString(hash) {
+		return "", fmt.Errorf("invalid hash: %s (expected an MD5, SHA-1, SHA-256 or SHA-512 hex digest)", hash)
+	}
+	return name, nil
+}
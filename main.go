@@ -1,25 +1,67 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
 
 	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/opsec"
 	"github.com/malika/osint-master/internal/output"
-	"github.com/malika/osint-master/pkg/domain"
-	"github.com/malika/osint-master/pkg/emaillookup"
-	"github.com/malika/osint-master/pkg/iplookup"
-	"github.com/malika/osint-master/pkg/namelookup"
+	"github.com/malika/osint-master/pkg/lookup"
 	"github.com/malika/osint-master/pkg/pdfgen"
-	"github.com/malika/osint-master/pkg/phonelookup"
-	"github.com/malika/osint-master/pkg/username"
+	"github.com/malika/osint-master/pkg/pivot"
+	"github.com/malika/osint-master/pkg/render"
+	"github.com/malika/osint-master/pkg/report"
+	"github.com/malika/osint-master/pkg/result"
 	"github.com/malika/osint-master/pkg/webserver"
 )
 
 const version = "1.0.0"
 
 func main() {
+	// Subcommands take their own arguments
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		if err := runCacheCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "case" {
+		if err := runCaseCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		if err := runDiffCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		if err := runAuditCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		if err := runWatchCommand(os.Args[2:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Define command-line flags
 	nameFlag := flag.String("n", "", "Search information by full name")
 	ipFlag := flag.String("i", "", "Search information by IP address")
@@ -28,13 +70,36 @@ func main() {
 	emailFlag := flag.String("e", "", "Search information by email address")
 	phoneFlag := flag.String("p", "", "Search information by phone number")
 	outputFlag := flag.String("o", "", "File name to save output")
+	formatFlag := flag.String("format", output.FormatText, "Output format: text, json or ndjson")
 	pdfFlag := flag.String("pdf", "", "Generate PDF report (specify filename)")
 	webFlag := flag.String("web", "", "Start web GUI server (specify port, e.g., 8080)")
+	webHostFlag := flag.String("web-host", webserver.DefaultHost, "Address the web server listens on, 0.0.0.0 exposes it to the network")
 	advancedFlag := flag.Bool("advanced", false, "Use advanced mode (browser automation - slower but more accurate)")
+	consensusFlag := flag.Bool("consensus", false, "Query every IP provider and merge the answers (with -i)")
+	passiveFlag := flag.Bool("passive", false, "Only use third-party data, skip checks that contact the target's infrastructure")
+	dryRunFlag := flag.Bool("dry-run", false, "List every request the lookups would make without sending any")
+	noCacheFlag := flag.Bool("no-cache", false, "Do not read or write the response cache")
+	refreshFlag := flag.Bool("refresh", false, "Ignore cached responses and store fresh ones")
+	recordFlag := flag.String("record", "", "Save every HTTP exchange to this directory as replayable fixtures")
+	replayFlag := flag.String("replay", "", "Answer every HTTP request from fixtures saved with --record, without the network")
+	timeoutFlag := flag.Duration("timeout", 0, "Stop the lookup after this long and print partial results (e.g. 30s)")
+	batchFlag := flag.String("batch", "", "Look up every target in a file, one per line (- reads stdin)")
+	typeFlag := flag.String("type", typeAuto, "Module for --batch targets: auto, ip, domain, email, phone, username or name")
+	workersFlag := flag.Int("workers", 4, "Number of --batch targets looked up at once")
+	outDirFlag := flag.String("out-dir", "", "Write one --batch result file per target to this directory")
+	pivotFlag := flag.Int("pivot", 0, "Follow discovered selectors this many hops deep (0 disables pivoting)")
+	allowFlag := flag.String("allow", "", "Comma-separated domains, CIDRs or selectors --pivot may look up")
+	allowFileFlag := flag.String("allow-file", "", "File of --pivot allowlist patterns, one per line")
+	maxLookupsFlag := flag.Int("max-lookups", pivot.DefaultMaxLookups, "Most lookups a --pivot run makes")
+	graphFlag := flag.String("graph", "", "Export the entity graph to a .graphml, .gexf or Maltego .csv file")
+	graphFormatFlag := flag.String("graph-format", "", "Format of --graph: graphml, gexf or maltego (default from the extension)")
+	caseFlag := flag.String("case", "", "Keep every lookup and raw response in this investigation case")
+	scopeFlag := flag.String("scope", "", "Engagement scope file, active checks of targets outside it are refused")
+	scopeExampleFlag := flag.Bool("scope-example", false, "Print an example scope file")
 	setupConfigFlag := flag.Bool("setup-config", false, "Create sample config file for API keys")
 	helpFlag := flag.Bool("help", false, "Display help information")
 
-	flag.Parse()
+	targets, _ := parseArgs(flag.CommandLine, os.Args[1:]) // CommandLine exits on errors
 
 	// Handle setup-config command
 	if *setupConfigFlag {
@@ -45,134 +110,494 @@ func main() {
 		return
 	}
 
-	// Handle web server mode
-	if *webFlag != "" {
-		if err := webserver.StartServer(*webFlag); err != nil {
-			fmt.Printf("Error starting web server: %v\n", err)
-			os.Exit(1)
-		}
+	if *scopeExampleFlag {
+		fmt.Print(sampleScopeFile)
 		return
 	}
 
 	// Show help if requested or no flags provided
-	if *helpFlag || flag.NFlag() == 0 {
+	if *helpFlag || (flag.NFlag() == 0 && len(targets) == 0) {
 		showHelp()
 		return
 	}
 
+	if err := output.ValidateFormat(*formatFlag); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	output.ToolVersion = version
+
 	// Load configuration
 	cfg := config.LoadConfig()
+	cfg.RecordDir, cfg.ReplayDir = *recordFlag, *replayFlag
+	if *scopeFlag != "" {
+		cfg.ScopeFile = *scopeFlag
+	}
+	if err := lookup.Configure(cfg); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer lookup.Close()
+	if cfg.RecordDir != "" {
+		fmt.Fprintf(os.Stderr, "Recording HTTP exchanges in %s, the cache is not used\n", cfg.RecordDir)
+	} else if cfg.ReplayDir != "" {
+		fmt.Fprintf(os.Stderr, "Replaying HTTP exchanges from %s, no request reaches the network\n", cfg.ReplayDir)
+	}
+
+	// Handle web server mode, with the same configuration as a lookup
+	if *webFlag != "" {
+		if err := webserver.StartServer(cfg, *webHostFlag, *webFlag); err != nil {
+			fmt.Printf("Error starting web server: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Validate that at least one search flag is provided
-	if *nameFlag == "" && *ipFlag == "" && *usernameFlag == "" && *domainFlag == "" && *emailFlag == "" && *phoneFlag == "" {
-		fmt.Println("Error: Please provide at least one search option (-n, -i, -u, -d, -e, or -p)")
+	if *batchFlag == "" && len(targets) == 0 && *nameFlag == "" && *ipFlag == "" && *usernameFlag == "" && *domainFlag == "" && *emailFlag == "" && *phoneFlag == "" {
+		fmt.Println("Error: Please provide a target or at least one search option (-n, -i, -u, -d, -e, or -p)")
 		fmt.Println("Use --help for more information")
 		os.Exit(1)
 	}
 
-	// Process based on the flag provided
-	var result string
-	var err error
+	// Ctrl-C or --timeout stop the lookup and keep what was found so far
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *timeoutFlag > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeoutFlag)
+		defer cancel()
+	}
 
-	if *nameFlag != "" {
-		fmt.Printf("Searching for: %s\n", *nameFlag)
-		result, err = namelookup.SearchByName(*nameFlag)
-	} else if *ipFlag != "" {
-		fmt.Printf("Looking up IP: %s\n", *ipFlag)
-		result, err = iplookup.LookupIP(*ipFlag)
-	} else if *usernameFlag != "" {
-		fmt.Printf("Searching for username: %s\n", *usernameFlag)
+	if *noCacheFlag {
+		ctx = httpclient.WithCacheMode(ctx, httpclient.CacheBypass)
+	} else if *refreshFlag {
+		ctx = httpclient.WithCacheMode(ctx, httpclient.CacheRefresh)
+	}
 
-		// Use advanced mode if flag is set
-		if *advancedFlag {
-			result, err = username.AdvancedSearchUsername(*usernameFlag)
-		} else {
-			result, err = username.SearchUsername(*usernameFlag)
+	// A case keeps every lookup of the run, including batch and pivot lookups, a dry run has none
+	runner := lookup.NewRunner(cfg)
+	if *caseFlag != "" && !*dryRunFlag {
+		c, err := openCase(cfg, *caseFlag)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
 		}
-	} else if *domainFlag != "" {
-		fmt.Printf("Enumerating domain: %s\n", *domainFlag)
-		result, err = domain.EnumerateDomain(*domainFlag)
-	} else if *emailFlag != "" {
-		fmt.Printf("Looking up email: %s\n", *emailFlag)
-		result, err = emaillookup.LookupEmailWithConfig(*emailFlag, cfg.HIBPAPIKey)
-	} else if *phoneFlag != "" {
-		fmt.Printf("Looking up phone: %s\n", *phoneFlag)
-		result, err = phonelookup.LookupPhone(*phoneFlag)
+		defer c.Close()
+		recordCase(runner, c)
+	}
+	engagement, err := enforceScope(cfg, runner)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if engagement != nil {
+		defer engagement.Close()
+	}
+	if err := auditLookups(cfg, runner, *caseFlag); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
 	}
 
+	// Batch mode replaces the single-target flags
+	if *batchFlag != "" {
+		opts := batchOptions{
+			Workers: *workersFlag,
+			OutDir:  *outDirFlag,
+			Output:  *outputFlag,
+			Format:  *formatFlag,
+			Lookup:  lookup.Options{Advanced: *advancedFlag, Consensus: *consensusFlag, Passive: *passiveFlag},
+			DryRun:  *dryRunFlag,
+		}
+		if err := runBatchCommand(ctx, runner, *batchFlag, *typeFlag, opts); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Every selector given on the command line is looked up
+	selectors := []selector{
+		{result.ModuleName, *nameFlag},
+		{result.ModuleIP, *ipFlag},
+		{result.ModuleUsername, *usernameFlag},
+		{result.ModuleDomain, *domainFlag},
+		{result.ModuleEmail, *emailFlag},
+		{result.ModulePhone, *phoneFlag},
+	}
+	for _, sel := range selectors {
+		if sel.target == "" {
+			continue
+		}
+		if err := lookup.Validate(sel.module, sel.target); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	graphFormat, err := graphExportFormat(*graphFlag, *graphFormatFlag)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Display results
-	fmt.Println(result)
+	// Positional targets are classified and dispatched to the matching module
+	for _, target := range targets {
+		sel, err := detectSelector(target)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		selectors = append(selectors, sel)
+	}
+	opts := lookup.Options{Advanced: *advancedFlag, Consensus: *consensusFlag, Passive: *passiveFlag}
+
+	// A dry run only lists the requests, pivots are not followed since nothing is found
+	if *dryRunFlag {
+		if err := printPlans(runner, selectors, opts, *formatFlag); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// A pivot run also follows what the selectors reveal and links it in a graph
+	var results []*result.Result
+	var graph *pivot.Graph
+	if *pivotFlag > 0 {
+		allow, err := loadAllowlist(*allowFlag, *allowFileFlag)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		graph, err = runPivot(ctx, runner, selectors, pivot.Options{
+			Depth:      *pivotFlag,
+			MaxLookups: *maxLookupsFlag,
+			Allow:      allow,
+			Lookup:     opts,
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		results = graph.Results
+	} else {
+		results, err = runSelectors(ctx, runner, selectors, opts)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Display results in the requested format
+	var formatted string
+	if graph != nil {
+		formatted, err = output.FormatGraph(*formatFlag, graph)
+	} else {
+		formatted, err = output.Format(*formatFlag, results...)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(formatted)
+	if *formatFlag == output.FormatText {
+		fmt.Println()
+	}
 
 	// Save to file if output flag is provided
 	if *outputFlag != "" {
-		err = output.SaveToFile(*outputFlag, result)
+		if graph != nil {
+			err = output.SaveGraph(*outputFlag, *formatFlag, graph)
+		} else {
+			err = output.SaveResults(*outputFlag, *formatFlag, results...)
+		}
 		if err != nil {
 			fmt.Printf("Error saving to file: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("Data saved in %s\n", *outputFlag)
+		fmt.Fprintf(os.Stderr, "Data saved in %s\n", *outputFlag)
+	}
+
+	// Export the entity graph, built from the results when there was no pivot run
+	if *graphFlag != "" {
+		if graph == nil {
+			graph = pivot.FromResults(results)
+		}
+		if err := exportGraph(*graphFlag, graphFormat, graph); err != nil {
+			fmt.Printf("Error exporting graph: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Entity graph exported to %s\n", *graphFlag)
 	}
 
 	// Generate PDF if pdf flag is provided
 	if *pdfFlag != "" {
-		var pdfErr error
-		if *emailFlag != "" {
-			pdfErr = pdfgen.GenerateEmailPDF(*pdfFlag, *emailFlag, result)
-		} else if *phoneFlag != "" {
-			pdfErr = pdfgen.GeneratePhonePDF(*pdfFlag, *phoneFlag, result)
-		} else if *usernameFlag != "" {
-			pdfErr = pdfgen.GenerateUsernamePDF(*pdfFlag, *usernameFlag, result)
-		} else if *ipFlag != "" {
-			pdfErr = pdfgen.GenerateIPPDF(*pdfFlag, *ipFlag, result)
-		} else if *domainFlag != "" {
-			pdfErr = pdfgen.GenerateDomainPDF(*pdfFlag, *domainFlag, result)
-		} else if *nameFlag != "" {
-			pdfErr = pdfgen.GenerateNamePDF(*pdfFlag, *nameFlag, result)
+		if err := generatePDF(*pdfFlag, results, graph); err != nil {
+			fmt.Printf("Error generating PDF: %v\n", err)
+			os.Exit(1)
 		}
+		fmt.Fprintf(os.Stderr, "PDF report generated: %s\n", *pdfFlag)
+	}
+}
 
-		if pdfErr != nil {
-			fmt.Printf("Error generating PDF: %v\n", pdfErr)
-			os.Exit(1)
+// selector is a target given with one of the -n, -i, -u, -d, -e or -p flags
+type selector struct {
+	module string
+	target string
+}
+
+// runSelectors looks up every selector with a target at the same time
+// A single selector fails the run, with several the failures become report sections
+func runSelectors(ctx context.Context, runner *lookup.Runner, selectors []selector, opts lookup.Options) ([]*result.Result, error) {
+	var active []selector
+	for _, sel := range selectors {
+		if sel.target != "" {
+			active = append(active, sel)
 		}
-		fmt.Printf("PDF report generated: %s\n", *pdfFlag)
 	}
+
+	results := make([]*result.Result, len(active))
+	errs := make([]error, len(active))
+
+	var wg sync.WaitGroup
+	for i, sel := range active {
+		fmt.Fprintf(os.Stderr, "Looking up %s: %s\n", sel.module, sel.target)
+
+		wg.Add(1)
+		go func(i int, sel selector) {
+			defer wg.Done()
+			results[i], errs[i] = runner.Run(ctx, sel.module, sel.target, opts)
+		}(i, sel)
+	}
+	wg.Wait()
+
+	if len(active) == 1 && errs[0] != nil {
+		return nil, errs[0]
+	}
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			results[i] = result.Failure(active[i].module, active[i].target, err)
+			fmt.Fprintf(os.Stderr, "Error looking up %s: %v\n", active[i].target, err)
+		} else if results[i].Partial {
+			fmt.Fprintf(os.Stderr, "Warning: %s lookup was interrupted, results are partial\n", active[i].target)
+		}
+	}
+	if failed == len(active) {
+		return nil, fmt.Errorf("all %d lookups failed", failed)
+	}
+
+	return results, nil
+}
+
+// runPivot looks up the selectors and pivots on what they reveal
+func runPivot(ctx context.Context, runner *lookup.Runner, selectors []selector, opts pivot.Options) (*pivot.Graph, error) {
+	var seeds []pivot.Seed
+	for _, sel := range selectors {
+		if sel.target != "" {
+			seeds = append(seeds, pivot.Seed{Module: sel.module, Target: sel.target})
+		}
+	}
+
+	graph, err := pivot.NewEngine(runner, opts).Run(ctx, seeds)
+	if err != nil {
+		return nil, err
+	}
+	if len(graph.Results) == 0 {
+		return nil, fmt.Errorf("every lookup failed, nothing to pivot on")
+	}
+	return graph, nil
+}
+
+// loadAllowlist reads pivot allowlist patterns from a comma-separated list and a file
+func loadAllowlist(list, file string) (*pivot.Allowlist, error) {
+	patterns := strings.Split(list, ",")
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read allowlist: %v", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				patterns = append(patterns, line)
+			}
+		}
+	}
+	return pivot.NewAllowlist(patterns), nil
+}
+
+// graphExportFormat checks the --graph format, taking it from the extension unless given
+func graphExportFormat(filename, format string) (string, error) {
+	if filename == "" {
+		return "", nil
+	}
+	if format == "" {
+		return pivot.ExportFormatFor(filename)
+	}
+	for _, f := range pivot.ExportFormats {
+		if f == format {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown graph format: %s (use %s)", format, strings.Join(pivot.ExportFormats, ", "))
+}
+
+// exportGraph writes the graph to filename
+func exportGraph(filename, format string, graph *pivot.Graph) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %v", err)
+	}
+	if err := pivot.Export(f, format, graph); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// generatePDF writes the module report for one result or a combined report for several
+// A pivot graph adds a last section listing every entity
+func generatePDF(filename string, results []*result.Result, graph *pivot.Graph) error {
+	if len(results) > 1 || graph != nil {
+		sections := make([]pdfgen.Section, 0, len(results)+1)
+		for i, res := range results {
+			sections = append(sections, pdfgen.Section{
+				Title:   report.SectionTitle(i, len(results), res),
+				Content: report.SectionText(res),
+			})
+		}
+		if graph != nil {
+			sections = append(sections, pdfgen.Section{Title: "Entity Graph", Content: pivot.Text(graph)})
+		}
+		summary := report.SummaryText(report.Summarize(results))
+		return pdfgen.GenerateReportPDF(filename, fmt.Sprintf("Investigation: %d targets", len(results)), summary, sections)
+	}
+
+	res := results[0]
+	text := render.Text(res)
+	switch res.Module {
+	case result.ModuleEmail:
+		return pdfgen.GenerateEmailPDF(filename, res.Target, text)
+	case result.ModulePhone:
+		return pdfgen.GeneratePhonePDF(filename, res.Target, text)
+	case result.ModuleUsername:
+		return pdfgen.GenerateUsernamePDF(filename, res.Target, text)
+	case result.ModuleIP:
+		return pdfgen.GenerateIPPDF(filename, res.Target, text)
+	case result.ModuleDomain:
+		return pdfgen.GenerateDomainPDF(filename, res.Target, text)
+	case result.ModuleName:
+		return pdfgen.GenerateNamePDF(filename, res.Target, text)
+	}
+	return pdfgen.GeneratePDF(filename, res.Target, text)
 }
 
 func showHelp() {
 	fmt.Println("\nWelcome to osintmaster multi-function Tool")
 	fmt.Printf("Version: %s\n\n", version)
-	fmt.Println("OPTIONS:")
+	fmt.Println("USAGE:")
+	fmt.Println("    osintmaster [options] <target>...   Detect the target type and run the matching lookup")
+	fmt.Println("    osintmaster [options] -e <email> -u <username> ...")
+	fmt.Println("\n    Targets are detected as IP, email, phone (E.164), URL, domain, username or name.")
+	fmt.Println("    CIDR ranges, ASNs and hashes are recognised but have no lookup module yet.")
+	fmt.Println("    Ambiguous targets (e.g. 5551234567: phone or username) prompt for a choice.")
+	fmt.Println("\nOPTIONS:")
 	fmt.Println("    -n  \"Full Name\"        Search information by full name")
 	fmt.Println("    -i  \"IP Address\"       Search information by IP address")
 	fmt.Println("    -u  \"Username\"         Search information by username")
 	fmt.Println("    -d  \"Domain\"           Enumerate subdomains and check for takeover risks")
 	fmt.Println("    -e  \"Email\"            Search information by email address")
 	fmt.Println("    -p  \"Phone Number\"     Search information by phone number")
+	fmt.Println("                           Several of -n, -i, -u, -d, -e and -p run together in one report")
 	fmt.Println("    -o  \"FileName\"         File name to save output")
+	fmt.Println("    --format \"text|json|ndjson\"  Output format for stdout and -o (default text)")
 	fmt.Println("    --pdf \"FileName.pdf\"   Generate professional PDF report")
-	fmt.Println("    --web \"8080\"           Start web GUI server on specified port")
+	fmt.Println("    --web \"8080\"           Start web GUI server on specified port, on localhost only")
+	fmt.Println("    --web-host \"0.0.0.0\"   Listen on another address, the server has no authentication")
 	fmt.Println("    --advanced             Use advanced mode with browser automation (slower)")
+	fmt.Println("    --consensus            Query every IP provider and merge the answers (with -i)")
+	fmt.Println("    --passive              Skip active checks that contact the target, list what was skipped")
+	fmt.Println("    --dry-run              List every request (host, method, URL, API key, active/passive) without sending")
+	fmt.Println("    --no-cache             Do not read or write the response cache")
+	fmt.Println("    --refresh              Ignore cached responses and store fresh ones")
+	fmt.Println("    --record \"fixtures/\"   Save every HTTP exchange as a JSON fixture (API keys redacted)")
+	fmt.Println("    --replay \"fixtures/\"   Answer HTTP requests from --record fixtures, DNS, TLS and browser checks")
+	fmt.Println("                           still run unless --passive is given")
+	fmt.Println("    --timeout \"30s\"        Stop after this long and print partial results")
+	fmt.Println("    --batch \"targets.txt\"  Look up every target in a file, one per line (- reads stdin)")
+	fmt.Println("    --type \"auto\"          Module for --batch targets (auto, ip, domain, email, phone, username, name)")
+	fmt.Println("    --workers 4            Number of --batch targets looked up at once")
+	fmt.Println("    --out-dir \"results/\"   Write one file per --batch target instead of an NDJSON stream")
+	fmt.Println("    --pivot 2              Look up discovered usernames, domains, IPs and owners this many hops deep")
+	fmt.Println("    --allow \"example.com\"  Only pivot to these domains, CIDRs or selectors (comma-separated)")
+	fmt.Println("    --allow-file \"scope\"   Read --pivot allowlist patterns from a file, one per line")
+	fmt.Printf("    --max-lookups %d       Most lookups a --pivot run makes\n", pivot.DefaultMaxLookups)
+	fmt.Println("    --graph \"case.gexf\"    Export the entity graph as GraphML (.graphml), GEXF (.gexf) or Maltego CSV (.csv)")
+	fmt.Println("    --graph-format \"gexf\"  Format of --graph when the extension does not tell (graphml, gexf, maltego)")
+	fmt.Println("    --case \"acme-ir-42\"     Keep every lookup and raw provider response in an investigation case")
+	fmt.Println("    --scope \"scope.yaml\"   Refuse active checks of targets outside the engagement scope, log every check")
+	fmt.Println("    --scope-example        Print an example scope file")
 	fmt.Println("    --setup-config         Create sample API configuration file")
 	fmt.Println("    --help                 Display this help message")
 	fmt.Println("\nEXAMPLES:")
+	fmt.Println("    osintmaster 8.8.8.8                                       (Detected as an IP)")
+	fmt.Println("    osintmaster john@example.com +14155552671 --pdf case.pdf  (Detected email and phone)")
 	fmt.Println("    osintmaster -n \"John Doe\" -o result.txt")
 	fmt.Println("    osintmaster -i 8.8.8.8 -o ip_info.txt")
+	fmt.Println("    osintmaster -i 8.8.8.8 --consensus                       (Cross-check providers)")
 	fmt.Println("    osintmaster -u \"@username\" -o user_search.txt")
 	fmt.Println("    osintmaster -u \"@username\" --advanced -o user_search.txt  (Advanced mode)")
 	fmt.Println("    osintmaster -d \"example.com\" -o domain_info.txt")
+	fmt.Println("    osintmaster -d \"example.com\" --timeout 1m              (Partial results after 1m)")
 	fmt.Println("    osintmaster -e \"email@example.com\" -o email_info.txt")
 	fmt.Println("    osintmaster -e \"email@example.com\" --pdf report.pdf      (PDF report)")
+	fmt.Println("    osintmaster -i 8.8.8.8 --format json | jq .results[0].data   (JSON output)")
 	fmt.Println("    osintmaster -p \"+1234567890\" -o phone_info.txt")
+	fmt.Println("    osintmaster -e \"email@example.com\" -u johndoe -p \"+1234567890\" --pdf case.pdf  (Combined report)")
+	fmt.Println("    osintmaster --batch ips.txt --type ip -o ips.ndjson       (Batch lookup)")
+	fmt.Println("    cat iocs.txt | osintmaster --batch - --out-dir results/   (Auto-detected targets)")
+	fmt.Println("    osintmaster -e john@acme.com --pivot 2 --allow acme.com --format json  (Entity graph)")
+	fmt.Println("    osintmaster -d example.com --graph example.gexf          (Open in Gephi)")
 	fmt.Println("    osintmaster --web 8080                                    (Start web GUI)")
+	fmt.Println("    osintmaster -e \"email@example.com\" --refresh           (Bypass cached answers)")
+	fmt.Println("    osintmaster -d acme.com --case acme-ir-42                (Keep the lookup in a case)")
+	fmt.Println("    osintmaster -d acme.com --scope acme-scope.yaml          (Stay inside the engagement)")
+	fmt.Println("    osintmaster -d acme.com --passive                        (Certificate logs only, no DNS or TLS)")
+	fmt.Println("    osintmaster -p +254712345678 --dry-run                   (See which providers would be asked)")
+	fmt.Println("    osintmaster -i 8.8.8.8 --record fixtures/ip              (Save the provider responses)")
+	fmt.Println("    osintmaster -i 8.8.8.8 --replay fixtures/ip              (Run again offline, exactly)")
 	fmt.Println("\nCONFIGURATION:")
 	fmt.Println("    osintmaster --setup-config         Create API config file")
 	fmt.Println("    Config file location: ~/.osintmaster/.env")
 	fmt.Println("    Add API keys to unlock full features (HIBP, phone lookup, etc.)")
+	fmt.Println("\nCACHE:")
+	fmt.Println("    Responses are cached under ~/.osintmaster/cache to save API quota")
+	fmt.Println("    osintmaster cache stats            Show cached responses per provider")
+	fmt.Println("    osintmaster cache purge            Remove every cached response")
+	fmt.Println("    osintmaster cache purge --expired  Remove only expired responses")
+	fmt.Println("\nCASES:")
+	fmt.Println("    Lookups run with --case are kept under ~/.osintmaster/cases")
+	fmt.Println("    osintmaster case list                      List cases and when they were last used")
+	fmt.Println("    osintmaster case show acme-ir-42 [id]      List the lookups of a case, or show one")
+	fmt.Println("    osintmaster case search acme-ir-42 query   Find lookups whose target or findings match")
+	fmt.Println("    osintmaster case export acme-ir-42 -o acme.json  Export results and raw responses")
+	fmt.Println("    osintmaster diff acme.com [--case acme-ir-42]    Show what changed since the previous run")
+	fmt.Println("    osintmaster diff acme.com --case acme-ir-42 --with 3  Compare the latest run with run #3")
+	fmt.Println("\nWATCH:")
+	fmt.Println("    osintmaster watch --config watch.yaml   Re-run lookups on a schedule, post changes to webhooks")
+	fmt.Println("    osintmaster watch --config watch.yaml --once  Check every watch once and exit")
+	fmt.Println("    osintmaster watch --example             Print an example watch file")
+	fmt.Println("\nAUDIT LOG:")
+	fmt.Println("    Every lookup and outbound request is appended to ~/.osintmaster/audit/audit.jsonl with")
+	fmt.Println("    the operator (OSINT_OPERATOR, default the OS user) and case, each entry chained by hash")
+	fmt.Println("    osintmaster audit verify [--dir path]   Detect edited or removed entries, print the head hash")
+	fmt.Println("\nPASSIVE MODE:")
+	fmt.Println("    --passive only asks third parties about a target. These active checks are skipped:")
+	for _, c := range opsec.Checks() {
+		fmt.Printf("    %-20s %-16s %s\n", c.Name, strings.Join(c.Modules, ", "), c.Description)
+	}
 	fmt.Println("\nETHICAL NOTICE:")
 	fmt.Println("    This tool is for EDUCATIONAL PURPOSES ONLY.")
 	fmt.Println("    Always obtain permission before gathering information.")
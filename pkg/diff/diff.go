@@ -0,0 +1,230 @@
+// Package diff compares two lookups of the same target and reports what changed
+// It works on findings, so stored results compare the same way as fresh ones
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Change kinds
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is one difference between two runs
+type Change struct {
+	Kind    string `json:"kind"`
+	Field   string `json:"field"`
+	Subject string `json:"subject,omitempty"` // the subdomain a per-subdomain value belongs to
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new,omitempty"`
+	Source  string `json:"source,omitempty"`
+}
+
+// Diff is the outcome of comparing two runs of a target
+type Diff struct {
+	Module  string    `json:"module"`
+	Target  string    `json:"target"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Partial bool      `json:"partial,omitempty"` // a run was cut short or skipped checks, removals may not be real
+	Changes []Change  `json:"changes"`
+}
+
+// Empty reports whether nothing changed
+func (d *Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// setFields hold any number of values, they only have additions and removals
+// Other fields with one value in each run are compared as a single value
+var setFields = map[string]bool{
+	"subdomain":          true,
+	"breach":             true,
+	"social_account":     true,
+	"profile":            true,
+	"username_variation": true,
+	"messaging_platform": true,
+}
+
+// subdomainFields are reported once per subdomain by the domain module
+// Their values are only compared for subdomains present in both runs
+var subdomainFields = map[string]func(result.Finding) (subject, value string){
+	"ip":            rawSubject,
+	"ssl_cert":      prefixSubject,
+	"takeover_risk": prefixSubject,
+}
+
+// rawSubject reads the subdomain from the finding's Raw value
+func rawSubject(f result.Finding) (string, string) {
+	subject, _ := f.Raw.(string)
+	return subject, f.Value
+}
+
+// prefixSubject splits a "subdomain: value" finding
+func prefixSubject(f result.Finding) (string, string) {
+	subject, value, ok := strings.Cut(f.Value, ": ")
+	if !ok {
+		return "", f.Value
+	}
+	return subject, value
+}
+
+// group is the findings of one field, or of one field of one subdomain
+type group struct {
+	field   string
+	subject string
+}
+
+// run is the findings of a result grouped for comparison
+type run struct {
+	order      []group
+	values     map[group][]result.Finding
+	subdomains map[string]bool
+}
+
+// newRun groups the findings of res, keeping the first finding of each value
+func newRun(res *result.Result) *run {
+	r := &run{values: make(map[group][]result.Finding), subdomains: make(map[string]bool)}
+	for _, f := range res.Findings {
+		g := group{field: f.Field}
+		if split, ok := subdomainFields[f.Field]; ok {
+			g.subject, f.Value = split(f)
+		}
+		f.Value = strings.TrimSpace(f.Value)
+		if f.Field == "subdomain" {
+			r.subdomains[strings.ToLower(f.Value)] = true
+		}
+
+		if _, ok := r.values[g]; !ok {
+			r.order = append(r.order, g)
+		}
+		if indexOf(r.values[g], f.Value) < 0 {
+			r.values[g] = append(r.values[g], f)
+		}
+	}
+	return r
+}
+
+// sameSkipped reports whether two runs skipped the same active checks, e.g. both ran with --passive
+func sameSkipped(earlier, later *result.Result) bool {
+	if len(earlier.Skipped) != len(later.Skipped) {
+		return false
+	}
+	checks := make(map[string]bool, len(earlier.Skipped))
+	for _, s := range earlier.Skipped {
+		checks[s.Check] = true
+	}
+	for _, s := range later.Skipped {
+		if !checks[s.Check] {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare returns the changes from an earlier run of a target to a later one
+func Compare(earlier, later *result.Result) *Diff {
+	d := &Diff{
+		Module:  later.Module,
+		Target:  later.Target,
+		From:    earlier.Timestamp,
+		To:      later.Timestamp,
+		Partial: earlier.Partial || later.Partial || !sameSkipped(earlier, later),
+		Changes: make([]Change, 0),
+	}
+
+	before, after := newRun(earlier), newRun(later)
+
+	// Groups keep the order of the later run, those that vanished follow
+	groups := append([]group(nil), after.order...)
+	for _, g := range before.order {
+		if _, ok := after.values[g]; !ok {
+			groups = append(groups, g)
+		}
+	}
+
+	for _, g := range groups {
+		if g.subject != "" {
+			subject := strings.ToLower(g.subject)
+			if !before.subdomains[subject] || !after.subdomains[subject] {
+				continue
+			}
+		}
+		d.Changes = append(d.Changes, compareGroup(g, before.values[g], after.values[g])...)
+	}
+	return d
+}
+
+// compareGroup compares the values of one group in both runs
+func compareGroup(g group, before, after []result.Finding) []Change {
+	if !setFields[g.field] && len(before) == 1 && len(after) == 1 {
+		if before[0].Value == after[0].Value {
+			return nil
+		}
+		return []Change{{Kind: Changed, Field: g.field, Subject: g.subject, Old: before[0].Value, New: after[0].Value, Source: after[0].Source}}
+	}
+
+	var changes []Change
+	for _, f := range after {
+		if indexOf(before, f.Value) < 0 {
+			changes = append(changes, Change{Kind: Added, Field: g.field, Subject: g.subject, New: f.Value, Source: f.Source})
+		}
+	}
+	for _, f := range before {
+		if indexOf(after, f.Value) < 0 {
+			changes = append(changes, Change{Kind: Removed, Field: g.field, Subject: g.subject, Old: f.Value, Source: f.Source})
+		}
+	}
+	return changes
+}
+
+// indexOf returns the position of the finding with value, -1 when there is none
+func indexOf(findings []result.Finding, value string) int {
+	for i, f := range findings {
+		if f.Value == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// Text renders a diff as one line per change: + added, - removed, ~ changed
+func Text(d *Diff) string {
+	var sb strings.Builder
+
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+	sb.WriteString(fmt.Sprintf("CHANGES FOR %s %s (%s -> %s):\n", strings.ToUpper(d.Module), d.Target,
+		d.From.Local().Format("2006-01-02 15:04"), d.To.Local().Format("2006-01-02 15:04")))
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+
+	if d.Empty() {
+		sb.WriteString("  No changes\n")
+	}
+	for _, c := range d.Changes {
+		field := c.Field
+		if c.Subject != "" {
+			field += " of " + c.Subject
+		}
+
+		switch c.Kind {
+		case Added:
+			sb.WriteString(fmt.Sprintf("  + %s: %s (%s)\n", field, c.New, c.Source))
+		case Removed:
+			sb.WriteString(fmt.Sprintf("  - %s: %s\n", field, c.Old))
+		default:
+			sb.WriteString(fmt.Sprintf("  ~ %s: %s -> %s (%s)\n", field, c.Old, c.New, c.Source))
+		}
+	}
+
+	if d.Partial {
+		sb.WriteString("\n⚠️  One of the runs was cut short or skipped checks, removed values may still exist\n")
+	}
+	return sb.String()
+}
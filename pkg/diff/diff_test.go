@@ -0,0 +1,193 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// sub is a subdomain as the domain module reports it
+type sub struct {
+	name string
+	ip   string
+	cert string
+}
+
+// domainRun builds a domain result the way the domain module fills its findings
+// Placeholder values such as "Unknown" and "Not checked" produce no finding
+func domainRun(subs ...sub) *result.Result {
+	res := result.New(result.ModuleDomain, "example.com")
+	for _, s := range subs {
+		res.Add("subdomain", s.name, "crt.sh", result.ConfidenceHigh, nil)
+		if s.ip != "Unknown" {
+			res.Add("ip", s.ip, "dns", result.ConfidenceHigh, s.name)
+		}
+		if s.cert != "" && s.cert != "Not checked" {
+			res.Add("ssl_cert", s.name+": "+s.cert, "tls", result.ConfidenceHigh, s.cert)
+		}
+	}
+	return res
+}
+
+// passive marks a run as having skipped the takeover probe
+func passive(res *result.Result) *result.Result {
+	res.Skipped = append(res.Skipped, result.SkippedCheck{Check: "takeover_probe"})
+	return res
+}
+
+func TestCompare(t *testing.T) {
+	www := sub{"www.example.com", "192.0.2.1", "Let's Encrypt"}
+	api := sub{"api.example.com", "192.0.2.2", "Let's Encrypt"}
+	mail := sub{"mail.example.com", "192.0.2.3", "Let's Encrypt"}
+
+	tests := []struct {
+		name        string
+		earlier     *result.Result
+		later       *result.Result
+		want        []Change
+		wantPartial bool
+	}{
+		{
+			name:    "same subdomains in another order",
+			earlier: domainRun(www, api, mail),
+			later:   domainRun(mail, www, api),
+		},
+		{
+			name:    "added and removed subdomains",
+			earlier: domainRun(www, api),
+			later:   domainRun(www, mail),
+			want: []Change{
+				{Kind: Added, Field: "subdomain", New: "mail.example.com", Source: "crt.sh"},
+				{Kind: Removed, Field: "subdomain", Old: "api.example.com", Source: "crt.sh"},
+			},
+		},
+		{
+			name:    "changed ip of a shared subdomain",
+			earlier: domainRun(www, api),
+			later:   domainRun(www, sub{"api.example.com", "192.0.2.20", "Let's Encrypt"}),
+			want: []Change{
+				{Kind: Changed, Field: "ip", Subject: "api.example.com", Old: "192.0.2.2", New: "192.0.2.20", Source: "dns"},
+			},
+		},
+		{
+			name:    "values of a new subdomain are not reported on their own",
+			earlier: domainRun(www),
+			later:   domainRun(www, api),
+			want: []Change{
+				{Kind: Added, Field: "subdomain", New: "api.example.com", Source: "crt.sh"},
+			},
+		},
+		{
+			name:    "unresolved ip removes the old address",
+			earlier: domainRun(www, api),
+			later:   domainRun(www, sub{"api.example.com", "Unknown", "Let's Encrypt"}),
+			want: []Change{
+				{Kind: Removed, Field: "ip", Subject: "api.example.com", Old: "192.0.2.2", Source: "dns"},
+			},
+		},
+		{
+			name:        "unchecked certificate in a passive run",
+			earlier:     domainRun(www),
+			later:       passive(domainRun(sub{"www.example.com", "192.0.2.1", "Not checked"})),
+			want:        []Change{{Kind: Removed, Field: "ssl_cert", Subject: "www.example.com", Old: "Let's Encrypt", Source: "tls"}},
+			wantPartial: true,
+		},
+		{
+			name:    "both runs skipped the same checks",
+			earlier: passive(domainRun(sub{"www.example.com", "192.0.2.1", "Not checked"})),
+			later:   passive(domainRun(sub{"www.example.com", "192.0.2.1", "Not checked"})),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := Compare(tc.earlier, tc.later)
+			got := d.Changes
+			if len(got) == 0 {
+				got = nil
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("changes = %+v, want %+v", got, tc.want)
+			}
+			if d.Empty() != (len(tc.want) == 0) {
+				t.Errorf("Empty() = %v with %d changes", d.Empty(), len(tc.want))
+			}
+			if d.Partial != tc.wantPartial {
+				t.Errorf("Partial = %v, want %v", d.Partial, tc.wantPartial)
+			}
+		})
+	}
+}
+
+func TestSameSkipped(t *testing.T) {
+	tests := []struct {
+		name    string
+		earlier []string
+		later   []string
+		want    bool
+	}{
+		{"none skipped", nil, nil, true},
+		{"same checks in another order", []string{"takeover_probe", "tls_probe"}, []string{"tls_probe", "takeover_probe"}, true},
+		{"one run skipped checks", nil, []string{"takeover_probe"}, false},
+		{"different checks", []string{"takeover_probe"}, []string{"tls_probe"}, false},
+	}
+
+	for _, tc := range tests {
+		earlier, later := result.New(result.ModuleDomain, "example.com"), result.New(result.ModuleDomain, "example.com")
+		for _, c := range tc.earlier {
+			earlier.Skipped = append(earlier.Skipped, result.SkippedCheck{Check: c})
+		}
+		for _, c := range tc.later {
+			later.Skipped = append(later.Skipped, result.SkippedCheck{Check: c})
+		}
+		if got := sameSkipped(earlier, later); got != tc.want {
+			t.Errorf("%s: sameSkipped = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCompareSingleValueField(t *testing.T) {
+	earlier := result.New(result.ModuleIP, "192.0.2.1")
+	earlier.Add("isp", "Example Net", "ipapi", result.ConfidenceHigh, nil)
+	later := result.New(result.ModuleIP, "192.0.2.1")
+	later.Add("isp", "Example Transit", "ipapi", result.ConfidenceHigh, nil)
+	later.Partial = true
+
+	d := Compare(earlier, later)
+	want := []Change{{Kind: Changed, Field: "isp", Old: "Example Net", New: "Example Transit", Source: "ipapi"}}
+	if !reflect.DeepEqual(d.Changes, want) {
+		t.Errorf("changes = %+v, want %+v", d.Changes, want)
+	}
+	if !d.Partial {
+		t.Error("Partial = false, want true when a run was cut short")
+	}
+}
+
+func TestText(t *testing.T) {
+	d := Compare(
+		domainRun(sub{"www.example.com", "192.0.2.1", ""}, sub{"api.example.com", "192.0.2.2", ""}),
+		passive(domainRun(sub{"www.example.com", "192.0.2.9", ""}, sub{"mail.example.com", "192.0.2.3", ""})),
+	)
+	text := Text(d)
+
+	for _, want := range []string{
+		"CHANGES FOR DOMAIN example.com",
+		"  ~ ip of www.example.com: 192.0.2.1 -> 192.0.2.9 (dns)\n",
+		"  + subdomain: mail.example.com (crt.sh)\n",
+		"  - subdomain: api.example.com\n",
+		"removed values may still exist",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text is missing %q:\n%s", want, text)
+		}
+	}
+
+	if text := Text(Compare(domainRun(), domainRun())); !strings.Contains(text, "No changes") {
+		t.Errorf("text for an empty diff is missing \"No changes\":\n%s", text)
+	}
+}
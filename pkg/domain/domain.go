@@ -1,47 +1,96 @@
 package domain
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/audit"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/opsec"
+	"github.com/malika/osint-master/pkg/result"
 )
 
+// crtshTimeout is the timeout for Certificate Transparency queries
+const crtshTimeout = 30 * time.Second
+
+// tlsTimeout bounds the TLS handshake used to read certificates
+const tlsTimeout = 10 * time.Second
+
+// takeoverTimeout bounds each takeover probe, retries included
+const takeoverTimeout = 5 * time.Second
+
+// Provider names and default base URLs, <NAME>_BASE_URL overrides a base URL, e.g. CRT_SH_BASE_URL
+const (
+	crtshName    = "crt.sh"
+	crtshBaseURL = "https://crt.sh"
+)
+
+// crtshURL is the Certificate Transparency search for every name under a domain, filled with the base URL first
+const crtshURL = "%s/?q=%%25.%s&output=json"
+
+// maxSubdomains is the number of subdomains checked
+const maxSubdomains = 10
+
 // Subdomain represents information about a subdomain
 type Subdomain struct {
-	Name        string
-	IP          string
-	SSLCert     string
-	IsTakeover  bool
-	TakeoverMsg string
+	Name        string `json:"name"`
+	IP          string `json:"ip"`
+	SSLCert     string `json:"ssl_cert"`
+	IsTakeover  bool   `json:"is_takeover"`
+	TakeoverMsg string `json:"takeover_msg,omitempty"`
 }
 
 // DomainInfo holds all information about a domain
 type DomainInfo struct {
-	MainDomain string
-	Subdomains []Subdomain
+	MainDomain string      `json:"main_domain"`
+	Subdomains []Subdomain `json:"subdomains"`
+}
+
+// Endpoints are the base URLs of the services a domain lookup queries
+type Endpoints struct {
+	CrtSh string
+}
+
+// NewEndpoints returns the default base URLs with the overrides of cfg
+func NewEndpoints(cfg *config.Config) *Endpoints {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	return &Endpoints{CrtSh: cfg.BaseURL(crtshName, crtshBaseURL)}
+}
+
+// defaultEndpoints are used by EnumerateDomain
+var defaultEndpoints = NewEndpoints(nil)
+
+// Configure rebuilds the default endpoints from configuration
+func Configure(cfg *config.Config) {
+	defaultEndpoints = NewEndpoints(cfg)
 }
 
 // EnumerateDomain enumerates subdomains and checks for takeover risks
-func EnumerateDomain(domain string) (string, error) {
+// Subdomains left unchecked when ctx ends are returned with a partial result
+func EnumerateDomain(ctx context.Context, domain string) (*result.Result, error) {
 	if domain == "" {
-		return "", fmt.Errorf("domain cannot be empty")
+		return nil, fmt.Errorf("domain cannot be empty")
 	}
 
-	// Remove protocol if present
-	domain = strings.TrimPrefix(domain, "http://")
-	domain = strings.TrimPrefix(domain, "https://")
-	domain = strings.TrimSuffix(domain, "/")
+	domain = cleanDomain(domain)
 
-	fmt.Println("\nEnumerating subdomains... This may take a moment.")
+	fmt.Fprintln(os.Stderr, "\nEnumerating subdomains... This may take a moment.")
 
 	// Get subdomains from Certificate Transparency logs
-	subdomains, err := getSubdomainsFromCrtSh(domain)
+	subdomains, err := defaultEndpoints.getSubdomainsFromCrtSh(ctx, domain)
 	if err != nil {
-		return "", fmt.Errorf("failed to enumerate subdomains: %v", err)
+		return nil, fmt.Errorf("failed to enumerate subdomains: %v", err)
 	}
 
 	// Check each subdomain for details
@@ -50,24 +99,73 @@ func EnumerateDomain(domain string) (string, error) {
 		Subdomains: make([]Subdomain, 0),
 	}
 
+	res := result.New(result.ModuleDomain, domain)
+
 	for _, sub := range subdomains {
-		info := checkSubdomain(sub)
+		if res.Interrupted(ctx) {
+			domainInfo.Subdomains = append(domainInfo.Subdomains, uncheckedSubdomain(sub))
+			continue
+		}
+		info := checkSubdomain(ctx, sub)
 		domainInfo.Subdomains = append(domainInfo.Subdomains, info)
 	}
 
-	result := formatDomainInfo(domainInfo)
-	return result, nil
+	addDomainFindings(res, domainInfo)
+	res.Data = domainInfo
+	return res, nil
+}
+
+// cleanDomain removes the protocol and trailing slash of a domain given as a URL
+func cleanDomain(domain string) string {
+	domain = strings.TrimPrefix(domain, "http://")
+	domain = strings.TrimPrefix(domain, "https://")
+	return strings.TrimSuffix(domain, "/")
+}
+
+// Requests declares the requests EnumerateDomain makes for domain, without sending them
+// Subdomains are only known once crt.sh answers, so their checks use a *.domain template
+func Requests(domain string) []opsec.Request {
+	domain = cleanDomain(domain)
+	each := fmt.Sprintf("for each subdomain found on crt.sh (up to %d)", maxSubdomains)
+	subdomain := "*." + domain
+
+	return []opsec.Request{
+		opsec.Declare(crtshName, "", "GET", fmt.Sprintf(crtshURL, defaultEndpoints.CrtSh, domain)),
+		opsec.Declare("dns", "dns_resolve", "DNS", subdomain).If(each + ", A/AAAA and CNAME"),
+		opsec.Declare("tls", "tls_certificate", "TLS", "tls://"+subdomain+":443").If(each),
+		opsec.Declare("takeover", "takeover_probe", "GET", "https://"+subdomain).If("for subdomains whose CNAME points to a hosted service"),
+	}
+}
+
+// addDomainFindings records subdomains, resolved IPs, certificates and takeover risks as findings
+func addDomainFindings(res *result.Result, info *DomainInfo) {
+	for _, sub := range info.Subdomains {
+		res.Add("subdomain", sub.Name, "crt.sh", result.ConfidenceHigh, sub)
+		if sub.IP != "Unknown" {
+			res.Add("ip", sub.IP, "dns", result.ConfidenceHigh, sub.Name)
+		}
+		if sub.SSLCert != "Not found" && sub.SSLCert != "Not checked" && sub.SSLCert != "Out of scope" {
+			res.Add("ssl_cert", fmt.Sprintf("%s: %s", sub.Name, sub.SSLCert), "tls", result.ConfidenceHigh, sub.SSLCert)
+		}
+		if sub.IsTakeover {
+			res.Add("takeover_risk", fmt.Sprintf("%s: %s", sub.Name, sub.TakeoverMsg), "cname", result.ConfidenceMedium, sub.Name)
+		}
+	}
 }
 
 // getSubdomainsFromCrtSh queries crt.sh for subdomains via Certificate Transparency
-func getSubdomainsFromCrtSh(domain string) ([]string, error) {
-	url := fmt.Sprintf("https://crt.sh/?q=%%25.%s&output=json", domain)
+func (e *Endpoints) getSubdomainsFromCrtSh(ctx context.Context, domain string) ([]string, error) {
+	url := fmt.Sprintf(crtshURL, e.CrtSh, domain)
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := client.Get(url)
+	// crt.sh is slow for large domains
+	client := httpclient.WithTimeout(crtshTimeout)
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -104,49 +202,76 @@ func getSubdomainsFromCrtSh(domain string) ([]string, error) {
 		subdomains = append(subdomains, sub)
 	}
 
-	// Limit to first 10 for demo purposes
-	if len(subdomains) > 10 {
-		subdomains = subdomains[:10]
+	// Sorted first so repeated runs keep the same subset and diff only reports real changes
+	sort.Strings(subdomains)
+	if len(subdomains) > maxSubdomains {
+		subdomains = subdomains[:maxSubdomains]
 	}
 
 	return subdomains, nil
 }
 
-// checkSubdomain checks a subdomain for IP, SSL, and takeover risks
-func checkSubdomain(subdomain string) Subdomain {
-	info := Subdomain{
-		Name:   subdomain,
-		IP:     "Unknown",
-		SSLCert: "Not checked",
+// uncheckedSubdomain is a subdomain that was found but not checked
+func uncheckedSubdomain(subdomain string) Subdomain {
+	return Subdomain{
+		Name:       subdomain,
+		IP:         "Unknown",
+		SSLCert:    "Not checked",
 		IsTakeover: false,
 	}
+}
 
-	// Resolve IP address
-	ips, err := net.LookupIP(subdomain)
-	if err == nil && len(ips) > 0 {
-		info.IP = ips[0].String()
+// checkSubdomain checks a subdomain for IP, SSL, and takeover risks
+func checkSubdomain(ctx context.Context, subdomain string) Subdomain {
+	info := uncheckedSubdomain(subdomain)
+
+	// Resolve IP address, the query can reach the target's own name servers
+	if opsec.Allow(ctx, "dns_resolve", result.ModuleDomain, subdomain) == nil {
+		ips, err := net.DefaultResolver.LookupIP(ctx, "ip", subdomain)
+		audit.Connection(ctx, "DNS", subdomain, err)
+		if err == nil && len(ips) > 0 {
+			info.IP = ips[0].String()
+		}
 	}
 
 	// Check SSL certificate
-	info.SSLCert = checkSSLCert(subdomain)
+	info.SSLCert = checkSSLCert(ctx, subdomain)
 
 	// Check for potential subdomain takeover
-	info.IsTakeover, info.TakeoverMsg = checkTakeoverRisk(subdomain)
+	info.IsTakeover, info.TakeoverMsg = checkTakeoverRisk(ctx, subdomain)
 
 	return info
 }
 
 // checkSSLCert checks the SSL certificate validity
-func checkSSLCert(subdomain string) string {
-	conn, err := tls.Dial("tcp", subdomain+":443", &tls.Config{
-		InsecureSkipVerify: true,
-	})
+func checkSSLCert(ctx context.Context, subdomain string) string {
+	// The handshake connects to the target itself
+	if err := opsec.Allow(ctx, "tls_certificate", result.ModuleDomain, subdomain); err != nil {
+		if opsec.Skipped(err) {
+			return "Not checked"
+		}
+		return "Out of scope"
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: tlsTimeout},
+		Config: &tls.Config{
+			InsecureSkipVerify: true,
+		},
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", subdomain+":443")
+	audit.Connection(ctx, "TLS", "tls://"+subdomain+":443", err)
 	if err != nil {
+		// A cancelled dial says nothing about the certificate
+		if ctx.Err() != nil {
+			return "Not checked"
+		}
 		return "Not found"
 	}
 	defer conn.Close()
 
-	certs := conn.ConnectionState().PeerCertificates
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
 	if len(certs) > 0 {
 		expiry := certs[0].NotAfter
 		return fmt.Sprintf("Valid until %s", expiry.Format("2006-01-02"))
@@ -156,9 +281,13 @@ func checkSSLCert(subdomain string) string {
 }
 
 // checkTakeoverRisk checks for potential subdomain takeover vulnerabilities
-func checkTakeoverRisk(subdomain string) (bool, string) {
+func checkTakeoverRisk(ctx context.Context, subdomain string) (bool, string) {
 	// Check CNAME records
-	cname, err := net.LookupCNAME(subdomain)
+	if opsec.Allow(ctx, "dns_resolve", result.ModuleDomain, subdomain) != nil {
+		return false, ""
+	}
+	cname, err := net.DefaultResolver.LookupCNAME(ctx, subdomain)
+	audit.Connection(ctx, "DNS", subdomain, err)
 	if err != nil {
 		return false, ""
 	}
@@ -177,12 +306,21 @@ func checkTakeoverRisk(subdomain string) (bool, string) {
 
 	for pattern, service := range takeoverPatterns {
 		if strings.Contains(cname, pattern) {
+			// The dangling CNAME is still worth reporting when the probe is not allowed
+			if err := opsec.Allow(ctx, "takeover_probe", result.ModuleDomain, subdomain); err != nil {
+				reason := "out of scope"
+				if opsec.Skipped(err) {
+					reason = "passive mode"
+				}
+				return true, fmt.Sprintf("CNAME points to %s, not probed (%s)", service, reason)
+			}
+
 			// Try to access the service
-			client := &http.Client{
-				Timeout: 5 * time.Second,
+			status, err := probeTakeover(ctx, subdomain)
+			if ctx.Err() != nil {
+				return false, ""
 			}
-			resp, err := client.Get("https://" + subdomain)
-			if err != nil || resp.StatusCode == 404 {
+			if err != nil || status == 404 {
 				return true, fmt.Sprintf("CNAME points to %s that may not exist", service)
 			}
 		}
@@ -191,38 +329,16 @@ func checkTakeoverRisk(subdomain string) (bool, string) {
 	return false, ""
 }
 
-// formatDomainInfo formats the domain information into a readable string
-func formatDomainInfo(info *DomainInfo) string {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("Main Domain: %s\n\n", info.MainDomain))
-	sb.WriteString(fmt.Sprintf("Subdomains found: %d\n", len(info.Subdomains)))
-
-	for _, sub := range info.Subdomains {
-		sb.WriteString(fmt.Sprintf("  - %s (IP: %s)\n", sub.Name, sub.IP))
-		sb.WriteString(fmt.Sprintf("    SSL Certificate: %s\n", sub.SSLCert))
-		if sub.IsTakeover {
-			sb.WriteString(fmt.Sprintf("    ⚠️  TAKEOVER RISK: %s\n", sub.TakeoverMsg))
-		}
-	}
-
-	// List takeover risks separately
-	hasRisks := false
-	for _, sub := range info.Subdomains {
-		if sub.IsTakeover {
-			if !hasRisks {
-				sb.WriteString("\n⚠️  Potential Subdomain Takeover Risks:\n")
-				hasRisks = true
-			}
-			sb.WriteString(fmt.Sprintf("  - Subdomain: %s\n", sub.Name))
-			sb.WriteString(fmt.Sprintf("    %s\n", sub.TakeoverMsg))
-			sb.WriteString("    Recommended Action: Verify ownership or remove DNS record\n\n")
-		}
-	}
+// probeTakeover returns the status of https://subdomain
+// The answer is never cached, a claimed service must show up on the next run
+func probeTakeover(ctx context.Context, subdomain string) (int, error) {
+	ctx, cancel := context.WithTimeout(httpclient.WithCacheMode(ctx, httpclient.CacheBypass), takeoverTimeout)
+	defer cancel()
 
-	if !hasRisks {
-		sb.WriteString("\nNo obvious subdomain takeover risks detected.\n")
+	resp, err := httpclient.Get(ctx, "https://"+subdomain)
+	if err != nil {
+		return 0, err
 	}
-
-	return sb.String()
+	resp.Body.Close()
+	return resp.StatusCode, nil
 }
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/internal/providertest"
+)
+
+// standIn starts a stand-in for crt.sh and returns endpoints pointing at it
+func standIn(t *testing.T, resp providertest.Response) (*providertest.Stand, *Endpoints) {
+	t.Helper()
+
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{"/": resp})
+	return stand, &Endpoints{CrtSh: stand.URL}
+}
+
+func TestGetSubdomainsFromCrtSh(t *testing.T) {
+	t.Parallel()
+	stand, ep := standIn(t, providertest.Response{Body: providertest.Payload(t, "crtsh.json")})
+
+	subdomains, err := ep.getSubdomainsFromCrtSh(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Wildcards are dropped and names listed by several certificates appear once
+	sort.Strings(subdomains)
+	want := []string{"example.com", "mail.example.com", "www.example.com"}
+	if !reflect.DeepEqual(subdomains, want) {
+		t.Errorf("subdomains = %v, want %v", subdomains, want)
+	}
+
+	query := stand.Requests()[0].URL.Query()
+	if query.Get("q") != "%.example.com" || query.Get("output") != "json" {
+		t.Errorf("query = %v, want q=%%.example.com and output=json", query)
+	}
+}
+
+func TestGetSubdomainsFromCrtShLimit(t *testing.T) {
+	t.Parallel()
+	var certs, want []string
+	for i := maxSubdomains + 4; i >= 0; i-- {
+		certs = append(certs, `{"name_value": "host`+strings.Repeat("x", i)+`.example.com"}`)
+	}
+	for i := 0; i < maxSubdomains; i++ {
+		want = append(want, "host"+strings.Repeat("x", i)+".example.com")
+	}
+	_, ep := standIn(t, providertest.Response{Body: "[" + strings.Join(certs, ",") + "]"})
+
+	// Every run keeps the same subdomains, otherwise diff reports changes that did not happen
+	for run := 0; run < 5; run++ {
+		subdomains, err := ep.getSubdomainsFromCrtSh(context.Background(), "example.com")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(subdomains, want) {
+			t.Fatalf("run %d: subdomains = %v, want the first %d in order %v", run, subdomains, maxSubdomains, want)
+		}
+	}
+}
+
+func TestGetSubdomainsFromCrtShErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		resp    providertest.Response
+		wantErr string
+	}{
+		{"rate limited", providertest.TooManyRequests, "crt.sh returned status: 429"},
+		{"server error", providertest.Response{Status: http.StatusBadGateway, Body: "<html>502</html>"}, "crt.sh returned status: 502"},
+		{"malformed JSON", providertest.Response{Body: `[{"name_value": "www.example.com"`}, "unexpected EOF"},
+		{"wrong schema", providertest.Response{Body: `{"error": "no results"}`}, "cannot unmarshal object"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, ep := standIn(t, tc.resp)
+
+			_, err := ep.getSubdomainsFromCrtSh(context.Background(), "example.com")
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetSubdomainsFromCrtShEmpty(t *testing.T) {
+	t.Parallel()
+	_, ep := standIn(t, providertest.Response{Body: `[]`})
+
+	subdomains, err := ep.getSubdomainsFromCrtSh(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subdomains) != 0 {
+		t.Errorf("subdomains = %v, want none", subdomains)
+	}
+}
@@ -1,46 +1,33 @@
 package emaillookup
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/malika/osint-master/pkg/result"
 )
 
 // AdvancedLookupEmail performs enhanced email lookup with additional checks
-func AdvancedLookupEmail(email, hibpAPIKey string) (string, error) {
+func AdvancedLookupEmail(ctx context.Context, email, hibpAPIKey string) (*result.Result, error) {
 	// Validate email format
 	if !isValidEmail(email) {
-		return "", fmt.Errorf("invalid email format: %s", email)
+		return nil, fmt.Errorf("invalid email format: %s", email)
 	}
 
-	var result strings.Builder
-	// result.WriteString("⚠️  ADVANCED MODE: Enhanced email analysis\n")
-	// result.WriteString("This mode performs additional checks and takes longer\n")
-	// result.WriteString(strings.Repeat("=", 70) + "\n\n")
-
 	// Perform standard lookup first
 	startTime := time.Now()
-	standardResult, err := LookupEmailWithConfig(email, hibpAPIKey)
+	res, err := LookupEmailWithConfig(ctx, email, hibpAPIKey)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	result.WriteString(standardResult)
-	result.WriteString("\n" + strings.Repeat("-", 70) + "\n")
-	result.WriteString("ADVANCED CHECKS:\n")
-	result.WriteString(strings.Repeat("-", 70) + "\n\n")
-
-	// Additional advanced checks
-	result.WriteString("Enhanced Social Media Discovery:\n")
+	res.Advanced = true
 
 	// Check more platforms in advanced mode
 	username := strings.Split(email, "@")[0]
 	domain := extractDomain(email)
 
-	result.WriteString(fmt.Sprintf("  Username pattern: %s\n", username))
-	result.WriteString(fmt.Sprintf("  Domain: %s\n", domain))
-	result.WriteString("  Checking extended platforms...\n\n")
-
 	// Additional platforms to check in advanced mode
 	advancedPlatforms := []struct {
 		name string
@@ -56,21 +43,17 @@ func AdvancedLookupEmail(email, hibpAPIKey string) (string, error) {
 		{"Twitch", fmt.Sprintf("https://www.twitch.tv/%s", username)},
 	}
 
-	result.WriteString("Extended Platform URLs (manual verification):\n")
 	for _, platform := range advancedPlatforms {
-		result.WriteString(fmt.Sprintf("  - %s: %s\n", platform.name, platform.url))
+		res.AddLink("Extended Platform URLs (manual verification)", platform.name, platform.url)
 	}
 
-	result.WriteString("\n" + strings.Repeat("-", 70) + "\n")
-	result.WriteString("Domain Analysis:\n")
-	result.WriteString(fmt.Sprintf("  MX Records: Check at https://mxtoolbox.com/SuperTool.aspx?action=mx%%3A%s\n", domain))
-	result.WriteString(fmt.Sprintf("  WHOIS: https://who.is/whois/%s\n", domain))
-	result.WriteString(fmt.Sprintf("  Email verification: https://email-checker.net/validate\n"))
+	res.AddLink("Domain Analysis", "MX Records", fmt.Sprintf("https://mxtoolbox.com/SuperTool.aspx?action=mx%%3A%s", domain))
+	res.AddLink("Domain Analysis", "WHOIS", fmt.Sprintf("https://who.is/whois/%s", domain))
+	res.AddLink("Domain Analysis", "Email verification", "https://email-checker.net/validate")
 
-	elapsed := time.Since(startTime)
-	result.WriteString("\n" + strings.Repeat("=", 70) + "\n")
-	result.WriteString(fmt.Sprintf("⏱️  Advanced search completed in %.2f seconds\n", elapsed.Seconds()))
-	result.WriteString("Note: Advanced mode provides extended platform coverage and analysis\n")
+	res.AddNote("Username pattern: %s", username)
+	res.AddNote("Advanced mode provides extended platform coverage and analysis")
 
-	return result.String(), nil
+	res.Elapsed = time.Since(startTime)
+	return res, nil
 }
@@ -1,40 +1,102 @@
 package emaillookup
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"regexp"
 	"strings"
-	"time"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/opsec"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Provider names and default base URLs, <NAME>_BASE_URL overrides a base URL, e.g. EMAILREP_IO_BASE_URL
+const (
+	gravatarName     = "gravatar.com"
+	gravatarBaseURL  = "https://www.gravatar.com"
+	emailRepName     = "emailrep.io"
+	emailRepBaseURL  = "https://emailrep.io"
+	hibpName         = "haveibeenpwned.com"
+	hibpBaseURL      = "https://haveibeenpwned.com"
+	githubName       = "github.com"
+	githubBaseURL    = "https://api.github.com"
+	instagramName    = "instagram.com"
+	instagramBaseURL = "https://www.instagram.com"
 )
 
+// Provider request URLs, filled with the base URL and the target
+const (
+	gravatarURL   = "%s/avatar/%s?d=404" // MD5 of the address
+	emailRepURL   = "%s/%s"
+	hibpURL       = "%s/api/v3/breachedaccount/%s?truncateResponse=false"
+	githubUserURL = "%s/users/%s" // local part of the address
+	instagramURL  = "%s/%s/"      // local part of the address
+)
+
+// Endpoints are the base URLs of the providers an email lookup queries
+type Endpoints struct {
+	Gravatar  string
+	EmailRep  string
+	HIBP      string
+	GitHub    string
+	Instagram string
+}
+
+// NewEndpoints returns the default base URLs with the overrides of cfg
+func NewEndpoints(cfg *config.Config) *Endpoints {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	return &Endpoints{
+		Gravatar:  cfg.BaseURL(gravatarName, gravatarBaseURL),
+		EmailRep:  cfg.BaseURL(emailRepName, emailRepBaseURL),
+		HIBP:      cfg.BaseURL(hibpName, hibpBaseURL),
+		GitHub:    cfg.BaseURL(githubName, githubBaseURL),
+		Instagram: cfg.BaseURL(instagramName, instagramBaseURL),
+	}
+}
+
+// defaultEndpoints are used by LookupEmail
+var defaultEndpoints = NewEndpoints(nil)
+
+// Configure rebuilds the default endpoints from configuration
+func Configure(cfg *config.Config) {
+	defaultEndpoints = NewEndpoints(cfg)
+}
+
 // EmailInfo holds information about an email address
 type EmailInfo struct {
-	Email           string
-	IsValid         bool
-	Domain          string
-	IsDisposable    bool
-	BreachCount     int
-	Breaches        []string
-	GravatarExists  bool
-	GravatarURL     string
-	Reputation      string
-	Suspicious      bool
-	References      int
+	Email          string          `json:"email"`
+	IsValid        bool            `json:"is_valid"`
+	Domain         string          `json:"domain"`
+	IsDisposable   bool            `json:"is_disposable"`
+	BreachCount    int             `json:"breach_count"`
+	Breaches       []string        `json:"breaches"`
+	GravatarExists bool            `json:"gravatar_exists"`
+	GravatarURL    string          `json:"gravatar_url,omitempty"`
+	Reputation     string          `json:"reputation,omitempty"`
+	Suspicious     bool            `json:"suspicious"`
+	References     int             `json:"references"`
+	SocialAccounts []SocialAccount `json:"social_accounts"`
 }
 
 // LookupEmail performs comprehensive email address lookup
-func LookupEmail(email string) (string, error) {
-	return LookupEmailWithConfig(email, "")
+func LookupEmail(ctx context.Context, email string) (*result.Result, error) {
+	return LookupEmailWithConfig(ctx, email, "")
 }
 
 // LookupEmailWithConfig performs email lookup with API key support
-func LookupEmailWithConfig(email, hibpAPIKey string) (string, error) {
+// Checks not started before ctx ends are skipped and the result is marked partial
+func LookupEmailWithConfig(ctx context.Context, email, hibpAPIKey string) (*result.Result, error) {
 	// Validate email format
 	if !isValidEmail(email) {
-		return "", fmt.Errorf("invalid email format: %s", email)
+		return nil, fmt.Errorf("invalid email format: %s", email)
 	}
 
 	email = strings.ToLower(strings.TrimSpace(email))
@@ -49,27 +111,82 @@ func LookupEmailWithConfig(email, hibpAPIKey string) (string, error) {
 	// Check if disposable email
 	info.IsDisposable = isDisposableEmail(info.Domain)
 
+	res := result.New(result.ModuleEmail, email)
+	ep := defaultEndpoints
+
 	// Check Gravatar
-	info.GravatarExists, info.GravatarURL = checkGravatar(email)
+	if !res.Interrupted(ctx) {
+		info.GravatarExists, info.GravatarURL = ep.checkGravatar(ctx, email)
+	}
 
 	// Check email reputation (FREE - no API key needed)
-	checkEmailReputation(email, info)
+	if !res.Interrupted(ctx) {
+		res.AddError(emailRepName, ep.checkEmailReputation(ctx, email, info))
+	}
 
 	// Check Have I Been Pwned (HIBP)
-	breaches, err := checkHIBPWithKey(email, hibpAPIKey)
-	if err == nil {
-		info.Breaches = breaches
-		info.BreachCount = len(breaches)
+	if !res.Interrupted(ctx) {
+		breaches, err := ep.checkHIBPWithKey(ctx, email, hibpAPIKey)
+		if err == nil {
+			info.Breaches = breaches
+			info.BreachCount = len(breaches)
+		}
+		res.AddError(hibpName, err)
 	}
 
 	// Automatically check social media accounts
-	fmt.Println("\nChecking social media accounts...")
-	socialAccounts := checkSocialMediaAccounts(email)
+	if !res.Interrupted(ctx) {
+		fmt.Fprintln(os.Stderr, "\nChecking social media accounts...")
+		info.SocialAccounts = ep.checkSocialMediaAccounts(ctx, email)
+	}
+	res.Interrupted(ctx)
+
+	addEmailFindings(res, info)
+	res.Data = info
+	return res, nil
+}
 
-	// Format output
-	result := formatEmailInfo(info)
-	result += "\n" + formatSocialAccounts(socialAccounts)
-	return result, nil
+// Requests declares the requests LookupEmailWithConfig makes for email, without sending them
+func Requests(email, hibpAPIKey string) []opsec.Request {
+	email = strings.ToLower(strings.TrimSpace(email))
+	username := strings.Split(email, "@")[0]
+	ep := defaultEndpoints
+
+	hibp := opsec.Declare(hibpName, "", "GET", fmt.Sprintf(hibpURL, ep.HIBP, email))
+	if hibpAPIKey != "" {
+		hibp = hibp.WithKey()
+	}
+
+	return []opsec.Request{
+		opsec.Declare(gravatarName, "", "GET", fmt.Sprintf(gravatarURL, ep.Gravatar, gravatarHash(email))),
+		opsec.Declare(emailRepName, "", "GET", fmt.Sprintf(emailRepURL, ep.EmailRep, email)),
+		hibp,
+		opsec.Declare("GitHub", "github_profile", "GET", fmt.Sprintf(githubUserURL, ep.GitHub, username)),
+		opsec.Declare("Instagram", "instagram_profile", "GET", fmt.Sprintf(instagramURL, ep.Instagram, username)),
+	}
+}
+
+// addEmailFindings records every populated EmailInfo field as a finding
+func addEmailFindings(res *result.Result, info *EmailInfo) {
+	res.Add("domain", info.Domain, "format", result.ConfidenceHigh, info.Domain)
+	res.Add("username", strings.Split(info.Email, "@")[0], "format", result.ConfidenceMedium, nil)
+	res.Add("disposable", fmt.Sprintf("%v", info.IsDisposable), "disposable-list", result.ConfidenceMedium, info.IsDisposable)
+	if info.GravatarExists {
+		res.Add("gravatar", info.GravatarURL, "gravatar.com", result.ConfidenceHigh, info.GravatarURL)
+	}
+	if info.Reputation != "" {
+		res.Add("reputation", info.Reputation, "emailrep.io", result.ConfidenceHigh, info.Reputation)
+		res.Add("suspicious", fmt.Sprintf("%v", info.Suspicious), "emailrep.io", result.ConfidenceHigh, info.Suspicious)
+		res.Add("references", fmt.Sprintf("%d", info.References), "emailrep.io", result.ConfidenceHigh, info.References)
+	}
+	for _, breach := range info.Breaches {
+		res.Add("breach", breach, "haveibeenpwned.com", result.ConfidenceHigh, breach)
+	}
+	for _, account := range info.SocialAccounts {
+		if account.Found {
+			res.Add("social_account", account.URL, account.Platform, result.ConfidenceMedium, account)
+		}
+	}
 }
 
 // isValidEmail validates email format using regex
@@ -106,19 +223,11 @@ func isDisposableEmail(domain string) bool {
 }
 
 // checkGravatar checks if email has associated Gravatar
-func checkGravatar(email string) (bool, string) {
-	// Generate MD5 hash of email
-	hash := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(email))))
-	hashStr := fmt.Sprintf("%x", hash)
-
-	gravatarURL := fmt.Sprintf("https://www.gravatar.com/avatar/%s?d=404", hashStr)
+func (e *Endpoints) checkGravatar(ctx context.Context, email string) (bool, string) {
+	hashStr := gravatarHash(email)
 
 	// Check if Gravatar exists
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
-
-	resp, err := client.Get(gravatarURL)
+	resp, err := httpclient.Get(ctx, fmt.Sprintf(gravatarURL, e.Gravatar, hashStr))
 	if err != nil {
 		return false, ""
 	}
@@ -132,35 +241,36 @@ func checkGravatar(email string) (bool, string) {
 	return false, ""
 }
 
+// gravatarHash returns the MD5 hash Gravatar indexes an address by
+func gravatarHash(email string) string {
+	hash := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return fmt.Sprintf("%x", hash)
+}
+
 // checkEmailReputation checks email reputation using EmailRep.io (FREE - no API key needed)
-func checkEmailReputation(email string, info *EmailInfo) {
-	url := fmt.Sprintf("https://emailrep.io/%s", email)
+func (e *Endpoints) checkEmailReputation(ctx context.Context, email string, info *EmailInfo) error {
+	url := fmt.Sprintf(emailRepURL, e.EmailRep, email)
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return
+		return err
 	}
 
-	// EmailRep.io requires User-Agent
-	req.Header.Set("User-Agent", "OSINT-Master-Educational-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return
+		return fmt.Errorf("emailrep.io returned status: %d", resp.StatusCode)
 	}
 
 	var result map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return
+		return err
 	}
 
 	// Parse reputation data
@@ -175,37 +285,34 @@ func checkEmailReputation(email string, info *EmailInfo) {
 	if references, ok := result["references"].(float64); ok {
 		info.References = int(references)
 	}
+
+	return nil
 }
 
 // checkHIBP checks Have I Been Pwned API for data breaches (without API key)
-func checkHIBP(email string) ([]string, error) {
-	return checkHIBPWithKey(email, "")
+func (e *Endpoints) checkHIBP(ctx context.Context, email string) ([]string, error) {
+	return e.checkHIBPWithKey(ctx, email, "")
 }
 
 // checkHIBPWithKey checks Have I Been Pwned API with optional API key
-func checkHIBPWithKey(email, apiKey string) ([]string, error) {
+func (e *Endpoints) checkHIBPWithKey(ctx context.Context, email, apiKey string) ([]string, error) {
 	// HIBP API v3 requires API key for email search
 	// For educational purposes, we'll use the public breach list
 	// In production, get API key from: https://haveibeenpwned.com/API/Key
 
-	url := fmt.Sprintf("https://haveibeenpwned.com/api/v3/breachedaccount/%s?truncateResponse=false", email)
+	url := fmt.Sprintf(hibpURL, e.HIBP, email)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Add required headers
-	req.Header.Set("User-Agent", "OSINT-Master-Educational-Tool")
-
 	// Add API key if provided
 	if apiKey != "" {
 		req.Header.Set("hibp-api-key", apiKey)
-		fmt.Println("Using HIBP API key for breach check...")
+		fmt.Fprintln(os.Stderr, "Using HIBP API key for breach check...")
 	}
 
 	resp, err := client.Do(req)
@@ -222,10 +329,9 @@ func checkHIBPWithKey(email, apiKey string) ([]string, error) {
 	// 401/403 means API key required or invalid
 	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
 		if apiKey == "" {
-			return []string{"API key required - Get yours at: https://haveibeenpwned.com/API/Key"}, nil
-		} else {
-			return []string{"API key invalid - Check your HIBP_API_KEY"}, nil
+			return nil, fmt.Errorf("API key required - Get yours at: https://haveibeenpwned.com/API/Key")
 		}
+		return nil, fmt.Errorf("API key invalid - Check your HIBP_API_KEY")
 	}
 
 	// 200 means breaches found
@@ -249,14 +355,15 @@ func checkHIBPWithKey(email, apiKey string) ([]string, error) {
 
 // SocialAccount represents a social media account
 type SocialAccount struct {
-	Platform string
-	Found    bool
-	URL      string
-	Method   string // How it was detected
+	Platform string `json:"platform"`
+	Found    bool   `json:"found"`
+	URL      string `json:"url,omitempty"`
+	Method   string `json:"method"` // How it was detected, or why it was not checked
+	Skipped  bool   `json:"skipped,omitempty"`
 }
 
 // checkSocialMediaAccounts automatically checks for social media accounts
-func checkSocialMediaAccounts(email string) []SocialAccount {
+func (e *Endpoints) checkSocialMediaAccounts(ctx context.Context, email string) []SocialAccount {
 	accounts := []SocialAccount{}
 
 	// Extract username from email for some platforms
@@ -265,18 +372,34 @@ func checkSocialMediaAccounts(email string) []SocialAccount {
 	// Check various platforms
 	platforms := []struct {
 		name      string
-		checkFunc func(string, string) (bool, string)
+		checkFunc func(context.Context, string, string) (bool, string)
+		active    string // opsec check name of platforms whose check visits the account
 	}{
-		{"Google/Gmail", checkGoogle},
-		{"GitHub", checkGitHub},
-		{"Twitter", checkTwitterByEmail},
-		{"Facebook", checkFacebook},
-		{"LinkedIn", checkLinkedIn},
-		{"Instagram", checkInstagram},
+		{"Google/Gmail", checkGoogle, ""},
+		{"GitHub", e.checkGitHub, "github_profile"},
+		{"Twitter", checkTwitterByEmail, ""},
+		{"Facebook", checkFacebook, ""},
+		{"LinkedIn", checkLinkedIn, ""},
+		{"Instagram", e.checkInstagram, "instagram_profile"},
 	}
 
 	for _, platform := range platforms {
-		found, url := platform.checkFunc(email, username)
+		if ctx.Err() != nil {
+			break
+		}
+
+		if platform.active != "" {
+			if err := opsec.Allow(ctx, platform.active, result.ModuleEmail, email); err != nil {
+				method := "Out of scope"
+				if opsec.Skipped(err) {
+					method = "Passive mode"
+				}
+				accounts = append(accounts, SocialAccount{Platform: platform.name, Skipped: true, Method: method})
+				continue
+			}
+		}
+
+		found, url := platform.checkFunc(ctx, email, username)
 		accounts = append(accounts, SocialAccount{
 			Platform: platform.name,
 			Found:    found,
@@ -289,7 +412,7 @@ func checkSocialMediaAccounts(email string) []SocialAccount {
 }
 
 // checkGoogle checks if email is a Gmail/Google account
-func checkGoogle(email, username string) (bool, string) {
+func checkGoogle(ctx context.Context, email, username string) (bool, string) {
 	// If it's a gmail.com email, account definitely exists
 	if strings.HasSuffix(email, "@gmail.com") {
 		return true, fmt.Sprintf("https://mail.google.com/mail/u/%s", email)
@@ -298,21 +421,17 @@ func checkGoogle(email, username string) (bool, string) {
 }
 
 // checkGitHub checks if email is associated with GitHub
-func checkGitHub(email, username string) (bool, string) {
+func (e *Endpoints) checkGitHub(ctx context.Context, email, username string) (bool, string) {
 	// Try to check GitHub API for user by username (from email)
-	url := fmt.Sprintf("https://api.github.com/users/%s", username)
+	url := fmt.Sprintf(githubUserURL, e.GitHub, username)
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return false, ""
 	}
 
-	req.Header.Set("User-Agent", "OSINT-Master-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return false, ""
@@ -327,37 +446,33 @@ func checkGitHub(email, username string) (bool, string) {
 }
 
 // checkTwitterByEmail checks Twitter
-func checkTwitterByEmail(email, username string) (bool, string) {
+func checkTwitterByEmail(ctx context.Context, email, username string) (bool, string) {
 	// Twitter doesn't allow email-based lookup without API key
 	// Return search URL for manual verification
 	return false, fmt.Sprintf("https://twitter.com/search?q=%s", email)
 }
 
 // checkFacebook checks Facebook
-func checkFacebook(email, username string) (bool, string) {
+func checkFacebook(ctx context.Context, email, username string) (bool, string) {
 	// Facebook requires login for email-based search
 	// Return search URL for manual verification
 	return false, fmt.Sprintf("https://www.facebook.com/search/people/?q=%s", email)
 }
 
 // checkLinkedIn checks LinkedIn
-func checkLinkedIn(email, username string) (bool, string) {
+func checkLinkedIn(ctx context.Context, email, username string) (bool, string) {
 	// LinkedIn requires login for email-based search
 	// Return search URL for manual verification
 	return false, fmt.Sprintf("https://www.linkedin.com/search/results/people/?keywords=%s", email)
 }
 
 // checkInstagram checks Instagram
-func checkInstagram(email, username string) (bool, string) {
+func (e *Endpoints) checkInstagram(ctx context.Context, email, username string) (bool, string) {
 	// Instagram doesn't allow email-based lookup
 	// Try username instead
-	url := fmt.Sprintf("https://www.instagram.com/%s/", username)
-
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	url := fmt.Sprintf(instagramURL, e.Instagram, username)
 
-	resp, err := client.Get(url)
+	resp, err := httpclient.Get(ctx, url)
 	if err != nil {
 		return false, ""
 	}
@@ -369,88 +484,3 @@ func checkInstagram(email, username string) (bool, string) {
 
 	return false, ""
 }
-
-// formatSocialAccounts formats social media account results
-func formatSocialAccounts(accounts []SocialAccount) string {
-	var sb strings.Builder
-
-	sb.WriteString("Social Media Account Detection:\n")
-	sb.WriteString("================================\n\n")
-
-	foundCount := 0
-	for _, account := range accounts {
-		if account.Found {
-			foundCount++
-			sb.WriteString(fmt.Sprintf("✓ %s: FOUND\n", account.Platform))
-			sb.WriteString(fmt.Sprintf("  URL: %s\n", account.URL))
-		} else {
-			sb.WriteString(fmt.Sprintf("✗ %s: Not found (or requires login to verify)\n", account.Platform))
-			if account.URL != "" {
-				sb.WriteString(fmt.Sprintf("  Search: %s\n", account.URL))
-			}
-		}
-		sb.WriteString("\n")
-	}
-
-	sb.WriteString(fmt.Sprintf("Summary: %d accounts found automatically\n", foundCount))
-	sb.WriteString("\nNote: Some platforms require login to search by email.\n")
-	sb.WriteString("      Links provided for manual verification where needed.\n")
-
-	return sb.String()
-}
-
-// formatEmailInfo formats email information into readable string
-func formatEmailInfo(info *EmailInfo) string {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("Email Address: %s\n", info.Email))
-	sb.WriteString(fmt.Sprintf("Domain: %s\n", info.Domain))
-	sb.WriteString(fmt.Sprintf("Valid Format: %v\n", info.IsValid))
-	sb.WriteString(fmt.Sprintf("Disposable Email: %v\n", info.IsDisposable))
-
-	if info.IsDisposable {
-		sb.WriteString("⚠️  Warning: This is a temporary/disposable email service\n")
-	}
-
-	// Email Reputation (FREE API - EmailRep.io)
-	sb.WriteString("\nEmail Reputation (via EmailRep.io - FREE):\n")
-	if info.Reputation != "" {
-		sb.WriteString(fmt.Sprintf("  Reputation: %s\n", info.Reputation))
-		sb.WriteString(fmt.Sprintf("  Suspicious: %v\n", info.Suspicious))
-		if info.References > 0 {
-			sb.WriteString(fmt.Sprintf("  References: %d (times seen in data)\n", info.References))
-		}
-		if info.Suspicious {
-			sb.WriteString("  ⚠️  Warning: Email marked as suspicious by reputation database\n")
-		}
-	} else {
-		sb.WriteString("  Status: No reputation data available\n")
-		sb.WriteString("  Note: This is a free service that may not have all emails\n")
-	}
-
-	sb.WriteString("\nGravatar:\n")
-	if info.GravatarExists {
-		sb.WriteString(fmt.Sprintf("  Found: Yes\n"))
-		sb.WriteString(fmt.Sprintf("  URL: %s\n", info.GravatarURL))
-	} else {
-		sb.WriteString("  Found: No\n")
-	}
-
-	sb.WriteString("\nData Breach Check (Have I Been Pwned):\n")
-	if info.BreachCount == 0 {
-		sb.WriteString("  Status: No breaches found (or API key required)\n")
-		sb.WriteString(fmt.Sprintf("  🔗 Check directly: https://haveibeenpwned.com/account/%s\n", info.Email))
-		sb.WriteString("  Note: Visit the link above to see detailed breach information\n")
-	} else {
-		sb.WriteString(fmt.Sprintf("  Breaches Found: %d\n", info.BreachCount))
-		sb.WriteString("  Breach Names:\n")
-		for _, breach := range info.Breaches {
-			sb.WriteString(fmt.Sprintf("    - %s\n", breach))
-		}
-		sb.WriteString("\n⚠️  WARNING: This email has been found in data breaches!\n")
-		sb.WriteString("  Recommendation: Change passwords immediately\n")
-		sb.WriteString(fmt.Sprintf("  🔗 View details: https://haveibeenpwned.com/account/%s\n", info.Email))
-	}
-
-	return sb.String()
-}
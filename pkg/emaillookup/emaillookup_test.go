@@ -0,0 +1,183 @@
+package emaillookup
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/internal/providertest"
+)
+
+// testEmail is the address looked up by the tests
+const testEmail = "bill@microsoft.com"
+
+// standIn starts a stand-in for every email provider and returns endpoints pointing at it
+func standIn(t *testing.T, routes map[string]providertest.Response) (*providertest.Stand, *Endpoints) {
+	t.Helper()
+
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, routes)
+	return stand, &Endpoints{Gravatar: stand.URL, EmailRep: stand.URL, HIBP: stand.URL, GitHub: stand.URL, Instagram: stand.URL}
+}
+
+func TestCheckGravatar(t *testing.T) {
+	t.Parallel()
+	path := "/avatar/" + gravatarHash(testEmail)
+
+	tests := []struct {
+		name      string
+		resp      providertest.Response
+		wantFound bool
+	}{
+		{"found", providertest.Response{Header: map[string]string{"Content-Type": "image/png"}, Body: "\x89PNG"}, true},
+		{"not found", providertest.Response{Status: http.StatusNotFound}, false},
+		{"rate limited", providertest.TooManyRequests, false},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			stand, ep := standIn(t, map[string]providertest.Response{path: tc.resp})
+
+			found, url := ep.checkGravatar(context.Background(), " Bill@Microsoft.com ")
+			if found != tc.wantFound {
+				t.Fatalf("found = %v, want %v", found, tc.wantFound)
+			}
+			if found && !strings.HasSuffix(url, gravatarHash(testEmail)) {
+				t.Errorf("url = %q, want the avatar of the address", url)
+			}
+			if d := stand.Requests()[0].URL.Query().Get("d"); d != "404" {
+				t.Errorf("d = %q, want 404 so missing avatars are not replaced", d)
+			}
+		})
+	}
+}
+
+func TestCheckEmailReputation(t *testing.T) {
+	t.Parallel()
+	_, ep := standIn(t, map[string]providertest.Response{
+		"/" + testEmail: {Body: providertest.Payload(t, "emailrep.json")},
+	})
+
+	info := &EmailInfo{Email: testEmail}
+	if err := ep.checkEmailReputation(context.Background(), testEmail, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Reputation != "high" || info.Suspicious || info.References != 79 {
+		t.Errorf("reputation = %q, suspicious = %v, references = %d, want high, false, 79",
+			info.Reputation, info.Suspicious, info.References)
+	}
+}
+
+func TestCheckEmailReputationErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		resp    providertest.Response
+		wantErr string
+	}{
+		{"rate limited", providertest.TooManyRequests, "emailrep.io returned status: 429"},
+		{"unauthorized", providertest.Response{Status: http.StatusUnauthorized, Body: `{"status":"fail","reason":"invalid key"}`}, "emailrep.io returned status: 401"},
+		{"malformed JSON", providertest.Response{Body: `{"reputation": "high",`}, "unexpected EOF"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, ep := standIn(t, map[string]providertest.Response{"/" + testEmail: tc.resp})
+
+			info := &EmailInfo{Email: testEmail}
+			err := ep.checkEmailReputation(context.Background(), testEmail, info)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+			}
+			if info.Reputation != "" {
+				t.Errorf("reputation = %q, want none after an error", info.Reputation)
+			}
+		})
+	}
+}
+
+func TestCheckEmailReputationSchemaChange(t *testing.T) {
+	t.Parallel()
+	// Fields of an unexpected type are ignored rather than misread
+	_, ep := standIn(t, map[string]providertest.Response{
+		"/" + testEmail: {Body: `{"reputation": 3, "suspicious": "yes", "references": "79"}`},
+	})
+
+	info := &EmailInfo{Email: testEmail}
+	if err := ep.checkEmailReputation(context.Background(), testEmail, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Reputation != "" || info.Suspicious || info.References != 0 {
+		t.Errorf("info = %+v, want the fields left empty", info)
+	}
+}
+
+func TestCheckHIBPWithKey(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/breachedaccount/" + testEmail
+
+	tests := []struct {
+		name    string
+		apiKey  string
+		resp    providertest.Response
+		want    []string
+		wantErr string
+	}{
+		{name: "breached", apiKey: "key", resp: providertest.Response{Body: providertest.Payload(t, "hibp.json")}, want: []string{"Adobe", "LinkedIn"}},
+		{name: "not breached", apiKey: "key", resp: providertest.Response{Status: http.StatusNotFound}, want: []string{}},
+		{name: "no key", resp: providertest.Response{Status: http.StatusUnauthorized}, wantErr: "API key required"},
+		{name: "invalid key", apiKey: "bad", resp: providertest.Response{Status: http.StatusUnauthorized}, wantErr: "API key invalid"},
+		{name: "rate limited", apiKey: "key", resp: providertest.TooManyRequests, wantErr: "unexpected status code: 429"},
+		{name: "malformed JSON", apiKey: "key", resp: providertest.Response{Body: `[{"Name": "Adobe"}, {"Name":`}, wantErr: "unexpected EOF"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			stand, ep := standIn(t, map[string]providertest.Response{path: tc.resp})
+
+			breaches, err := ep.checkHIBPWithKey(context.Background(), testEmail, tc.apiKey)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(breaches, tc.want) {
+				t.Errorf("breaches = %v, want %v", breaches, tc.want)
+			}
+
+			req := stand.Requests()[0]
+			if key := req.Header.Get("hibp-api-key"); key != tc.apiKey {
+				t.Errorf("hibp-api-key = %q, want %q", key, tc.apiKey)
+			}
+			if req.URL.Query().Get("truncateResponse") != "false" {
+				t.Error("truncateResponse=false is missing, breach names would be cut")
+			}
+		})
+	}
+}
+
+func TestCheckGitHub(t *testing.T) {
+	t.Parallel()
+	_, ep := standIn(t, map[string]providertest.Response{
+		"/users/bill": {Body: `{"login": "bill", "id": 1, "type": "User"}`},
+	})
+
+	if found, url := ep.checkGitHub(context.Background(), testEmail, "bill"); !found || url != "https://github.com/bill" {
+		t.Errorf("checkGitHub(bill) = %v, %q, want the profile", found, url)
+	}
+	if found, _ := ep.checkGitHub(context.Background(), "nobody@example.com", "nobody"); found {
+		t.Error("checkGitHub(nobody) found a profile the stand-in does not have")
+	}
+}
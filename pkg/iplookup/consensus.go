@@ -0,0 +1,336 @@
+package iplookup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// consensusSource is the IPInfo source of a merged consensus answer
+const consensusSource = "consensus"
+
+// coordinateTolerance is the distance in km within which coordinates agree
+const coordinateTolerance = 50.0
+
+// FieldValue is one value reported for a field and the providers that reported it
+type FieldValue struct {
+	Value   string   `json:"value"`
+	Sources []string `json:"sources"`
+}
+
+// FieldConsensus is the merged view of one IPInfo field across providers
+// SingleSource marks a field only one provider reported, its low confidence
+// means the value is unconfirmed, not that providers disagree
+type FieldConsensus struct {
+	Field        string       `json:"field"`
+	Value        string       `json:"value"`
+	Sources      []string     `json:"sources"`
+	Confidence   float64      `json:"confidence"`
+	Conflict     bool         `json:"conflict"`
+	SingleSource bool         `json:"single_source,omitempty"`
+	Alternatives []FieldValue `json:"alternatives,omitempty"`
+}
+
+// ConsensusInfo is the merged answer of every provider that responded
+// The embedded IPInfo holds the winning value of each field
+type ConsensusInfo struct {
+	IPInfo
+	Providers []string         `json:"providers"`
+	Fields    []FieldConsensus `json:"fields"`
+}
+
+// consensusField describes how one IPInfo field is compared and merged
+type consensusField struct {
+	name  string
+	value func(*IPInfo) string
+	same  func(a, b string) bool
+	apply func(dst, src *IPInfo)
+}
+
+var asnPattern = regexp.MustCompile(`(?i)^(?:AS)?(\d+)`)
+
+var consensusFields = []consensusField{
+	{"city", func(i *IPInfo) string { return i.City }, sameText, func(d, s *IPInfo) { d.City = s.City }},
+	{"region", func(i *IPInfo) string { return i.Region }, sameText, func(d, s *IPInfo) { d.Region = s.Region }},
+	{"country", func(i *IPInfo) string { return i.Country }, sameText, func(d, s *IPInfo) { d.Country = s.Country }},
+	{"country_code", func(i *IPInfo) string { return i.CountryCode }, sameText, func(d, s *IPInfo) { d.CountryCode = s.CountryCode }},
+	{"timezone", func(i *IPInfo) string { return i.Timezone }, sameText, func(d, s *IPInfo) { d.Timezone = s.Timezone }},
+	{"isp", func(i *IPInfo) string { return i.ISP }, sameText, func(d, s *IPInfo) { d.ISP = s.ISP }},
+	{"asn", func(i *IPInfo) string { return i.ASN }, sameASN, func(d, s *IPInfo) { d.ASN = s.ASN }},
+	{"coordinates", coordinates, sameCoordinates, func(d, s *IPInfo) { d.Latitude, d.Longitude = s.Latitude, s.Longitude }},
+}
+
+// ConsensusLookupIP queries every enabled provider of the default registry and merges the answers
+func ConsensusLookupIP(ctx context.Context, ip string) (*result.Result, error) {
+	return LookupIPConsensus(ctx, DefaultRegistry(), ip)
+}
+
+// LookupIPConsensus queries every enabled provider of reg concurrently and merges
+// their answers field by field
+// Each field keeps the value most providers agree on, with the providers that
+// reported it, a confidence based on the level of agreement and the
+// conflicting values reported by other providers
+func LookupIPConsensus(ctx context.Context, reg *Registry, ip string) (*result.Result, error) {
+	// Validate IP address
+	if ip == "" {
+		return nil, fmt.Errorf("IP address cannot be empty")
+	}
+
+	ip = strings.TrimSpace(ip)
+	res := result.New(result.ModuleIP, ip)
+
+	providers := reg.Providers()
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no IP lookup providers enabled")
+	}
+	for _, name := range reg.MissingKeys() {
+		res.AddNote("%s skipped: it needs an API key and none is configured", name)
+	}
+
+	infos := make([]*IPInfo, len(providers))
+	errs := make([]error, len(providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider Provider) {
+			defer wg.Done()
+			infos[i], errs[i] = provider.Lookup(ctx, ip)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	// Providers that answered before the deadline still count
+	res.Interrupted(ctx)
+
+	// Keep provider order so ties go to the preferred provider
+	var answered []*IPInfo
+	for i, provider := range providers {
+		if errs[i] != nil || infos[i] == nil {
+			if errs[i] == nil {
+				errs[i] = fmt.Errorf("%s returned no data", provider.Name())
+			}
+			res.AddError(provider.Name(), errs[i])
+			continue
+		}
+		infos[i].Source = provider.Name()
+		answered = append(answered, infos[i])
+	}
+
+	if len(answered) == 0 {
+		// Keep the failures recorded so far, the lookup did not run to the end
+		if res.Partial {
+			return res, nil
+		}
+		return nil, fmt.Errorf("failed to lookup IP address from all providers: %w", errors.Join(errs...))
+	}
+
+	info := mergeIPInfo(ip, answered)
+	addConsensusFindings(res, info)
+	res.Data = info
+
+	return res, nil
+}
+
+// mergeIPInfo groups the values reported for each field and picks the best supported one
+func mergeIPInfo(ip string, infos []*IPInfo) *ConsensusInfo {
+	merged := &ConsensusInfo{
+		IPInfo: IPInfo{IP: ip, Source: consensusSource},
+	}
+
+	for _, info := range infos {
+		merged.Providers = append(merged.Providers, info.Source)
+	}
+
+	for _, field := range consensusFields {
+		type group struct {
+			value FieldValue
+			first *IPInfo
+		}
+
+		var groups []*group
+		reported := 0
+
+		for _, info := range infos {
+			value := field.value(info)
+			if value == "" {
+				continue
+			}
+			reported++
+
+			var match *group
+			for _, g := range groups {
+				if field.same(g.value.Value, value) {
+					match = g
+					break
+				}
+			}
+			if match == nil {
+				match = &group{value: FieldValue{Value: value}, first: info}
+				groups = append(groups, match)
+			}
+			match.value.Sources = append(match.value.Sources, info.Source)
+		}
+
+		if reported == 0 {
+			continue
+		}
+
+		// The largest group wins, earlier groups come from preferred providers
+		best := groups[0]
+		for _, g := range groups[1:] {
+			if len(g.value.Sources) > len(best.value.Sources) {
+				best = g
+			}
+		}
+
+		field.apply(&merged.IPInfo, best.first)
+
+		fc := FieldConsensus{
+			Field:        field.name,
+			Value:        best.value.Value,
+			Sources:      best.value.Sources,
+			Confidence:   agreement(len(best.value.Sources), reported),
+			Conflict:     len(groups) > 1,
+			SingleSource: reported == 1,
+		}
+		for _, g := range groups {
+			if g != best {
+				fc.Alternatives = append(fc.Alternatives, g.value)
+			}
+		}
+
+		merged.Fields = append(merged.Fields, fc)
+	}
+
+	return merged
+}
+
+// agreement scores a value by the share of providers that reported it
+// A value backed by a single provider is never more than low confidence
+func agreement(agree, reported int) float64 {
+	if agree <= 1 {
+		return result.ConfidenceLow
+	}
+	return math.Round(float64(agree)/float64(reported)*100) / 100
+}
+
+// addConsensusFindings records the winning and the conflicting value of every field
+func addConsensusFindings(res *result.Result, info *ConsensusInfo) {
+	var single []string
+	for _, fc := range info.Fields {
+		addConsensusFinding(res, fc.Field, fc.Value, fc.Sources, fc.Confidence, fc)
+		if fc.SingleSource {
+			single = append(single, fc.Field)
+		}
+
+		if !fc.Conflict {
+			continue
+		}
+
+		reported := len(fc.Sources)
+		values := []string{fmt.Sprintf("%s (%s)", fc.Value, strings.Join(fc.Sources, ", "))}
+		for _, alt := range fc.Alternatives {
+			reported += len(alt.Sources)
+			values = append(values, fmt.Sprintf("%s (%s)", alt.Value, strings.Join(alt.Sources, ", ")))
+		}
+		for _, alt := range fc.Alternatives {
+			addConsensusFinding(res, fc.Field, alt.Value, alt.Sources, agreement(len(alt.Sources), reported), alt)
+		}
+
+		res.AddNote("Providers disagree on %s: %s", fc.Field, strings.Join(values, " vs "))
+	}
+
+	if len(single) > 0 {
+		res.AddNote("Only one provider reported %s, low confidence there means unconfirmed, not disputed", strings.Join(single, ", "))
+	}
+}
+
+// addConsensusFinding adds a finding, splitting coordinates into latitude and longitude
+func addConsensusFinding(res *result.Result, field, value string, sources []string, confidence float64, raw interface{}) {
+	source := strings.Join(sources, ",")
+
+	if field != "coordinates" {
+		res.Add(field, value, source, confidence, raw)
+		return
+	}
+
+	lat, lon, ok := parseCoordinates(value)
+	if !ok {
+		return
+	}
+	res.Add("latitude", fmt.Sprintf("%.6f", lat), source, confidence, raw)
+	res.Add("longitude", fmt.Sprintf("%.6f", lon), source, confidence, raw)
+}
+
+// sameText compares names case-insensitively, ignoring punctuation
+func sameText(a, b string) bool {
+	return normalizeText(a) == normalizeText(b)
+}
+
+// normalizeText lowercases a value and drops punctuation and extra spaces
+func normalizeText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '.' || r == ',' || r == '-' || r == '_' {
+			return ' '
+		}
+		return r
+	}, strings.ToLower(s))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// sameASN compares AS numbers, so "AS15169 Google LLC" matches "AS15169"
+func sameASN(a, b string) bool {
+	ma := asnPattern.FindStringSubmatch(strings.TrimSpace(a))
+	mb := asnPattern.FindStringSubmatch(strings.TrimSpace(b))
+	if ma == nil || mb == nil {
+		return sameText(a, b)
+	}
+	return ma[1] == mb[1]
+}
+
+// coordinates formats the location of an IPInfo, empty when unknown
+func coordinates(info *IPInfo) string {
+	if info.Latitude == 0 && info.Longitude == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.4f, %.4f", info.Latitude, info.Longitude)
+}
+
+// parseCoordinates reads a value formatted by coordinates
+func parseCoordinates(value string) (float64, float64, bool) {
+	var lat, lon float64
+	if _, err := fmt.Sscanf(value, "%f, %f", &lat, &lon); err != nil {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// sameCoordinates reports whether two locations are within coordinateTolerance
+func sameCoordinates(a, b string) bool {
+	lat1, lon1, ok1 := parseCoordinates(a)
+	lat2, lon2, ok2 := parseCoordinates(b)
+	if !ok1 || !ok2 {
+		return a == b
+	}
+	return distanceKm(lat1, lon1, lat2, lon2) <= coordinateTolerance
+}
+
+// distanceKm returns the great-circle distance between two points
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+	rad := math.Pi / 180
+
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,220 @@
+package iplookup
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/internal/providertest"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// cities returns one answer per provider, in provider order, with the given city
+func cities(pairs ...string) []*IPInfo {
+	var infos []*IPInfo
+	for i := 0; i < len(pairs); i += 2 {
+		infos = append(infos, &IPInfo{Source: pairs[i], City: pairs[i+1]})
+	}
+	return infos
+}
+
+// fieldOf returns the consensus of one field, nil when no provider reported it
+func fieldOf(info *ConsensusInfo, name string) *FieldConsensus {
+	for i := range info.Fields {
+		if info.Fields[i].Field == name {
+			return &info.Fields[i]
+		}
+	}
+	return nil
+}
+
+func TestMergeIPInfoTieBreaking(t *testing.T) {
+	tests := []struct {
+		name             string
+		infos            []*IPInfo
+		wantValue        string
+		wantSources      []string
+		wantConfidence   float64
+		wantAlternatives int
+	}{
+		{
+			name:           "single answer",
+			infos:          cities("ip-api.com", "Mountain View"),
+			wantValue:      "Mountain View",
+			wantSources:    []string{"ip-api.com"},
+			wantConfidence: result.ConfidenceLow,
+		},
+		{
+			name:           "all agree",
+			infos:          cities("ip-api.com", "Mountain View", "ipinfo.io", "Mountain View", "ipwhois.app", "Mountain View"),
+			wantValue:      "Mountain View",
+			wantSources:    []string{"ip-api.com", "ipinfo.io", "ipwhois.app"},
+			wantConfidence: 1,
+		},
+		{
+			// One against one goes to the provider tried first
+			name:             "one each",
+			infos:            cities("ip-api.com", "Mountain View", "ipinfo.io", "Palo Alto"),
+			wantValue:        "Mountain View",
+			wantSources:      []string{"ip-api.com"},
+			wantConfidence:   result.ConfidenceLow,
+			wantAlternatives: 1,
+		},
+		{
+			name:             "one each, order reversed",
+			infos:            cities("ipinfo.io", "Palo Alto", "ip-api.com", "Mountain View"),
+			wantValue:        "Palo Alto",
+			wantSources:      []string{"ipinfo.io"},
+			wantConfidence:   result.ConfidenceLow,
+			wantAlternatives: 1,
+		},
+		{
+			// A tie between groups goes to the group whose first provider comes first
+			name:             "two each",
+			infos:            cities("ip-api.com", "Mountain View", "ipinfo.io", "Palo Alto", "ipwhois.app", "Palo Alto", "ipapi.co", "Mountain View"),
+			wantValue:        "Mountain View",
+			wantSources:      []string{"ip-api.com", "ipapi.co"},
+			wantConfidence:   0.5,
+			wantAlternatives: 1,
+		},
+		{
+			name:             "majority beats the first provider",
+			infos:            cities("ip-api.com", "Mountain View", "ipinfo.io", "Palo Alto", "ipwhois.app", "Palo Alto"),
+			wantValue:        "Palo Alto",
+			wantSources:      []string{"ipinfo.io", "ipwhois.app"},
+			wantConfidence:   0.67,
+			wantAlternatives: 1,
+		},
+		{
+			// Spelling variants agree, the value of the first provider is kept
+			name:           "spelling variants",
+			infos:          cities("ip-api.com", "St. Louis", "ipinfo.io", "st louis", "ipwhois.app", "ST-LOUIS"),
+			wantValue:      "St. Louis",
+			wantSources:    []string{"ip-api.com", "ipinfo.io", "ipwhois.app"},
+			wantConfidence: 1,
+		},
+		{
+			// Providers without a value do not count towards agreement
+			name:           "missing values",
+			infos:          cities("ip-api.com", "", "ipinfo.io", "Palo Alto", "ipwhois.app", "Palo Alto"),
+			wantValue:      "Palo Alto",
+			wantSources:    []string{"ipinfo.io", "ipwhois.app"},
+			wantConfidence: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := mergeIPInfo("8.8.8.8", tc.infos)
+			fc := fieldOf(merged, "city")
+			if fc == nil {
+				t.Fatal("no consensus for city")
+			}
+			if fc.Value != tc.wantValue || merged.City != tc.wantValue {
+				t.Errorf("city = %q (merged %q), want %q", fc.Value, merged.City, tc.wantValue)
+			}
+			if !reflect.DeepEqual(fc.Sources, tc.wantSources) {
+				t.Errorf("sources = %v, want %v", fc.Sources, tc.wantSources)
+			}
+			if fc.Confidence != tc.wantConfidence {
+				t.Errorf("confidence = %v, want %v", fc.Confidence, tc.wantConfidence)
+			}
+			if len(fc.Alternatives) != tc.wantAlternatives || fc.Conflict != (tc.wantAlternatives > 0) {
+				t.Errorf("alternatives = %v, conflict = %v, want %d", fc.Alternatives, fc.Conflict, tc.wantAlternatives)
+			}
+			// Low confidence from a lone answer is not a disagreement
+			if wantSingle := len(tc.infos) == 1; fc.SingleSource != wantSingle {
+				t.Errorf("single source = %v, want %v", fc.SingleSource, wantSingle)
+			}
+		})
+	}
+}
+
+func TestMergeIPInfoMatching(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     string
+		a, b      IPInfo
+		wantAgree bool
+	}{
+		{"same ASN with and without name", "asn", IPInfo{ASN: "AS15169 Google LLC"}, IPInfo{ASN: "15169"}, true},
+		{"different ASN", "asn", IPInfo{ASN: "AS15169"}, IPInfo{ASN: "AS15170"}, false},
+		{"ASN names without numbers", "asn", IPInfo{ASN: "Google LLC"}, IPInfo{ASN: "google, llc"}, true},
+		{"coordinates within tolerance", "coordinates", IPInfo{Latitude: 37.386, Longitude: -122.0838}, IPInfo{Latitude: 37.4419, Longitude: -122.143}, true},
+		{"coordinates too far apart", "coordinates", IPInfo{Latitude: 37.386, Longitude: -122.0838}, IPInfo{Latitude: 34.0522, Longitude: -118.2437}, false},
+		{"country code case", "country_code", IPInfo{CountryCode: "US"}, IPInfo{CountryCode: "us"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := tc.a, tc.b
+			a.Source, b.Source = "ip-api.com", "ipinfo.io"
+
+			fc := fieldOf(mergeIPInfo("8.8.8.8", []*IPInfo{&a, &b}), tc.field)
+			if fc == nil {
+				t.Fatalf("no consensus for %s", tc.field)
+			}
+			if agree := !fc.Conflict; agree != tc.wantAgree {
+				t.Errorf("agree = %v, want %v: %+v", agree, tc.wantAgree, fc)
+			}
+			// The first provider wins a tie as well as an agreement
+			if fc.Sources[0] != "ip-api.com" {
+				t.Errorf("sources = %v, want ip-api.com first", fc.Sources)
+			}
+		})
+	}
+}
+
+func TestLookupIPConsensusSingleSource(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{
+		"/json/8.8.8.8": {Body: providertest.Payload(t, "ip-api.json")},
+		"/8.8.8.8/json": {Status: http.StatusServiceUnavailable},
+	})
+
+	reg := NewRegistry()
+	reg.Register(&IPAPIProvider{BaseURL: stand.URL})
+	reg.Register(&IPInfoProvider{BaseURL: stand.URL})
+
+	res, err := LookupIPConsensus(context.Background(), reg, "8.8.8.8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info := res.Data.(*ConsensusInfo)
+	for _, fc := range info.Fields {
+		if !fc.SingleSource || fc.Conflict {
+			t.Errorf("%s: single source = %v, conflict = %v, want a single source without conflict", fc.Field, fc.SingleSource, fc.Conflict)
+		}
+	}
+	if len(res.Notes) != 1 || !strings.HasPrefix(res.Notes[0], "Only one provider reported city,") {
+		t.Errorf("notes = %q, want one naming the single source fields", res.Notes)
+	}
+}
+
+func TestLookupIPConsensusAllFail(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{
+		"/json/8.8.8.8": {Status: http.StatusServiceUnavailable},
+		"/8.8.8.8/json": {Body: `not json`},
+	})
+
+	reg := NewRegistry()
+	reg.Register(&IPAPIProvider{BaseURL: stand.URL})
+	reg.Register(&IPInfoProvider{BaseURL: stand.URL})
+
+	res, err := LookupIPConsensus(context.Background(), reg, "8.8.8.8")
+	if err == nil {
+		t.Fatal("expected an error when every provider fails")
+	}
+	if res != nil {
+		t.Errorf("result = %+v, want none", res)
+	}
+	// The error keeps the reason of every provider
+	for _, want := range []string{"ip-api.com returned status: 503", "ipinfo.io returned invalid JSON"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v, want it to contain %q", err, want)
+		}
+	}
+}
@@ -1,11 +1,12 @@
 package iplookup
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
 	"strings"
-	"time"
+
+	"github.com/malika/osint-master/pkg/result"
 )
 
 // IPInfo holds geolocation information about an IP address
@@ -20,429 +21,73 @@ type IPInfo struct {
 	Timezone    string  `json:"timezone"`
 	Latitude    float64 `json:"latitude"`
 	Longitude   float64 `json:"longitude"`
+	Source      string  `json:"source,omitempty"`
+}
+
+// LookupIP performs IP geolocation lookup using the enabled providers of the default registry
+func LookupIP(ctx context.Context, ip string) (*result.Result, error) {
+	return LookupIPWithRegistry(ctx, DefaultRegistry(), ip)
 }
 
-// LookupIP performs IP geolocation lookup using multiple API providers
-func LookupIP(ip string) (string, error) {
+// LookupIPWithRegistry tries each enabled provider of reg in order and
+// returns the first successful answer
+func LookupIPWithRegistry(ctx context.Context, reg *Registry, ip string) (*result.Result, error) {
 	// Validate IP address
 	if ip == "" {
-		return "", fmt.Errorf("IP address cannot be empty")
+		return nil, fmt.Errorf("IP address cannot be empty")
 	}
 
 	ip = strings.TrimSpace(ip)
+	res := result.New(result.ModuleIP, ip)
 
-	// Try multiple APIs for redundancy
-	var info *IPInfo
-	var err error
-
-	// Try ip-api.com first (free, no key required, 45 requests/minute)
-	info, err = lookupIPAPI(ip)
-	if err == nil && info != nil {
-		return formatIPInfo(info), nil
-	}
-
-	// Try ipinfo.io as fallback (free tier available)
-	info, err = lookupIPInfo(ip)
-	if err == nil && info != nil {
-		return formatIPInfo(info), nil
-	}
-
-	// Try ipapi.co as second fallback
-	info, err = lookupIPApiCo(ip)
-	if err == nil && info != nil {
-		return formatIPInfo(info), nil
+	providers := reg.Providers()
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no IP lookup providers enabled")
 	}
-
-	// Try ipwhois.app as last resort
-	info, err = lookupIPWhois(ip)
-	if err == nil && info != nil {
-		return formatIPInfo(info), nil
+	for _, name := range reg.MissingKeys() {
+		res.AddNote("%s skipped: it needs an API key and none is configured", name)
 	}
 
-	return "", fmt.Errorf("failed to lookup IP address from all providers")
-}
-
-// lookupIPAPI queries ip-api.com for IP information
-func lookupIPAPI(ip string) (*IPInfo, error) {
-	url := fmt.Sprintf("http://ip-api.com/json/%s?fields=status,message,country,countryCode,region,city,lat,lon,timezone,isp,org,as,query", ip)
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ip-api.com returned status: %d", resp.StatusCode)
-	}
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	// Check if lookup was successful
-	if status, ok := result["status"].(string); ok && status != "success" {
-		if msg, ok := result["message"].(string); ok {
-			return nil, fmt.Errorf("ip-api error: %s", msg)
+	// Try multiple APIs for redundancy
+	var errs []error
+	for _, provider := range providers {
+		if ctx.Err() != nil {
+			break
 		}
-		return nil, fmt.Errorf("ip-api lookup failed")
-	}
-
-	// Parse response into IPInfo
-	info := &IPInfo{
-		IP: ip,
-	}
-
-	if city, ok := result["city"].(string); ok {
-		info.City = city
-	}
-	if region, ok := result["region"].(string); ok {
-		info.Region = region
-	}
-	if country, ok := result["country"].(string); ok {
-		info.Country = country
-	}
-	if countryCode, ok := result["countryCode"].(string); ok {
-		info.CountryCode = countryCode
-	}
-	if isp, ok := result["isp"].(string); ok {
-		info.ISP = isp
-	}
-	if asn, ok := result["as"].(string); ok {
-		info.ASN = asn
-	}
-	if timezone, ok := result["timezone"].(string); ok {
-		info.Timezone = timezone
-	}
-	if lat, ok := result["lat"].(float64); ok {
-		info.Latitude = lat
-	}
-	if lon, ok := result["lon"].(float64); ok {
-		info.Longitude = lon
-	}
 
-	return info, nil
-}
-
-// lookupIPInfo queries ipinfo.io for IP information
-func lookupIPInfo(ip string) (*IPInfo, error) {
-	url := fmt.Sprintf("https://ipinfo.io/%s/json", ip)
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ipinfo.io returned status: %d", resp.StatusCode)
-	}
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	info := &IPInfo{
-		IP: ip,
-	}
-
-	if city, ok := result["city"].(string); ok {
-		info.City = city
-	}
-	if region, ok := result["region"].(string); ok {
-		info.Region = region
-	}
-	if country, ok := result["country"].(string); ok {
-		info.CountryCode = country
-		// Convert country code to full name
-		info.Country = getCountryName(country)
-	}
-	if org, ok := result["org"].(string); ok {
-		info.ISP = org
-		// Extract ASN if present in org field (format: "AS15169 Google LLC")
-		if strings.Contains(org, "AS") {
-			parts := strings.Fields(org)
-			if len(parts) > 0 && strings.HasPrefix(parts[0], "AS") {
-				info.ASN = parts[0]
-			}
+		info, err := provider.Lookup(ctx, ip)
+		if err == nil && info != nil {
+			info.Source = provider.Name()
+			addIPFindings(res, info)
+			res.Data = info
+			return res, nil
 		}
-	}
-	if timezone, ok := result["timezone"].(string); ok {
-		info.Timezone = timezone
-	}
-	if loc, ok := result["loc"].(string); ok {
-		// loc format: "latitude,longitude"
-		parts := strings.Split(loc, ",")
-		if len(parts) == 2 {
-			fmt.Sscanf(parts[0], "%f", &info.Latitude)
-			fmt.Sscanf(parts[1], "%f", &info.Longitude)
-		}
-	}
-
-	return info, nil
-}
-
-// lookupIPApiCo queries ipapi.co for IP information
-func lookupIPApiCo(ip string) (*IPInfo, error) {
-	url := fmt.Sprintf("https://ipapi.co/%s/json/", ip)
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// ipapi.co requires User-Agent header
-	req.Header.Set("User-Agent", "OSINT-Master-Tool")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ipapi.co returned status: %d", resp.StatusCode)
-	}
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	// Check for error response
-	if errMsg, ok := result["error"].(bool); ok && errMsg {
-		if reason, ok := result["reason"].(string); ok {
-			return nil, fmt.Errorf("ipapi.co error: %s", reason)
+		if err == nil {
+			err = fmt.Errorf("%s returned no data", provider.Name())
 		}
-		return nil, fmt.Errorf("ipapi.co lookup failed")
+		res.AddError(provider.Name(), err)
+		errs = append(errs, err)
 	}
 
-	info := &IPInfo{
-		IP: ip,
+	// Keep the failures recorded so far, the lookup did not run to the end
+	if res.Interrupted(ctx) {
+		return res, nil
 	}
-
-	if city, ok := result["city"].(string); ok {
-		info.City = city
-	}
-	if region, ok := result["region"].(string); ok {
-		info.Region = region
-	}
-	if country, ok := result["country_name"].(string); ok {
-		info.Country = country
-	}
-	if countryCode, ok := result["country_code"].(string); ok {
-		info.CountryCode = countryCode
-	}
-	if org, ok := result["org"].(string); ok {
-		info.ISP = org
-	}
-	if asn, ok := result["asn"].(string); ok {
-		info.ASN = asn
-	}
-	if timezone, ok := result["timezone"].(string); ok {
-		info.Timezone = timezone
-	}
-	if lat, ok := result["latitude"].(float64); ok {
-		info.Latitude = lat
-	}
-	if lon, ok := result["longitude"].(float64); ok {
-		info.Longitude = lon
-	}
-
-	return info, nil
+	return nil, fmt.Errorf("failed to lookup IP address from all providers: %w", errors.Join(errs...))
 }
 
-// lookupIPWhois queries ipwhois.app for IP information
-func lookupIPWhois(ip string) (*IPInfo, error) {
-	url := fmt.Sprintf("https://ipwhois.app/json/%s", ip)
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ipwhois.app returned status: %d", resp.StatusCode)
-	}
-
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	// Check for error or success field
-	if success, ok := result["success"].(bool); ok && !success {
-		if msg, ok := result["message"].(string); ok {
-			return nil, fmt.Errorf("ipwhois.app error: %s", msg)
-		}
-		return nil, fmt.Errorf("ipwhois.app lookup failed")
-	}
-
-	info := &IPInfo{
-		IP: ip,
-	}
-
-	if city, ok := result["city"].(string); ok {
-		info.City = city
-	}
-	if region, ok := result["region"].(string); ok {
-		info.Region = region
-	}
-	if country, ok := result["country"].(string); ok {
-		info.Country = country
-	}
-	if countryCode, ok := result["country_code"].(string); ok {
-		info.CountryCode = countryCode
-	}
-	if isp, ok := result["isp"].(string); ok {
-		info.ISP = isp
-	}
-	if asn, ok := result["asn"].(string); ok {
-		info.ASN = asn
-	}
-	if timezone, ok := result["timezone"].(string); ok {
-		info.Timezone = timezone
-	}
-	if lat, ok := result["latitude"].(string); ok {
-		fmt.Sscanf(lat, "%f", &info.Latitude)
-	}
-	if lon, ok := result["longitude"].(string); ok {
-		fmt.Sscanf(lon, "%f", &info.Longitude)
-	}
-
-	return info, nil
-}
-
-// getCountryName converts country code to full country name
-func getCountryName(code string) string {
-	// Try to get full country name from restcountries.com API
-	url := fmt.Sprintf("https://restcountries.com/v3.1/alpha/%s", code)
-
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		return code
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return code
-	}
-
-	var countries []map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&countries); err != nil {
-		return code
-	}
-
-	if len(countries) > 0 {
-		if name, ok := countries[0]["name"].(map[string]interface{}); ok {
-			if common, ok := name["common"].(string); ok {
-				return common
-			}
-		}
-	}
-
-	return code
-}
-
-// formatIPInfo formats IP information into readable string
-func formatIPInfo(info *IPInfo) string {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("IP Address: %s\n", info.IP))
-	sb.WriteString(strings.Repeat("=", 50) + "\n\n")
-
-	sb.WriteString("Location Information:\n")
-	sb.WriteString(strings.Repeat("-", 50) + "\n")
-
-	if info.City != "" {
-		sb.WriteString(fmt.Sprintf("City:         %s\n", info.City))
-	}
-	if info.Region != "" {
-		sb.WriteString(fmt.Sprintf("Region:       %s\n", info.Region))
-	}
-	if info.Country != "" {
-		sb.WriteString(fmt.Sprintf("Country:      %s", info.Country))
-		if info.CountryCode != "" {
-			sb.WriteString(fmt.Sprintf(" (%s)", info.CountryCode))
-		}
-		sb.WriteString("\n")
-	} else if info.CountryCode != "" {
-		sb.WriteString(fmt.Sprintf("Country:      %s\n", info.CountryCode))
-	}
-	if info.Timezone != "" {
-		sb.WriteString(fmt.Sprintf("Timezone:     %s\n", info.Timezone))
-	}
+// addIPFindings records every populated IPInfo field as a finding
+func addIPFindings(res *result.Result, info *IPInfo) {
+	source := info.Source
+	res.Add("city", info.City, source, result.ConfidenceHigh, info.City)
+	res.Add("region", info.Region, source, result.ConfidenceHigh, info.Region)
+	res.Add("country", info.Country, source, result.ConfidenceHigh, info.Country)
+	res.Add("country_code", info.CountryCode, source, result.ConfidenceHigh, info.CountryCode)
+	res.Add("timezone", info.Timezone, source, result.ConfidenceHigh, info.Timezone)
+	res.Add("isp", info.ISP, source, result.ConfidenceHigh, info.ISP)
+	res.Add("asn", info.ASN, source, result.ConfidenceHigh, info.ASN)
 	if info.Latitude != 0 || info.Longitude != 0 {
-		sb.WriteString(fmt.Sprintf("Coordinates:  %.6f, %.6f\n", info.Latitude, info.Longitude))
-		sb.WriteString(fmt.Sprintf("Map:          https://www.google.com/maps?q=%.6f,%.6f\n", info.Latitude, info.Longitude))
+		res.Add("latitude", fmt.Sprintf("%.6f", info.Latitude), source, result.ConfidenceMedium, info.Latitude)
+		res.Add("longitude", fmt.Sprintf("%.6f", info.Longitude), source, result.ConfidenceMedium, info.Longitude)
 	}
-
-	sb.WriteString("\nNetwork Information:\n")
-	sb.WriteString(strings.Repeat("-", 50) + "\n")
-
-	if info.ISP != "" {
-		sb.WriteString(fmt.Sprintf("ISP:          %s\n", info.ISP))
-	}
-	if info.ASN != "" {
-		sb.WriteString(fmt.Sprintf("ASN:          %s\n", info.ASN))
-	}
-
-	sb.WriteString("\nAdditional Lookup Resources:\n")
-	sb.WriteString(strings.Repeat("-", 50) + "\n")
-	sb.WriteString(fmt.Sprintf("IP-API:       http://ip-api.com/#%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("IPInfo:       https://ipinfo.io/%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("WhoIs:        https://who.is/whois-ip/ip-address/%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("ViewDNS:      https://viewdns.info/iphistory/?domain=%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("DNSChecker:   https://dnschecker.org/ip-location.php?ip=%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("IPVoid:       https://www.ipvoid.com/ip-blacklist-check/\n"))
-
-	sb.WriteString("\nThreat Intelligence:\n")
-	sb.WriteString(strings.Repeat("-", 50) + "\n")
-	sb.WriteString(fmt.Sprintf("AbuseIPDB:    https://www.abuseipdb.com/check/%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("VirusTotal:   https://www.virustotal.com/gui/ip-address/%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("Shodan:       https://www.shodan.io/host/%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("Censys:       https://search.censys.io/hosts/%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("Talos Intel:  https://www.talosintelligence.com/reputation_center/lookup?search=%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("GreyNoise:    https://viz.greynoise.io/ip/%s\n", info.IP))
-
-	sb.WriteString("\nWHOIS & Network Details:\n")
-	sb.WriteString(strings.Repeat("-", 50) + "\n")
-	sb.WriteString(fmt.Sprintf("ARIN WHOIS:   https://search.arin.net/rdap/?query=%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("RIPE:         https://apps.db.ripe.net/db-web-ui/query?searchtext=%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("APNIC:        https://wq.apnic.net/query?searchtext=%s\n", info.IP))
-
-	sb.WriteString("\nReverse DNS & Domain History:\n")
-	sb.WriteString(strings.Repeat("-", 50) + "\n")
-	sb.WriteString(fmt.Sprintf("MXToolbox:    https://mxtoolbox.com/SuperTool.aspx?action=ptr%%3A%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("SecurityTrails: https://securitytrails.com/list/ip/%s\n", info.IP))
-	sb.WriteString(fmt.Sprintf("Robtex:       https://www.robtex.com/ip-lookup/%s\n", info.IP))
-
-	sb.WriteString("\n" + strings.Repeat("=", 50) + "\n")
-	sb.WriteString("Note: Use 'Advanced Mode' for comprehensive security analysis\n")
-	sb.WriteString("      and additional threat intelligence resources.\n")
-
-	return sb.String()
 }
@@ -0,0 +1,191 @@
+package iplookup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"github.com/malika/osint-master/internal/opsec"
+)
+
+// mmdbName is the name of the offline database provider
+const mmdbName = "mmdb"
+
+// mmdbCityRecord is the part of a GeoLite2-City or DB-IP City Lite record we use
+type mmdbCityRecord struct {
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	Country struct {
+		IsoCode string            `maxminddb:"iso_code"`
+		Names   map[string]string `maxminddb:"names"`
+	} `maxminddb:"country"`
+	Location struct {
+		Latitude  float64 `maxminddb:"latitude"`
+		Longitude float64 `maxminddb:"longitude"`
+		TimeZone  string  `maxminddb:"time_zone"`
+	} `maxminddb:"location"`
+}
+
+// mmdbASNRecord is a GeoLite2-ASN or DB-IP ASN Lite record
+type mmdbASNRecord struct {
+	Number       uint   `maxminddb:"autonomous_system_number"`
+	Organization string `maxminddb:"autonomous_system_organization"`
+}
+
+// MMDBProvider reads IP information from local MaxMind or DB-IP .mmdb files
+// No network access is needed, either database path may be empty
+// When one database fails to open the other is still used
+type MMDBProvider struct {
+	CityPath string
+	ASNPath  string
+
+	once    sync.Once
+	city    *maxminddb.Reader
+	asn     *maxminddb.Reader
+	cityErr error
+	asnErr  error
+}
+
+// NewMMDBProvider creates a provider for the given City and ASN databases
+// The files are opened on first lookup
+func NewMMDBProvider(cityPath, asnPath string) *MMDBProvider {
+	return &MMDBProvider{CityPath: cityPath, ASNPath: asnPath}
+}
+
+// Name returns the provider name
+func (p *MMDBProvider) Name() string { return mmdbName }
+
+// RequiresKey reports that local databases need no key
+func (p *MMDBProvider) RequiresKey() bool { return false }
+
+// Priority places the local databases before every online provider
+func (p *MMDBProvider) Priority() int { return 0 }
+
+// Requests returns nothing, the databases are local files
+func (p *MMDBProvider) Requests(ip string) []opsec.Request { return nil }
+
+// Lookup reads the IP from the configured databases
+func (p *MMDBProvider) Lookup(ctx context.Context, ip string) (*IPInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil, fmt.Errorf("invalid IP address: %s", ip)
+	}
+
+	if err := p.open(); err != nil {
+		return nil, err
+	}
+
+	info := &IPInfo{IP: ip}
+	found := false
+	errs := []error{p.cityErr, p.asnErr}
+
+	if p.city != nil {
+		var record mmdbCityRecord
+		_, ok, err := p.city.LookupNetwork(addr, &record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("city database lookup failed: %v", err))
+		} else if ok {
+			found = true
+			info.City = englishName(record.City.Names)
+			if len(record.Subdivisions) > 0 {
+				info.Region = englishName(record.Subdivisions[0].Names)
+			}
+			info.Country = englishName(record.Country.Names)
+			info.CountryCode = record.Country.IsoCode
+			info.Timezone = record.Location.TimeZone
+			info.Latitude = record.Location.Latitude
+			info.Longitude = record.Location.Longitude
+		}
+	}
+
+	if p.asn != nil {
+		var record mmdbASNRecord
+		_, ok, err := p.asn.LookupNetwork(addr, &record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ASN database lookup failed: %v", err))
+		} else if ok {
+			found = true
+			info.ISP = record.Organization
+			if record.Number != 0 {
+				info.ASN = fmt.Sprintf("AS%d", record.Number)
+			}
+		}
+	}
+
+	if !found {
+		if err := errors.Join(errs...); err != nil {
+			return nil, fmt.Errorf("no database record for %s: %w", ip, err)
+		}
+		return nil, fmt.Errorf("no database record for %s", ip)
+	}
+
+	return info, nil
+}
+
+// Close releases the database files
+func (p *MMDBProvider) Close() error {
+	var err error
+	if p.city != nil {
+		err = p.city.Close()
+	}
+	if p.asn != nil {
+		if cerr := p.asn.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// open opens the configured databases once
+// It only fails when no database could be opened
+func (p *MMDBProvider) open() error {
+	p.once.Do(func() {
+		if p.CityPath != "" {
+			var err error
+			if p.city, err = maxminddb.Open(p.CityPath); err != nil {
+				p.city, p.cityErr = nil, fmt.Errorf("failed to open city database: %v", err)
+			}
+		}
+
+		if p.ASNPath != "" {
+			var err error
+			if p.asn, err = maxminddb.Open(p.ASNPath); err != nil {
+				p.asn, p.asnErr = nil, fmt.Errorf("failed to open ASN database: %v", err)
+			}
+		}
+
+		if p.city != nil || p.asn != nil {
+			for _, err := range []error{p.cityErr, p.asnErr} {
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v, using the other database only\n", err)
+				}
+			}
+		}
+	})
+
+	if p.city == nil && p.asn == nil {
+		if err := errors.Join(p.cityErr, p.asnErr); err != nil {
+			return err
+		}
+		return fmt.Errorf("no .mmdb database configured")
+	}
+	return nil
+}
+
+// englishName picks the English name from a localized names map
+func englishName(names map[string]string) string {
+	return names["en"]
+}
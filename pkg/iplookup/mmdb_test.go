@@ -0,0 +1,84 @@
+package iplookup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The fixtures hold one record each for 203.0.113.0/24
+const (
+	cityFixture = "testdata/city.mmdb"
+	asnFixture  = "testdata/asn.mmdb"
+)
+
+var (
+	fixtureCity = IPInfo{
+		IP:          "203.0.113.7",
+		City:        "Springfield",
+		Region:      "Illinois",
+		Country:     "United States",
+		CountryCode: "US",
+		Timezone:    "America/Chicago",
+		Latitude:    39.7817,
+		Longitude:   -89.6501,
+	}
+	fixtureASN = IPInfo{IP: "203.0.113.7", ISP: "Example Transit", ASN: "AS64500"}
+)
+
+func TestMMDBProviderLookup(t *testing.T) {
+	// A file that is not a database, as left behind by an interrupted download
+	broken := filepath.Join(t.TempDir(), "broken.mmdb")
+	if err := os.WriteFile(broken, []byte("not a database"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	both := fixtureCity
+	both.ISP, both.ASN = fixtureASN.ISP, fixtureASN.ASN
+
+	tests := []struct {
+		name    string
+		city    string
+		asn     string
+		ip      string
+		want    *IPInfo // nil when the lookup must fail
+		wantErr string
+	}{
+		{name: "city and ASN", city: cityFixture, asn: asnFixture, ip: "203.0.113.7", want: &both},
+		{name: "city only", city: cityFixture, ip: "203.0.113.7", want: &fixtureCity},
+		{name: "ASN only", asn: asnFixture, ip: "203.0.113.7", want: &fixtureASN},
+		{name: "broken ASN database keeps city data", city: cityFixture, asn: broken, ip: "203.0.113.7", want: &fixtureCity},
+		{name: "missing city database keeps ASN data", city: "testdata/missing.mmdb", asn: asnFixture, ip: "203.0.113.7", want: &fixtureASN},
+		{name: "address not in the databases", city: cityFixture, asn: asnFixture, ip: "198.51.100.1", wantErr: "no database record for 198.51.100.1"},
+		{name: "not in the city database and ASN broken", city: cityFixture, asn: broken, ip: "198.51.100.1", wantErr: "failed to open ASN database"},
+		{name: "no database opens", city: "testdata/missing.mmdb", asn: broken, ip: "203.0.113.7", wantErr: "failed to open city database"},
+		{name: "no database configured", ip: "203.0.113.7", wantErr: "no .mmdb database configured"},
+		{name: "invalid address", city: cityFixture, ip: "not-an-ip", wantErr: "invalid IP address"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewMMDBProvider(tc.city, tc.asn)
+			defer p.Close()
+
+			info, err := p.Lookup(context.Background(), tc.ip)
+			if tc.want == nil {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(info, tc.want) {
+				t.Errorf("info = %+v\nwant   %+v", info, tc.want)
+			}
+		})
+	}
+}
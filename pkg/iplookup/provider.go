@@ -0,0 +1,272 @@
+package iplookup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/opsec"
+)
+
+// Provider is a source of IP geolocation data
+type Provider interface {
+	// Name identifies the provider in results, errors and configuration
+	Name() string
+	// RequiresKey reports whether the provider only works with an API key
+	RequiresKey() bool
+	// Priority orders providers, lower values are tried first
+	Priority() int
+	// Lookup returns geolocation data for an IP address
+	Lookup(ctx context.Context, ip string) (*IPInfo, error)
+	// Requests declares the outbound requests Lookup makes for ip, without sending them
+	Requests(ip string) []opsec.Request
+}
+
+// KeyHolder is implemented by providers that take an API key
+// A provider that requires a key is left out of lookups until HasKey reports one
+type KeyHolder interface {
+	HasKey() bool
+}
+
+// missingKey reports whether p requires an API key that is not configured
+func missingKey(p Provider) bool {
+	if !p.RequiresKey() {
+		return false
+	}
+	k, ok := p.(KeyHolder)
+	return !ok || !k.HasKey()
+}
+
+// Registry holds the providers used by LookupIP
+// Providers can be disabled or reordered without unregistering them
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[string]Provider
+	disabled  map[string]bool
+	order     []string
+}
+
+// NewRegistry creates an empty provider registry
+func NewRegistry() *Registry {
+	return &Registry{
+		providers: make(map[string]Provider),
+		disabled:  make(map[string]bool),
+	}
+}
+
+// Register adds a provider, replacing any provider with the same name
+func (r *Registry) Register(p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.Name()] = p
+}
+
+// Enable re-enables a disabled provider
+func (r *Registry) Enable(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.disabled, name)
+}
+
+// Disable stops a provider from being used without removing it
+func (r *Registry) Disable(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.disabled[name] = true
+}
+
+// SetOrder sets an explicit provider order
+// Providers not listed run afterwards in priority order
+func (r *Registry) SetOrder(names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.order = append([]string(nil), names...)
+}
+
+// Get returns a registered provider by name
+func (r *Registry) Get(name string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Providers returns the enabled providers in lookup order
+// Providers that need an API key without one configured are left out, see MissingKeys
+func (r *Registry) Providers() []Provider {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rank := make(map[string]int, len(r.order))
+	for i, name := range r.order {
+		rank[name] = i
+	}
+
+	providers := make([]Provider, 0, len(r.providers))
+	for name, p := range r.providers {
+		if !r.disabled[name] && !missingKey(p) {
+			providers = append(providers, p)
+		}
+	}
+
+	sort.SliceStable(providers, func(i, j int) bool {
+		ri, iok := rank[providers[i].Name()]
+		rj, jok := rank[providers[j].Name()]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		case providers[i].Priority() != providers[j].Priority():
+			return providers[i].Priority() < providers[j].Priority()
+		default:
+			return providers[i].Name() < providers[j].Name()
+		}
+	})
+
+	return providers
+}
+
+// MissingKeys returns the enabled providers skipped because they need an API key that is not configured
+func (r *Registry) MissingKeys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var names []string
+	for name, p := range r.providers {
+		if !r.disabled[name] && missingKey(p) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes every registered provider that holds resources, such as GeoIP databases
+func (r *Registry) Close() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var err error
+	for _, p := range r.providers {
+		if c, ok := p.(io.Closer); ok {
+			if cerr := c.Close(); cerr != nil && err == nil {
+				err = cerr
+			}
+		}
+	}
+	return err
+}
+
+// Requests declares the requests a lookup of ip makes with the enabled providers
+// A standard lookup only moves on to the next provider when one fails, a consensus lookup asks them all
+func (r *Registry) Requests(ip string, consensus bool) []opsec.Request {
+	ip = strings.TrimSpace(ip)
+
+	var requests []opsec.Request
+	previous := ""
+	for _, p := range r.Providers() {
+		for _, req := range p.Requests(ip) {
+			if !consensus && previous != "" {
+				req = req.If("if " + previous + " fails")
+			}
+			requests = append(requests, req)
+		}
+		previous = p.Name()
+	}
+	return requests
+}
+
+// defaultRegistry is used by LookupIP
+var defaultRegistry = NewDefaultRegistry(nil)
+
+// DefaultRegistry returns the registry used by LookupIP
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Configure rebuilds the default registry from configuration and closes the one it replaces
+func Configure(cfg *config.Config) {
+	previous := defaultRegistry
+	defaultRegistry = NewDefaultRegistry(cfg)
+	previous.Close()
+}
+
+// Close closes the default registry
+func Close() error {
+	return defaultRegistry.Close()
+}
+
+// NewDefaultRegistry creates a registry with the built-in providers
+// Base URLs, keys, order and disabled providers are taken from cfg when set
+func NewDefaultRegistry(cfg *config.Config) *Registry {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+
+	r := NewRegistry()
+	if cfg.GeoIPCityDB != "" || cfg.GeoIPASNDB != "" {
+		r.Register(NewMMDBProvider(cfg.GeoIPCityDB, cfg.GeoIPASNDB))
+	}
+	r.Register(&IPAPIProvider{BaseURL: cfg.BaseURL(ipAPIName, ipAPIBaseURL)})
+	r.Register(&IPInfoProvider{BaseURL: cfg.BaseURL(ipInfoName, ipInfoBaseURL)})
+	r.Register(&IPApiCoProvider{BaseURL: cfg.BaseURL(ipApiCoName, ipApiCoBaseURL), APIKey: cfg.IPAPIKey})
+	r.Register(&IPWhoisProvider{BaseURL: cfg.BaseURL(ipWhoisName, ipWhoisBaseURL)})
+
+	r.SetOrder(cfg.IPProviders)
+	for _, name := range cfg.IPProvidersDisabled {
+		r.Disable(name)
+	}
+
+	return r
+}
+
+// fetchJSON performs a GET request and decodes a JSON response into v
+func fetchJSON(ctx context.Context, provider, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status: %d", provider, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s returned invalid JSON: %v", provider, err)
+	}
+
+	return nil
+}
+
+// flexFloat decodes coordinates sent either as JSON numbers or strings
+type flexFloat float64
+
+// UnmarshalJSON accepts 12.5 as well as "12.5"
+func (f *flexFloat) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+
+	var v float64
+	if _, err := fmt.Sscanf(s, "%g", &v); err != nil {
+		return fmt.Errorf("invalid coordinate %s", string(data))
+	}
+
+	*f = flexFloat(v)
+	return nil
+}
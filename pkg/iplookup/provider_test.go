@@ -0,0 +1,142 @@
+package iplookup
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/malika/osint-master/internal/providertest"
+)
+
+// keyedProvider is a provider that only works with an API key
+type keyedProvider struct {
+	*IPAPIProvider
+	key string
+}
+
+func (p *keyedProvider) Name() string      { return "keyed" }
+func (p *keyedProvider) RequiresKey() bool { return true }
+func (p *keyedProvider) HasKey() bool      { return p.key != "" }
+
+// names returns the names of providers in order
+func names(providers []Provider) []string {
+	var out []string
+	for _, p := range providers {
+		out = append(out, p.Name())
+	}
+	return out
+}
+
+func TestRegistryProviders(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       []string
+		disabled    []string
+		key         string
+		want        []string
+		wantMissing []string
+	}{
+		{
+			name:        "priority order",
+			want:        []string{ipAPIName, ipInfoName, ipApiCoName, ipWhoisName},
+			wantMissing: []string{"keyed"},
+		},
+		{
+			name:        "listed providers run first",
+			order:       []string{ipWhoisName, ipInfoName},
+			want:        []string{ipWhoisName, ipInfoName, ipAPIName, ipApiCoName},
+			wantMissing: []string{"keyed"},
+		},
+		{
+			name:        "unknown names in the order are ignored",
+			order:       []string{"nosuch", ipApiCoName},
+			want:        []string{ipApiCoName, ipAPIName, ipInfoName, ipWhoisName},
+			wantMissing: []string{"keyed"},
+		},
+		{
+			name:     "disabled providers are left out",
+			order:    []string{ipWhoisName},
+			disabled: []string{ipWhoisName, ipInfoName, "keyed"},
+			want:     []string{ipAPIName, ipApiCoName},
+		},
+		{
+			name:  "keyed provider runs once it has a key",
+			order: []string{"keyed"},
+			key:   "secret",
+			want:  []string{"keyed", ipAPIName, ipInfoName, ipApiCoName, ipWhoisName},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			reg := NewDefaultRegistry(nil)
+			reg.Register(&keyedProvider{IPAPIProvider: &IPAPIProvider{}, key: tc.key})
+			reg.SetOrder(tc.order)
+			for _, name := range tc.disabled {
+				reg.Disable(name)
+			}
+
+			if got := names(reg.Providers()); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("providers = %v, want %v", got, tc.want)
+			}
+			if got := reg.MissingKeys(); !reflect.DeepEqual(got, tc.wantMissing) {
+				t.Errorf("missing keys = %v, want %v", got, tc.wantMissing)
+			}
+		})
+	}
+}
+
+func TestRegistryEnable(t *testing.T) {
+	reg := NewDefaultRegistry(nil)
+	reg.Disable(ipAPIName)
+	reg.Enable(ipAPIName)
+
+	if got := names(reg.Providers()); len(got) == 0 || got[0] != ipAPIName {
+		t.Errorf("providers = %v, want %s back in first place", got, ipAPIName)
+	}
+}
+
+func TestLookupIPWithRegistryNotesMissingKey(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{
+		"/json/8.8.8.8": {Body: providertest.Payload(t, "ip-api.json")},
+	})
+
+	reg := NewRegistry()
+	reg.Register(&keyedProvider{IPAPIProvider: &IPAPIProvider{BaseURL: stand.URL}})
+	reg.Register(&IPAPIProvider{BaseURL: stand.URL})
+
+	res, err := LookupIPWithRegistry(context.Background(), reg, "8.8.8.8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"keyed skipped: it needs an API key and none is configured"}
+	if !reflect.DeepEqual(res.Notes, want) {
+		t.Errorf("notes = %q, want %q", res.Notes, want)
+	}
+	if n := len(stand.Requests()); n != 1 {
+		t.Errorf("stand-in got %d requests, want 1", n)
+	}
+}
+
+func TestConfigureClosesPreviousRegistry(t *testing.T) {
+	original := defaultRegistry
+	t.Cleanup(func() { defaultRegistry = original })
+
+	p := &closingProvider{IPAPIProvider: &IPAPIProvider{}}
+	previous := NewRegistry()
+	previous.Register(p)
+	defaultRegistry = previous
+
+	Configure(nil)
+
+	if p.closed != 1 {
+		t.Errorf("previous provider closed %d times, want 1", p.closed)
+	}
+	if DefaultRegistry() == previous {
+		t.Error("default registry was not replaced")
+	}
+}
@@ -0,0 +1,309 @@
+package iplookup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/malika/osint-master/internal/countries"
+	"github.com/malika/osint-master/internal/opsec"
+)
+
+// Built-in provider names and default base URLs
+const (
+	ipAPIName      = "ip-api.com"
+	ipAPIBaseURL   = "http://ip-api.com"
+	ipInfoName     = "ipinfo.io"
+	ipInfoBaseURL  = "https://ipinfo.io"
+	ipApiCoName    = "ipapi.co"
+	ipApiCoBaseURL = "https://ipapi.co"
+	ipWhoisName    = "ipwhois.app"
+	ipWhoisBaseURL = "https://ipwhois.app"
+)
+
+// IPAPIProvider queries ip-api.com (free, no key required, 45 requests/minute)
+type IPAPIProvider struct {
+	BaseURL string
+}
+
+// Name returns the provider name
+func (p *IPAPIProvider) Name() string { return ipAPIName }
+
+// RequiresKey reports that ip-api.com works without a key
+func (p *IPAPIProvider) RequiresKey() bool { return false }
+
+// Priority places ip-api.com first
+func (p *IPAPIProvider) Priority() int { return 10 }
+
+// url returns the ip-api.com request URL for ip
+func (p *IPAPIProvider) url(ip string) string {
+	return fmt.Sprintf("%s/json/%s?fields=status,message,country,countryCode,region,city,lat,lon,timezone,isp,org,as,query", p.BaseURL, ip)
+}
+
+// Requests declares the ip-api.com request
+func (p *IPAPIProvider) Requests(ip string) []opsec.Request {
+	return []opsec.Request{opsec.Declare(p.Name(), "", "GET", p.url(ip))}
+}
+
+// Lookup queries ip-api.com for IP information
+func (p *IPAPIProvider) Lookup(ctx context.Context, ip string) (*IPInfo, error) {
+	url := p.url(ip)
+
+	var resp struct {
+		Status      string    `json:"status"`
+		Message     string    `json:"message"`
+		Country     string    `json:"country"`
+		CountryCode string    `json:"countryCode"`
+		Region      string    `json:"region"`
+		City        string    `json:"city"`
+		Lat         flexFloat `json:"lat"`
+		Lon         flexFloat `json:"lon"`
+		Timezone    string    `json:"timezone"`
+		ISP         string    `json:"isp"`
+		AS          string    `json:"as"`
+	}
+
+	if err := fetchJSON(ctx, p.Name(), url, &resp); err != nil {
+		return nil, err
+	}
+
+	// Check if lookup was successful
+	if resp.Status != "" && resp.Status != "success" {
+		if resp.Message != "" {
+			return nil, fmt.Errorf("ip-api error: %s", resp.Message)
+		}
+		return nil, fmt.Errorf("ip-api lookup failed")
+	}
+
+	return &IPInfo{
+		IP:          ip,
+		City:        resp.City,
+		Region:      resp.Region,
+		Country:     resp.Country,
+		CountryCode: resp.CountryCode,
+		ISP:         resp.ISP,
+		ASN:         resp.AS,
+		Timezone:    resp.Timezone,
+		Latitude:    float64(resp.Lat),
+		Longitude:   float64(resp.Lon),
+	}, nil
+}
+
+// IPInfoProvider queries ipinfo.io (free tier available)
+type IPInfoProvider struct {
+	BaseURL string
+}
+
+// Name returns the provider name
+func (p *IPInfoProvider) Name() string { return ipInfoName }
+
+// RequiresKey reports that ipinfo.io works without a key
+func (p *IPInfoProvider) RequiresKey() bool { return false }
+
+// Priority places ipinfo.io after ip-api.com
+func (p *IPInfoProvider) Priority() int { return 20 }
+
+// url returns the ipinfo.io request URL for ip
+func (p *IPInfoProvider) url(ip string) string {
+	return fmt.Sprintf("%s/%s/json", p.BaseURL, ip)
+}
+
+// Requests declares the ipinfo.io request
+func (p *IPInfoProvider) Requests(ip string) []opsec.Request {
+	return []opsec.Request{opsec.Declare(p.Name(), "", "GET", p.url(ip))}
+}
+
+// Lookup queries ipinfo.io for IP information
+func (p *IPInfoProvider) Lookup(ctx context.Context, ip string) (*IPInfo, error) {
+	url := p.url(ip)
+
+	var resp struct {
+		City     string `json:"city"`
+		Region   string `json:"region"`
+		Country  string `json:"country"`
+		Org      string `json:"org"`
+		Timezone string `json:"timezone"`
+		Loc      string `json:"loc"`
+	}
+
+	if err := fetchJSON(ctx, p.Name(), url, &resp); err != nil {
+		return nil, err
+	}
+
+	info := &IPInfo{
+		IP:       ip,
+		City:     resp.City,
+		Region:   resp.Region,
+		ISP:      resp.Org,
+		Timezone: resp.Timezone,
+	}
+
+	if resp.Country != "" {
+		info.CountryCode = resp.Country
+		// Convert country code to full name
+		info.Country = countries.Name(resp.Country)
+	}
+
+	// Extract ASN if present in org field (format: "AS15169 Google LLC")
+	parts := strings.Fields(resp.Org)
+	if len(parts) > 0 && strings.HasPrefix(parts[0], "AS") {
+		info.ASN = parts[0]
+	}
+
+	// loc format: "latitude,longitude"
+	coords := strings.Split(resp.Loc, ",")
+	if len(coords) == 2 {
+		fmt.Sscanf(coords[0], "%f", &info.Latitude)
+		fmt.Sscanf(coords[1], "%f", &info.Longitude)
+	}
+
+	return info, nil
+}
+
+// IPApiCoProvider queries ipapi.co, the API key is optional
+type IPApiCoProvider struct {
+	BaseURL string
+	APIKey  string
+}
+
+// Name returns the provider name
+func (p *IPApiCoProvider) Name() string { return ipApiCoName }
+
+// RequiresKey reports that ipapi.co has a keyless free tier
+func (p *IPApiCoProvider) RequiresKey() bool { return false }
+
+// HasKey reports whether an ipapi.co key is configured
+func (p *IPApiCoProvider) HasKey() bool { return p.APIKey != "" }
+
+// Priority places ipapi.co after ipinfo.io
+func (p *IPApiCoProvider) Priority() int { return 30 }
+
+// url returns the ipapi.co request URL for ip, with key when it is set
+func (p *IPApiCoProvider) url(ip, key string) string {
+	url := fmt.Sprintf("%s/%s/json/", p.BaseURL, ip)
+	if key != "" {
+		url += "?key=" + key
+	}
+	return url
+}
+
+// Requests declares the ipapi.co request
+func (p *IPApiCoProvider) Requests(ip string) []opsec.Request {
+	if p.APIKey != "" {
+		return []opsec.Request{opsec.Declare(p.Name(), "", "GET", p.url(ip, "<IPAPI_KEY>")).WithKey()}
+	}
+	return []opsec.Request{opsec.Declare(p.Name(), "", "GET", p.url(ip, ""))}
+}
+
+// Lookup queries ipapi.co for IP information
+func (p *IPApiCoProvider) Lookup(ctx context.Context, ip string) (*IPInfo, error) {
+	url := p.url(ip, p.APIKey)
+
+	var resp struct {
+		Error       bool      `json:"error"`
+		Reason      string    `json:"reason"`
+		City        string    `json:"city"`
+		Region      string    `json:"region"`
+		CountryName string    `json:"country_name"`
+		CountryCode string    `json:"country_code"`
+		Org         string    `json:"org"`
+		ASN         string    `json:"asn"`
+		Timezone    string    `json:"timezone"`
+		Latitude    flexFloat `json:"latitude"`
+		Longitude   flexFloat `json:"longitude"`
+	}
+
+	// ipapi.co requires a User-Agent header, the shared client always sends one
+	if err := fetchJSON(ctx, p.Name(), url, &resp); err != nil {
+		return nil, err
+	}
+
+	// Check for error response
+	if resp.Error {
+		if resp.Reason != "" {
+			return nil, fmt.Errorf("ipapi.co error: %s", resp.Reason)
+		}
+		return nil, fmt.Errorf("ipapi.co lookup failed")
+	}
+
+	return &IPInfo{
+		IP:          ip,
+		City:        resp.City,
+		Region:      resp.Region,
+		Country:     resp.CountryName,
+		CountryCode: resp.CountryCode,
+		ISP:         resp.Org,
+		ASN:         resp.ASN,
+		Timezone:    resp.Timezone,
+		Latitude:    float64(resp.Latitude),
+		Longitude:   float64(resp.Longitude),
+	}, nil
+}
+
+// IPWhoisProvider queries ipwhois.app
+type IPWhoisProvider struct {
+	BaseURL string
+}
+
+// Name returns the provider name
+func (p *IPWhoisProvider) Name() string { return ipWhoisName }
+
+// RequiresKey reports that ipwhois.app works without a key
+func (p *IPWhoisProvider) RequiresKey() bool { return false }
+
+// Priority places ipwhois.app last
+func (p *IPWhoisProvider) Priority() int { return 40 }
+
+// url returns the ipwhois.app request URL for ip
+func (p *IPWhoisProvider) url(ip string) string {
+	return fmt.Sprintf("%s/json/%s", p.BaseURL, ip)
+}
+
+// Requests declares the ipwhois.app request
+func (p *IPWhoisProvider) Requests(ip string) []opsec.Request {
+	return []opsec.Request{opsec.Declare(p.Name(), "", "GET", p.url(ip))}
+}
+
+// Lookup queries ipwhois.app for IP information
+func (p *IPWhoisProvider) Lookup(ctx context.Context, ip string) (*IPInfo, error) {
+	url := p.url(ip)
+
+	var resp struct {
+		Success     *bool     `json:"success"`
+		Message     string    `json:"message"`
+		City        string    `json:"city"`
+		Region      string    `json:"region"`
+		Country     string    `json:"country"`
+		CountryCode string    `json:"country_code"`
+		ISP         string    `json:"isp"`
+		ASN         string    `json:"asn"`
+		Timezone    string    `json:"timezone"`
+		Latitude    flexFloat `json:"latitude"`
+		Longitude   flexFloat `json:"longitude"`
+	}
+
+	if err := fetchJSON(ctx, p.Name(), url, &resp); err != nil {
+		return nil, err
+	}
+
+	// Check for error or success field
+	if resp.Success != nil && !*resp.Success {
+		if resp.Message != "" {
+			return nil, fmt.Errorf("ipwhois.app error: %s", resp.Message)
+		}
+		return nil, fmt.Errorf("ipwhois.app lookup failed")
+	}
+
+	return &IPInfo{
+		IP:          ip,
+		City:        resp.City,
+		Region:      resp.Region,
+		Country:     resp.Country,
+		CountryCode: resp.CountryCode,
+		ISP:         resp.ISP,
+		ASN:         resp.ASN,
+		Timezone:    resp.Timezone,
+		Latitude:    float64(resp.Latitude),
+		Longitude:   float64(resp.Longitude),
+	}, nil
+}
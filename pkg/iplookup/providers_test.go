@@ -0,0 +1,346 @@
+package iplookup
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/internal/providertest"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// providerCase is one answer of a stand-in and what a provider makes of it
+type providerCase struct {
+	name    string
+	resp    providertest.Response
+	want    *IPInfo // nil when the lookup must fail
+	wantErr string
+}
+
+// runProviderCases looks up ip with a provider pointed at a stand-in answering path
+func runProviderCases(t *testing.T, path, ip string, newProvider func(baseURL string) Provider, cases []providerCase) {
+	t.Helper()
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			providertest.UseTestClient(t)
+			stand := providertest.New(t, map[string]providertest.Response{path: tc.resp})
+
+			info, err := newProvider(stand.URL).Lookup(context.Background(), ip)
+			if tc.want == nil {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(info, tc.want) {
+				t.Errorf("info = %+v\nwant   %+v", info, tc.want)
+			}
+		})
+	}
+}
+
+func TestIPAPIProviderLookup(t *testing.T) {
+	newProvider := func(baseURL string) Provider { return &IPAPIProvider{BaseURL: baseURL} }
+	runProviderCases(t, "/json/8.8.8.8", "8.8.8.8", newProvider, []providerCase{
+		{
+			name: "success",
+			resp: providertest.Response{Body: providertest.Payload(t, "ip-api.json")},
+			want: &IPInfo{
+				IP:          "8.8.8.8",
+				City:        "Ashburn",
+				Region:      "VA",
+				Country:     "United States",
+				CountryCode: "US",
+				ISP:         "Google LLC",
+				ASN:         "AS15169 Google LLC",
+				Timezone:    "America/New_York",
+				Latitude:    39.03,
+				Longitude:   -77.5,
+			},
+		},
+		{
+			name:    "failed lookup",
+			resp:    providertest.Response{Body: providertest.Payload(t, "ip-api-fail.json")},
+			wantErr: "ip-api error: reserved range",
+		},
+		{
+			name:    "rate limited",
+			resp:    providertest.TooManyRequests,
+			wantErr: "ip-api.com returned status: 429",
+		},
+		{
+			name:    "server error",
+			resp:    providertest.Response{Status: http.StatusInternalServerError},
+			wantErr: "ip-api.com returned status: 500",
+		},
+		{
+			name:    "malformed JSON",
+			resp:    providertest.Response{Body: `{"status":"success","city":`},
+			wantErr: "ip-api.com returned invalid JSON",
+		},
+	})
+}
+
+func TestIPInfoProviderLookup(t *testing.T) {
+	newProvider := func(baseURL string) Provider { return &IPInfoProvider{BaseURL: baseURL} }
+	runProviderCases(t, "/8.8.8.8/json", "8.8.8.8", newProvider, []providerCase{
+		{
+			name: "success",
+			resp: providertest.Response{Body: providertest.Payload(t, "ipinfo.json")},
+			want: &IPInfo{
+				IP:          "8.8.8.8",
+				City:        "Mountain View",
+				Region:      "California",
+				Country:     "United States",
+				CountryCode: "US",
+				ISP:         "AS15169 Google LLC",
+				ASN:         "AS15169",
+				Timezone:    "America/Los_Angeles",
+				Latitude:    37.4056,
+				Longitude:   -122.0775,
+			},
+		},
+		{
+			name:    "rate limited",
+			resp:    providertest.TooManyRequests,
+			wantErr: "ipinfo.io returned status: 429",
+		},
+		{
+			name:    "malformed JSON",
+			resp:    providertest.Response{Body: `<html>Bad gateway</html>`},
+			wantErr: "ipinfo.io returned invalid JSON",
+		},
+	})
+}
+
+func TestIPApiCoProviderLookup(t *testing.T) {
+	newProvider := func(baseURL string) Provider { return &IPApiCoProvider{BaseURL: baseURL} }
+	runProviderCases(t, "/8.8.8.8/json/", "8.8.8.8", newProvider, []providerCase{
+		{
+			name: "success",
+			resp: providertest.Response{Body: providertest.Payload(t, "ipapi-co.json")},
+			want: &IPInfo{
+				IP:          "8.8.8.8",
+				City:        "Mountain View",
+				Region:      "California",
+				Country:     "United States",
+				CountryCode: "US",
+				ISP:         "GOOGLE",
+				ASN:         "AS15169",
+				Timezone:    "America/Los_Angeles",
+				Latitude:    37.42301,
+				Longitude:   -122.083352,
+			},
+		},
+		{
+			name:    "error body",
+			resp:    providertest.Response{Body: providertest.Payload(t, "ipapi-co-error.json")},
+			wantErr: "ipapi.co error: RateLimited",
+		},
+		{
+			name:    "rate limited",
+			resp:    providertest.TooManyRequests,
+			wantErr: "ipapi.co returned status: 429",
+		},
+		{
+			name:    "malformed JSON",
+			resp:    providertest.Response{Body: `{"city": "Mountain View", "latitude": true}`},
+			wantErr: "ipapi.co returned invalid JSON",
+		},
+	})
+}
+
+func TestIPApiCoProviderSendsKey(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{
+		"/8.8.8.8/json/": {Body: providertest.Payload(t, "ipapi-co.json")},
+	})
+
+	p := &IPApiCoProvider{BaseURL: stand.URL, APIKey: "secret"}
+	if _, err := p.Lookup(context.Background(), "8.8.8.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key := stand.Requests()[0].URL.Query().Get("key"); key != "secret" {
+		t.Errorf("key = %q, want secret", key)
+	}
+}
+
+func TestIPWhoisProviderLookup(t *testing.T) {
+	newProvider := func(baseURL string) Provider { return &IPWhoisProvider{BaseURL: baseURL} }
+	runProviderCases(t, "/json/8.8.8.8", "8.8.8.8", newProvider, []providerCase{
+		{
+			// ipwhois.app sends coordinates as strings
+			name: "success",
+			resp: providertest.Response{Body: providertest.Payload(t, "ipwhois.json")},
+			want: &IPInfo{
+				IP:          "8.8.8.8",
+				City:        "Mountain View",
+				Region:      "California",
+				Country:     "United States",
+				CountryCode: "US",
+				ISP:         "Google LLC",
+				ASN:         "AS15169",
+				Timezone:    "America/Los_Angeles",
+				Latitude:    37.3860517,
+				Longitude:   -122.0838511,
+			},
+		},
+		{
+			name:    "failed lookup",
+			resp:    providertest.Response{Body: providertest.Payload(t, "ipwhois-fail.json")},
+			wantErr: "ipwhois.app error: reserved range",
+		},
+		{
+			name:    "rate limited",
+			resp:    providertest.TooManyRequests,
+			wantErr: "ipwhois.app returned status: 429",
+		},
+		{
+			name:    "malformed coordinates",
+			resp:    providertest.Response{Body: `{"success": true, "latitude": "north"}`},
+			wantErr: "ipwhois.app returned invalid JSON",
+		},
+	})
+}
+
+func TestLookupIPWithRegistryFallsBack(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{
+		"/json/8.8.8.8": providertest.TooManyRequests,
+		"/8.8.8.8/json": {Body: providertest.Payload(t, "ipinfo.json")},
+	})
+
+	reg := NewRegistry()
+	reg.Register(&IPAPIProvider{BaseURL: stand.URL})
+	reg.Register(&IPInfoProvider{BaseURL: stand.URL})
+
+	res, err := LookupIPWithRegistry(context.Background(), reg, "8.8.8.8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if source := res.Data.(*IPInfo).Source; source != ipInfoName {
+		t.Errorf("source = %q, want %s", source, ipInfoName)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Provider != ipAPIName {
+		t.Errorf("errors = %+v, want the ip-api.com failure", res.Errors)
+	}
+}
+
+func TestLookupIPWithRegistryAllFail(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{
+		"/json/8.8.8.8": {Status: http.StatusServiceUnavailable},
+		"/8.8.8.8/json": {Body: `not json`},
+	})
+
+	reg := NewRegistry()
+	reg.Register(&IPAPIProvider{BaseURL: stand.URL})
+	reg.Register(&IPInfoProvider{BaseURL: stand.URL})
+
+	res, err := LookupIPWithRegistry(context.Background(), reg, "8.8.8.8")
+	if err == nil {
+		t.Fatal("expected an error when every provider fails")
+	}
+	if res != nil {
+		t.Errorf("result = %+v, want none", res)
+	}
+	// The error keeps the reason of every provider
+	for _, want := range []string{"ip-api.com returned status: 503", "ipinfo.io returned invalid JSON"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestLookupIPWithRegistryAllFailWrapsCause(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{})
+
+	reg := NewRegistry()
+	reg.Register(&IPAPIProvider{BaseURL: stand.URL})
+	stand.Close()
+
+	_, err := LookupIPWithRegistry(context.Background(), reg, "8.8.8.8")
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		t.Errorf("error = %v, want the connection error of the provider wrapped", err)
+	}
+}
+
+func TestLookupIPWithRegistryInterrupted(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{
+		"/json/8.8.8.8": providertest.TooManyRequests,
+		"/8.8.8.8/json": {Body: providertest.Payload(t, "ipinfo.json")},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reg := NewRegistry()
+	reg.Register(&cancellingProvider{IPAPIProvider: &IPAPIProvider{BaseURL: stand.URL}, cancel: cancel})
+	reg.Register(&IPInfoProvider{BaseURL: stand.URL})
+
+	res, err := LookupIPWithRegistry(ctx, reg, "8.8.8.8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Partial {
+		t.Error("result is not marked partial")
+	}
+	// The failure seen before the cancel is kept, the next provider is never asked
+	var providers []string
+	for _, e := range res.Errors {
+		providers = append(providers, e.Provider)
+	}
+	if want := []string{ipAPIName, result.ContextProvider}; !reflect.DeepEqual(providers, want) {
+		t.Errorf("errors from %v, want %v", providers, want)
+	}
+	if n := len(stand.Requests()); n != 1 {
+		t.Errorf("stand-in got %d requests, want 1", n)
+	}
+}
+
+// cancellingProvider cancels the lookup once it has answered
+type cancellingProvider struct {
+	*IPAPIProvider
+	cancel context.CancelFunc
+}
+
+func (p *cancellingProvider) Lookup(ctx context.Context, ip string) (*IPInfo, error) {
+	defer p.cancel()
+	return p.IPAPIProvider.Lookup(ctx, ip)
+}
+
+// closingProvider counts how often the registry closes it
+type closingProvider struct {
+	*IPAPIProvider
+	closed int
+}
+
+func (p *closingProvider) Close() error {
+	p.closed++
+	return nil
+}
+
+func TestRegistryCloseClosesProviders(t *testing.T) {
+	p := &closingProvider{IPAPIProvider: &IPAPIProvider{}}
+	reg := NewRegistry()
+	reg.Register(p)
+	reg.Register(&IPInfoProvider{})
+	reg.Disable(p.Name())
+
+	if err := reg.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Disabled providers still hold their files
+	if p.closed != 1 {
+		t.Errorf("provider closed %d times, want 1", p.closed)
+	}
+}
@@ -0,0 +1,280 @@
+// Package lookup runs any lookup module by name
+// It is the single dispatch point shared by the CLI, batch mode and the web server
+package lookup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/audit"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/opsec"
+	"github.com/malika/osint-master/internal/scope"
+	"github.com/malika/osint-master/internal/validator"
+	"github.com/malika/osint-master/pkg/domain"
+	"github.com/malika/osint-master/pkg/emaillookup"
+	"github.com/malika/osint-master/pkg/iplookup"
+	"github.com/malika/osint-master/pkg/namelookup"
+	"github.com/malika/osint-master/pkg/phonelookup"
+	"github.com/malika/osint-master/pkg/result"
+	"github.com/malika/osint-master/pkg/username"
+)
+
+// Options changes how a lookup runs
+type Options struct {
+	Advanced  bool // browser automation and extended checks
+	Consensus bool // query every IP provider, IP lookups only
+	Passive   bool // skip active checks that contact the target's infrastructure
+}
+
+// Func runs one lookup module for a target
+type Func func(ctx context.Context, cfg *config.Config, target string, opts Options) (*result.Result, error)
+
+// modules maps module names to their lookup
+var modules = map[string]Func{
+	result.ModuleIP: func(ctx context.Context, cfg *config.Config, target string, opts Options) (*result.Result, error) {
+		switch {
+		case opts.Consensus && opts.Advanced:
+			return iplookup.AdvancedConsensusLookupIP(ctx, target)
+		case opts.Consensus:
+			return iplookup.ConsensusLookupIP(ctx, target)
+		case opts.Advanced:
+			return iplookup.AdvancedLookupIP(ctx, target)
+		}
+		return iplookup.LookupIP(ctx, target)
+	},
+	result.ModuleDomain: func(ctx context.Context, cfg *config.Config, target string, opts Options) (*result.Result, error) {
+		if opts.Advanced {
+			return domain.AdvancedEnumerateDomain(ctx, target)
+		}
+		return domain.EnumerateDomain(ctx, target)
+	},
+	result.ModuleEmail: func(ctx context.Context, cfg *config.Config, target string, opts Options) (*result.Result, error) {
+		if opts.Advanced {
+			return emaillookup.AdvancedLookupEmail(ctx, target, cfg.HIBPAPIKey)
+		}
+		return emaillookup.LookupEmailWithConfig(ctx, target, cfg.HIBPAPIKey)
+	},
+	result.ModulePhone: func(ctx context.Context, cfg *config.Config, target string, opts Options) (*result.Result, error) {
+		if opts.Advanced {
+			return phonelookup.AdvancedLookupPhoneWithConfig(ctx, target, cfg)
+		}
+		return phonelookup.LookupPhoneWithConfig(ctx, target, cfg)
+	},
+	result.ModuleUsername: func(ctx context.Context, cfg *config.Config, target string, opts Options) (*result.Result, error) {
+		if opts.Advanced {
+			return username.AdvancedSearchUsername(ctx, target)
+		}
+		return username.SearchUsername(ctx, target)
+	},
+	result.ModuleName: func(ctx context.Context, cfg *config.Config, target string, opts Options) (*result.Result, error) {
+		if opts.Advanced {
+			return namelookup.AdvancedSearchByName(ctx, target)
+		}
+		return namelookup.SearchByName(ctx, target)
+	},
+}
+
+// validators check a target before its module runs, so a malformed target never reaches a provider
+var validators = map[string]func(target string) error{
+	result.ModuleIP:       validator.ValidateIP,
+	result.ModuleDomain:   validator.ValidateDomain,
+	result.ModuleEmail:    validator.ValidateEmail,
+	result.ModulePhone:    validator.ValidatePhone,
+	result.ModuleUsername: validator.ValidateUsername,
+	result.ModuleName:     validator.ValidateName,
+}
+
+// InvalidTargetError is returned for a target its module cannot look up
+// It is the caller's mistake, unlike the failures of the providers
+type InvalidTargetError struct {
+	Module string
+	Target string
+	Err    error
+}
+
+// Error implements error
+func (e *InvalidTargetError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the validation failure
+func (e *InvalidTargetError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks that target can be looked up with module, the error is an *InvalidTargetError
+func Validate(module, target string) error {
+	validate, ok := validators[module]
+	if !ok {
+		return fmt.Errorf("unknown module: %s", module)
+	}
+	if err := validate(strings.TrimSpace(target)); err != nil {
+		return &InvalidTargetError{Module: module, Target: target, Err: err}
+	}
+	return nil
+}
+
+// PlanFunc declares the requests a lookup module would make for a target
+type PlanFunc func(cfg *config.Config, target string, opts Options) ([]opsec.Request, error)
+
+// plans maps module names to the requests their lookup declares
+// Name lookups only build search links and make no requests
+var plans = map[string]PlanFunc{
+	result.ModuleIP: func(cfg *config.Config, target string, opts Options) ([]opsec.Request, error) {
+		return iplookup.DefaultRegistry().Requests(target, opts.Consensus), nil
+	},
+	result.ModuleDomain: func(cfg *config.Config, target string, opts Options) ([]opsec.Request, error) {
+		return domain.Requests(target), nil
+	},
+	result.ModuleEmail: func(cfg *config.Config, target string, opts Options) ([]opsec.Request, error) {
+		return emaillookup.Requests(target, cfg.HIBPAPIKey), nil
+	},
+	result.ModulePhone: func(cfg *config.Config, target string, opts Options) ([]opsec.Request, error) {
+		return phonelookup.Requests(target, cfg), nil
+	},
+	result.ModuleUsername: func(cfg *config.Config, target string, opts Options) ([]opsec.Request, error) {
+		return username.Requests(target, opts.Advanced)
+	},
+	result.ModuleName: func(cfg *config.Config, target string, opts Options) ([]opsec.Request, error) {
+		return nil, nil
+	},
+}
+
+// Configure applies cfg to the shared HTTP client and every lookup module
+// It is the one setup sequence of the CLI, the watch daemon and the web server
+func Configure(cfg *config.Config) error {
+	if err := httpclient.Configure(httpclient.OptionsFromConfig(cfg)); err != nil {
+		return err
+	}
+
+	iplookup.Configure(cfg)
+	domain.Configure(cfg)
+	emaillookup.Configure(cfg)
+	phonelookup.Configure(cfg)
+	return nil
+}
+
+// Close releases what Configure opened, such as the GeoIP databases
+func Close() error {
+	return iplookup.Close()
+}
+
+// Modules returns the supported module names in alphabetical order
+func Modules() []string {
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Supported reports whether module names a lookup module
+func Supported(module string) bool {
+	_, ok := modules[module]
+	return ok
+}
+
+// Observer is told about every lookup a runner finishes, with the raw provider responses
+// Failed lookups are reported as a result from result.Failure
+type Observer func(res *result.Result, responses []httpclient.Response)
+
+// Runner runs lookups with one configuration
+type Runner struct {
+	cfg      *config.Config
+	observer Observer
+	scope    *scope.Scope
+	auditLog *audit.Log
+}
+
+// NewRunner creates a runner using cfg for API keys, nil loads no keys
+func NewRunner(cfg *config.Config) *Runner {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	return &Runner{cfg: cfg}
+}
+
+// Config returns the configuration used by the runner
+func (r *Runner) Config() *config.Config {
+	return r.cfg
+}
+
+// Observe sets a function called after every lookup, it must be safe for concurrent use
+// Observe must be called before the runner is shared
+func (r *Runner) Observe(observer Observer) {
+	r.observer = observer
+}
+
+// Enforce holds every lookup of the runner to an engagement scope
+// Enforce must be called before the runner is shared
+func (r *Runner) Enforce(s *scope.Scope) {
+	r.scope = s
+}
+
+// Audit records every lookup of the runner and the requests it sends in l
+// Audit must be called before the runner is shared
+func (r *Runner) Audit(l *audit.Log) {
+	r.auditLog = l
+}
+
+// Plan returns the requests a lookup of target would make, nothing is sent
+func (r *Runner) Plan(module, target string, opts Options) ([]opsec.Request, error) {
+	plan, ok := plans[module]
+	if !ok {
+		return nil, fmt.Errorf("unknown module: %s", module)
+	}
+	return plan(r.cfg, target, opts)
+}
+
+// Run looks up target with the named module
+// Responses served from the cache are listed in the result
+func (r *Runner) Run(ctx context.Context, module, target string, opts Options) (*result.Result, error) {
+	lookup, ok := modules[module]
+	if !ok {
+		return nil, fmt.Errorf("unknown module: %s", module)
+	}
+	if err := Validate(module, target); err != nil {
+		return nil, err
+	}
+
+	if r.scope != nil {
+		ctx = scope.WithScope(ctx, r.scope)
+	}
+	scope.Note(ctx, "lookup", module, target)
+	if r.auditLog != nil {
+		ctx = audit.WithLookup(ctx, r.auditLog, module, target)
+	}
+
+	if opts.Passive {
+		ctx = opsec.WithPassive(ctx)
+	}
+	ctx, skipLog := opsec.TrackSkipped(ctx)
+	ctx, cacheLog := httpclient.TrackCache(ctx)
+	var responseLog *httpclient.ResponseLog
+	if r.observer != nil {
+		ctx, responseLog = httpclient.CaptureResponses(ctx)
+	}
+
+	res, err := lookup(ctx, r.cfg, target, opts)
+	if r.auditLog != nil {
+		r.auditLog.Lookup(ctx, module, target, res != nil && res.Partial, err)
+	}
+	if err != nil {
+		if r.observer != nil {
+			r.observer(result.Failure(module, target, err), responseLog.Responses())
+		}
+		return nil, err
+	}
+
+	res.Cached = cacheLog.Responses()
+	res.Skipped = skipLog.Checks()
+	if r.observer != nil {
+		r.observer(res, responseLog.Responses())
+	}
+	return res, nil
+}
@@ -1,96 +1,74 @@
 package namelookup
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/malika/osint-master/pkg/result"
 )
 
 // AdvancedSearchByName performs enhanced people search with additional sources
-func AdvancedSearchByName(fullName string) (string, error) {
-	var result strings.Builder
-	// result.WriteString("⚠️  ADVANCED MODE: Enhanced people search\n")
-	// result.WriteString("This mode provides additional search sources\n")
-	// result.WriteString(strings.Repeat("=", 70) + "\n\n")
-
+func AdvancedSearchByName(ctx context.Context, fullName string) (*result.Result, error) {
 	// Perform standard search first
 	startTime := time.Now()
-	standardResult, err := SearchByName(fullName)
+	res, err := SearchByName(ctx, fullName)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	result.WriteString(standardResult)
-	result.WriteString("\n" + strings.Repeat("-", 70) + "\n")
-	result.WriteString("ADVANCED CHECKS:\n")
-	result.WriteString(strings.Repeat("-", 70) + "\n\n")
+	res.Advanced = true
 
 	// Parse name
 	firstName, lastName := parseName(fullName)
 	fullNameEncoded := strings.ReplaceAll(fullName, " ", "%20")
 
-	result.WriteString("Professional Networks:\n")
-	result.WriteString(fmt.Sprintf("  - LinkedIn: https://www.linkedin.com/search/results/all/?keywords=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Indeed Resume: https://www.indeed.com/resumes?q=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - AngelList: https://angel.co/search?q=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Crunchbase: https://www.crunchbase.com/discover/people?q=%s\n", fullNameEncoded))
-
-	result.WriteString("\nSocial Media Deep Search:\n")
-	result.WriteString(fmt.Sprintf("  - Facebook People: https://www.facebook.com/search/people/?q=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Twitter Advanced: https://twitter.com/search?q=%s&f=user\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Instagram: https://www.instagram.com/explore/tags/%s/\n", strings.ToLower(strings.ReplaceAll(fullName, " ", ""))))
-	result.WriteString(fmt.Sprintf("  - TikTok: https://www.tiktok.com/search/user?q=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Reddit: https://www.reddit.com/search/?q=%s\n", fullNameEncoded))
-
-	result.WriteString("\nPeople Search Services:\n")
-	result.WriteString(fmt.Sprintf("  - Whitepages: https://www.whitepages.com/name/%s-%s\n", firstName, lastName))
-	result.WriteString(fmt.Sprintf("  - TruePeopleSearch: https://www.truepeoplesearch.com/results?name=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - FastPeopleSearch: https://www.fastpeoplesearch.com/name/%s-%s\n", firstName, lastName))
-	result.WriteString(fmt.Sprintf("  - Spokeo: https://www.spokeo.com/%s-%s\n", firstName, lastName))
-	result.WriteString(fmt.Sprintf("  - Pipl: https://pipl.com/search/?q=%s\n", fullNameEncoded))
-
-	result.WriteString("\nPublic Records:\n")
-	result.WriteString(fmt.Sprintf("  - Court Records: Search county clerk websites\n"))
-	result.WriteString(fmt.Sprintf("  - Property Records: Search county assessor websites\n"))
-	result.WriteString(fmt.Sprintf("  - Voter Registration: Search state voter databases\n"))
-	result.WriteString(fmt.Sprintf("  - Business Filings: Search Secretary of State websites\n"))
-
-	result.WriteString("\nProfessional & Academic:\n")
-	result.WriteString(fmt.Sprintf("  - Google Scholar: https://scholar.google.com/scholar?q=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - ResearchGate: https://www.researchgate.net/search.Search.html?query=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - ORCID: https://orcid.org/orcid-search/search?searchQuery=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Academia.edu: https://www.academia.edu/search?q=%s\n", fullNameEncoded))
-
-	result.WriteString("\nContent & Profiles:\n")
-	result.WriteString(fmt.Sprintf("  - GitHub: https://github.com/search?q=%s&type=users\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Stack Overflow: https://stackoverflow.com/users?search=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Medium: https://medium.com/search/people?q=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - YouTube: https://www.youtube.com/results?search_query=%s\n", fullNameEncoded))
-	result.WriteString(fmt.Sprintf("  - Vimeo: https://vimeo.com/search?q=%s\n", fullNameEncoded))
-
-	result.WriteString("\nUsername Variations to Try:\n")
-	result.WriteString(fmt.Sprintf("  - %s%s\n", strings.ToLower(firstName), strings.ToLower(lastName)))
-	result.WriteString(fmt.Sprintf("  - %s.%s\n", strings.ToLower(firstName), strings.ToLower(lastName)))
-	result.WriteString(fmt.Sprintf("  - %s_%s\n", strings.ToLower(firstName), strings.ToLower(lastName)))
-	if len(firstName) > 0 && len(lastName) > 0 {
-		result.WriteString(fmt.Sprintf("  - %c%s\n", strings.ToLower(firstName)[0], strings.ToLower(lastName)))
+	res.AddLink("Professional Networks", "LinkedIn", fmt.Sprintf("https://www.linkedin.com/search/results/all/?keywords=%s", fullNameEncoded))
+	res.AddLink("Professional Networks", "Indeed Resume", fmt.Sprintf("https://www.indeed.com/resumes?q=%s", fullNameEncoded))
+	res.AddLink("Professional Networks", "AngelList", fmt.Sprintf("https://angel.co/search?q=%s", fullNameEncoded))
+	res.AddLink("Professional Networks", "Crunchbase", fmt.Sprintf("https://www.crunchbase.com/discover/people?q=%s", fullNameEncoded))
+
+	res.AddLink("Social Media Deep Search", "Facebook People", fmt.Sprintf("https://www.facebook.com/search/people/?q=%s", fullNameEncoded))
+	res.AddLink("Social Media Deep Search", "Twitter Advanced", fmt.Sprintf("https://twitter.com/search?q=%s&f=user", fullNameEncoded))
+	res.AddLink("Social Media Deep Search", "Instagram", fmt.Sprintf("https://www.instagram.com/explore/tags/%s/", strings.ToLower(strings.ReplaceAll(fullName, " ", ""))))
+	res.AddLink("Social Media Deep Search", "TikTok", fmt.Sprintf("https://www.tiktok.com/search/user?q=%s", fullNameEncoded))
+	res.AddLink("Social Media Deep Search", "Reddit", fmt.Sprintf("https://www.reddit.com/search/?q=%s", fullNameEncoded))
+
+	res.AddLink("People Search Services", "Whitepages", fmt.Sprintf("https://www.whitepages.com/name/%s-%s", firstName, lastName))
+	res.AddLink("People Search Services", "TruePeopleSearch", fmt.Sprintf("https://www.truepeoplesearch.com/results?name=%s", fullNameEncoded))
+	res.AddLink("People Search Services", "FastPeopleSearch", fmt.Sprintf("https://www.fastpeoplesearch.com/name/%s-%s", firstName, lastName))
+	res.AddLink("People Search Services", "Spokeo", fmt.Sprintf("https://www.spokeo.com/%s-%s", firstName, lastName))
+	res.AddLink("People Search Services", "Pipl", fmt.Sprintf("https://pipl.com/search/?q=%s", fullNameEncoded))
+
+	res.AddLink("Professional & Academic", "Google Scholar", fmt.Sprintf("https://scholar.google.com/scholar?q=%s", fullNameEncoded))
+	res.AddLink("Professional & Academic", "ResearchGate", fmt.Sprintf("https://www.researchgate.net/search.Search.html?query=%s", fullNameEncoded))
+	res.AddLink("Professional & Academic", "ORCID", fmt.Sprintf("https://orcid.org/orcid-search/search?searchQuery=%s", fullNameEncoded))
+	res.AddLink("Professional & Academic", "Academia.edu", fmt.Sprintf("https://www.academia.edu/search?q=%s", fullNameEncoded))
+
+	res.AddLink("Content & Profiles", "GitHub", fmt.Sprintf("https://github.com/search?q=%s&type=users", fullNameEncoded))
+	res.AddLink("Content & Profiles", "Stack Overflow", fmt.Sprintf("https://stackoverflow.com/users?search=%s", fullNameEncoded))
+	res.AddLink("Content & Profiles", "Medium", fmt.Sprintf("https://medium.com/search/people?q=%s", fullNameEncoded))
+	res.AddLink("Content & Profiles", "YouTube", fmt.Sprintf("https://www.youtube.com/results?search_query=%s", fullNameEncoded))
+	res.AddLink("Content & Profiles", "Vimeo", fmt.Sprintf("https://vimeo.com/search?q=%s", fullNameEncoded))
+
+	res.AddLink("Data Breach Checking", "HIBP", "https://haveibeenpwned.com/")
+	res.AddLink("Data Breach Checking", "DeHashed", "https://dehashed.com/")
+
+	// Username variations to try
+	first := strings.ToLower(firstName)
+	last := strings.ToLower(lastName)
+	res.Add("username_variation", first+last, "name-pattern", result.ConfidenceLow, nil)
+	res.Add("username_variation", first+"."+last, "name-pattern", result.ConfidenceLow, nil)
+	res.Add("username_variation", first+"_"+last, "name-pattern", result.ConfidenceLow, nil)
+	if len(first) > 0 && len(last) > 0 {
+		res.Add("username_variation", fmt.Sprintf("%c%s", first[0], last), "name-pattern", result.ConfidenceLow, nil)
 	}
 
-	result.WriteString("\nData Breach Checking:\n")
-	result.WriteString("  Check if name appears in data breaches:\n")
-	result.WriteString(fmt.Sprintf("  - HIBP: https://haveibeenpwned.com/\n"))
-	result.WriteString(fmt.Sprintf("  - DeHashed: https://dehashed.com/\n"))
-
-	result.WriteString("\n⚠️  IMPORTANT NOTES:\n")
-	result.WriteString("  - Always verify information from multiple sources\n")
-	result.WriteString("  - Respect privacy laws and ethical boundaries\n")
-	result.WriteString("  - Some services require paid subscriptions\n")
-	result.WriteString("  - Public records vary by jurisdiction\n")
-
-	elapsed := time.Since(startTime)
-	result.WriteString("\n" + strings.Repeat("=", 70) + "\n")
-	result.WriteString(fmt.Sprintf("⏱️  Advanced search completed in %.2f seconds\n", elapsed.Seconds()))
-	result.WriteString("Note: Advanced mode provides comprehensive people search resources\n")
+	res.AddNote("Public records (court, property, voter, business filings) vary by jurisdiction")
+	res.AddNote("Always verify information from multiple sources")
+	res.AddNote("Respect privacy laws and ethical boundaries")
+	res.AddNote("Advanced mode provides comprehensive people search resources")
 
-	return result.String(), nil
+	res.Elapsed = time.Since(startTime)
+	return res, nil
 }
@@ -1,26 +1,34 @@
 package namelookup
 
 import (
+	"context"
 	"fmt"
 	"strings"
+
+	"github.com/malika/osint-master/pkg/result"
 )
 
 // NameInfo holds information about a person
 type NameInfo struct {
-	FirstName    string
-	LastName     string
-	PhoneNumber  string
-	Address      string
-	LinkedInURL  string
-	FacebookURL  string
-	TwitterURL   string
-	InstagramURL string
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	PhoneNumber  string `json:"phone_number"`
+	Address      string `json:"address"`
+	LinkedInURL  string `json:"linkedin_url"`
+	FacebookURL  string `json:"facebook_url"`
+	TwitterURL   string `json:"twitter_url"`
+	InstagramURL string `json:"instagram_url"`
 }
 
 // SearchByName searches for information based on a full name
-func SearchByName(fullName string) (string, error) {
+// No network requests are made, ctx is only checked before starting
+func SearchByName(ctx context.Context, fullName string) (*result.Result, error) {
 	if fullName == "" {
-		return "", fmt.Errorf("name cannot be empty")
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// Parse the full name into first and last name
@@ -42,8 +50,11 @@ func SearchByName(fullName string) (string, error) {
 		InstagramURL: fmt.Sprintf("https://www.instagram.com/explore/tags/%s%s/", strings.ToLower(firstName), strings.ToLower(lastName)),
 	}
 
-	result := formatNameInfo(info)
-	return result, nil
+	res := result.New(result.ModuleName, fullName)
+	res.Add("first_name", info.FirstName, "parser", result.ConfidenceHigh, info.FirstName)
+	res.Add("last_name", info.LastName, "parser", result.ConfidenceHigh, info.LastName)
+	res.Data = info
+	return res, nil
 }
 
 // parseName splits a full name into first and last name
@@ -62,22 +73,3 @@ func parseName(fullName string) (string, string) {
 
 	return firstName, lastName
 }
-
-// formatNameInfo formats the name information into a readable string
-func formatNameInfo(info *NameInfo) string {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("First Name: %s\n", info.FirstName))
-	sb.WriteString(fmt.Sprintf("Last Name: %s\n", info.LastName))
-	sb.WriteString(fmt.Sprintf("Phone Number: %s\n", info.PhoneNumber))
-	sb.WriteString(fmt.Sprintf("Address: %s\n", info.Address))
-	sb.WriteString("\nSocial Media Search URLs:\n")
-	sb.WriteString(fmt.Sprintf("LinkedIn: %s\n", info.LinkedInURL))
-	sb.WriteString(fmt.Sprintf("Facebook: %s\n", info.FacebookURL))
-	sb.WriteString(fmt.Sprintf("Twitter: %s\n", info.TwitterURL))
-	sb.WriteString(fmt.Sprintf("Instagram: %s\n", info.InstagramURL))
-	sb.WriteString("\nNote: Phone and address require paid API access (e.g., Pipl, Whitepages)\n")
-	sb.WriteString("Visit the URLs above to manually search on each platform.\n")
-
-	return sb.String()
-}
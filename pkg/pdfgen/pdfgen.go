@@ -207,3 +207,41 @@ func GenerateNamePDF(filename, name, content string) error {
 	title := fmt.Sprintf("Name Lookup: %s", name)
 	return GeneratePDF(filename, title, content)
 }
+
+// Section is one target of a combined report
+type Section struct {
+	Title   string
+	Content string
+}
+
+// GenerateReportPDF creates a combined report, the summary first and one page per section
+func GenerateReportPDF(filename, title, summary string, sections []Section) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(true, 15)
+
+	pdf.AddPage()
+	addHeader(pdf, title)
+	addMetadata(pdf)
+	addContent(pdf, summary)
+
+	for _, section := range sections {
+		pdf.AddPage()
+		addSectionTitle(pdf, section.Title)
+		addContent(pdf, section.Content)
+	}
+
+	addFooter(pdf)
+
+	return pdf.OutputFileAndClose(filename)
+}
+
+// addSectionTitle adds a coloured title bar at the top of a section
+func addSectionTitle(pdf *gofpdf.Fpdf, title string) {
+	pdf.SetFillColor(41, 128, 185)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Arial", "B", 14)
+	pdf.CellFormat(0, 10, cleanText(title), "", 1, "L", true, 0, "")
+
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(4)
+}
@@ -1,115 +1,46 @@
 package phonelookup
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/pkg/result"
 )
 
 // AdvancedLookupPhone performs enhanced phone number lookup
-func AdvancedLookupPhone(phone string) (string, error) {
-	return AdvancedLookupPhoneWithConfig(phone, nil)
+func AdvancedLookupPhone(ctx context.Context, phone string) (*result.Result, error) {
+	return AdvancedLookupPhoneWithConfig(ctx, phone, nil)
 }
 
 // AdvancedLookupPhoneWithConfig performs enhanced phone number lookup with API configuration
-func AdvancedLookupPhoneWithConfig(phone string, cfg *config.Config) (string, error) {
-	var result strings.Builder
-	// result.WriteString("⚠️  ADVANCED MODE: Enhanced phone number analysis\n")
-	// result.WriteString("This mode performs additional checks and takes longer\n")
-	// result.WriteString(strings.Repeat("=", 70) + "\n\n")
-
+// Messaging platform checks are already part of the standard lookup
+func AdvancedLookupPhoneWithConfig(ctx context.Context, phone string, cfg *config.Config) (*result.Result, error) {
 	// Perform standard lookup first
 	startTime := time.Now()
-	standardResult, err := LookupPhoneWithConfig(phone, cfg)
+	res, err := LookupPhoneWithConfig(ctx, phone, cfg)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	result.WriteString(standardResult)
-	result.WriteString("\n" + strings.Repeat("-", 70) + "\n")
-	result.WriteString("ADVANCED CHECKS:\n")
-	result.WriteString(strings.Repeat("-", 70) + "\n\n")
+	res.Advanced = true
 
 	// Clean phone for display
 	cleanedPhone := cleanPhoneNumber(phone)
 
-	result.WriteString("Enhanced Verification:\n")
-	result.WriteString(fmt.Sprintf("  Cleaned Number: %s\n\n", cleanedPhone))
-
-	// Perform automated messaging platform checks
-	result.WriteString("🔍 Automated Messaging Platform Detection:\n")
-	result.WriteString("  Checking WhatsApp registration...\n")
-	onWhatsApp, whatsAppStatus := checkWhatsApp(cleanedPhone)
-	if onWhatsApp {
-		result.WriteString(fmt.Sprintf("  ✓ WhatsApp: %s\n", whatsAppStatus))
-	} else {
-		result.WriteString(fmt.Sprintf("  ✗ WhatsApp: %s\n", whatsAppStatus))
-	}
-
-	result.WriteString("  Checking Telegram registration...\n")
-	onTelegram, telegramStatus := checkTelegram(cleanedPhone)
-	if onTelegram {
-		result.WriteString(fmt.Sprintf("  ✓ Telegram: %s\n", telegramStatus))
-	} else {
-		result.WriteString(fmt.Sprintf("  ✗ Telegram: %s\n", telegramStatus))
-	}
-
-	result.WriteString("  Checking Signal registration...\n")
-	onSignal, signalStatus := checkSignal(cleanedPhone)
-	if onSignal {
-		result.WriteString(fmt.Sprintf("  ✓ Signal: %s\n", signalStatus))
-	} else {
-		result.WriteString(fmt.Sprintf("  ✗ Signal: %s\n", signalStatus))
-	}
-
-	result.WriteString("  Checking Viber registration...\n")
-	onViber, viberStatus := checkViber(cleanedPhone)
-	if onViber {
-		result.WriteString(fmt.Sprintf("  ✓ Viber: %s\n", viberStatus))
-	} else {
-		result.WriteString(fmt.Sprintf("  ✗ Viber: %s\n", viberStatus))
-	}
-
-	result.WriteString("  Checking WeChat registration...\n")
-	onWeChat, wechatStatus := checkWeChat(cleanedPhone)
-	if onWeChat {
-		result.WriteString(fmt.Sprintf("  ✓ WeChat: %s\n", wechatStatus))
-	} else {
-		result.WriteString(fmt.Sprintf("  ✗ WeChat: %s\n", wechatStatus))
-	}
-
-	result.WriteString("  Checking LINE registration...\n")
-	onLine, lineStatus := checkLine(cleanedPhone)
-	if onLine {
-		result.WriteString(fmt.Sprintf("  ✓ LINE: %s\n", lineStatus))
-	} else {
-		result.WriteString(fmt.Sprintf("  ✗ LINE: %s\n", lineStatus))
-	}
-
-	// Additional lookup services
-	result.WriteString("\nAdditional Lookup Services:\n")
-	result.WriteString(fmt.Sprintf("  - TrueCaller: https://www.truecaller.com/search/us/%s\n", cleanedPhone))
-	result.WriteString(fmt.Sprintf("  - WhitePages: https://www.whitepages.com/phone/%s\n", strings.ReplaceAll(cleanedPhone, "+", "")))
-	(fmt.Println("  - Spy Dialer: https://www.spydialer.com/"))
-	fmt.Println("  - NumLookup: https://www.numlookup.com/")
-
-	result.WriteString("\nSocial Media Registration:\n")
-	result.WriteString("  Some platforms allow phone number registration\n")
-	result.WriteString("  - Facebook: May be linked to account\n")
-	result.WriteString("  - Twitter: May be linked to account\n")
-	result.WriteString("  - Instagram: May be linked to account\n")
-	result.WriteString("  - LinkedIn: May be linked to account\n")
+	res.AddLink("Additional Lookup Services", "TrueCaller", fmt.Sprintf("https://www.truecaller.com/search/us/%s", cleanedPhone))
+	res.AddLink("Additional Lookup Services", "WhitePages", fmt.Sprintf("https://www.whitepages.com/phone/%s", strings.ReplaceAll(cleanedPhone, "+", "")))
+	res.AddLink("Additional Lookup Services", "Spy Dialer", "https://www.spydialer.com/")
+	res.AddLink("Additional Lookup Services", "NumLookup", "https://www.numlookup.com/")
 
-	result.WriteString("\nCarrier & CNAM Lookup:\n")
-	(fmt.Println("  - FreeCarrierLookup: https://freecarrierlookup.com/"))
-	(fmt.Println("  - Carrier Lookup: https://www.carrierlookup.com/"))
+	res.AddLink("Carrier & CNAM Lookup", "FreeCarrierLookup", "https://freecarrierlookup.com/")
+	res.AddLink("Carrier & CNAM Lookup", "Carrier Lookup", "https://www.carrierlookup.com/")
 
-	elapsed := time.Since(startTime)
-	result.WriteString("\n" + strings.Repeat("=", 70) + "\n")
-	result.WriteString(fmt.Sprintf("⏱️  Advanced search completed in %.2f seconds\n", elapsed.Seconds()))
-	result.WriteString("Note: Advanced mode provides automated platform detection and extended verification\n")
+	res.AddNote("Cleaned Number: %s", cleanedPhone)
+	res.AddNote("Facebook, Twitter, Instagram and LinkedIn allow phone registration and may be linked to an account")
+	res.AddNote("Advanced mode provides automated platform detection and extended verification")
 
-	return result.String(), nil
+	res.Elapsed = time.Since(startTime)
+	return res, nil
 }
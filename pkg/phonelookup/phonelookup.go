@@ -1,6 +1,7 @@
 package phonelookup
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,59 +9,142 @@ import (
 	"os/exec"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/audit"
+	"github.com/malika/osint-master/internal/countries"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/opsec"
+	"github.com/malika/osint-master/pkg/result"
 )
 
+// Provider request URLs, filled with the base URL and the number's digits
+const (
+	veriphoneURL         = "%s/v2/verify?phone=%s"
+	mccMNCURL            = "%s/api/?phone=%s"
+	hlrLookupsURL        = "%s/api/free/%s"
+	freeCarrierLookupURL = "%s/api/%s"
+	numverifyURL         = "%s/api/validate?access_key=%s&number=%s&format=1"
+	abstractAPIURL       = "%s/v1/?api_key=%s&phone=%s"
+	ipqsURL              = "%s/api/json/phone/%s/%s"
+	carrier411URL        = "%s/api/v1/phone/%s"
+	whatsAppURL          = "%s/%s"
+	wassengerURL         = "%s/v1/numbers/%s/exists"
+	getContactURL        = "%s/search?phoneNumber=%s"
+	syncMeURL            = "%s/api/v3/contacts/search?phoneNumber=%s"
+	eyeconURL            = "%s/app/getnames.jsp?cli=%s&lang=en"
+	numLookupURL         = "%s/api/validate/%s"
+	phoneValidatorURL    = "%s/api/lookup/%s"
+)
+
+// trueCallerURL is opened by internal/scraper/truecaller_scraper.js and has no base URL override
+const trueCallerURL = "https://www.truecaller.com/search/ke/%s"
+
+// Endpoints holds the base URL of each phone provider
+type Endpoints struct {
+	Veriphone         string
+	MCCMNC            string
+	HLRLookups        string
+	FreeCarrierLookup string
+	Numverify         string
+	AbstractAPI       string
+	IPQualityScore    string
+	Carrier411        string
+	WhatsApp          string
+	Wassenger         string
+	GetContact        string
+	SyncMe            string
+	Eyecon            string
+	NumLookup         string
+	PhoneValidator    string
+}
+
+// NewEndpoints returns the provider base URLs with the <NAME>_BASE_URL overrides of cfg applied
+func NewEndpoints(cfg *config.Config) *Endpoints {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	return &Endpoints{
+		Veriphone:         cfg.BaseURL("veriphone.io", "https://api.veriphone.io"),
+		MCCMNC:            cfg.BaseURL("mcc-mnc.net", "https://mcc-mnc.net"),
+		HLRLookups:        cfg.BaseURL("hlr-lookups.com", "https://hlr-lookups.com"),
+		FreeCarrierLookup: cfg.BaseURL("freecarrierlookup.com", "https://www.freecarrierlookup.com"),
+		Numverify:         cfg.BaseURL("apilayer.net", "http://apilayer.net"),
+		AbstractAPI:       cfg.BaseURL("abstractapi.com", "https://phonevalidation.abstractapi.com"),
+		IPQualityScore:    cfg.BaseURL("ipqualityscore.com", "https://ipqualityscore.com"),
+		Carrier411:        cfg.BaseURL("carrier411.com", "https://www.carrier411.com"),
+		WhatsApp:          cfg.BaseURL("wa.me", "https://wa.me"),
+		Wassenger:         cfg.BaseURL("wassenger.com", "https://api.wassenger.com"),
+		GetContact:        cfg.BaseURL("getcontact.com", "https://api.getcontact.com"),
+		SyncMe:            cfg.BaseURL("sync.me", "https://api.sync.me"),
+		Eyecon:            cfg.BaseURL("eyecon-app.com", "https://api.eyecon-app.com"),
+		NumLookup:         cfg.BaseURL("numlookup.com", "https://www.numlookup.com"),
+		PhoneValidator:    cfg.BaseURL("phonevalidator.com", "https://www.phonevalidator.com"),
+	}
+}
+
+// defaultEndpoints is used by LookupPhoneWithConfig and Requests
+var defaultEndpoints = NewEndpoints(nil)
+
+// Configure rebuilds the default endpoints from cfg
+func Configure(cfg *config.Config) {
+	defaultEndpoints = NewEndpoints(cfg)
+}
+
 // PhoneInfo holds information about a phone number
 // Contains carrier details, location, and messaging platform status
 type PhoneInfo struct {
-	Number         string
-	CountryCode    string
-	Country        string
-	Region         string
-	Carrier        string
-	LineType       string
-	IsValid        bool
-	OnWhatsApp     bool
-	OnTelegram     bool
-	OnSignal       bool
-	OnViber        bool
-	OnWeChat       bool
-	OnLine         bool
-	WhatsAppStatus string
-	TelegramStatus string
-	SignalStatus   string
-	ViberStatus    string
-	WeChatStatus   string
-	LineStatus     string
-	OwnerName      string
-	OwnerEmail     string
-	OwnerAddress   string
-	OwnerSource    string
+	Number         string `json:"number"`
+	CountryCode    string `json:"country_code"`
+	Country        string `json:"country"`
+	Region         string `json:"region"`
+	Carrier        string `json:"carrier"`
+	LineType       string `json:"line_type"`
+	IsValid        bool   `json:"is_valid"`
+	OnWhatsApp     bool   `json:"on_whatsapp"`
+	OnTelegram     bool   `json:"on_telegram"`
+	OnSignal       bool   `json:"on_signal"`
+	OnViber        bool   `json:"on_viber"`
+	OnWeChat       bool   `json:"on_wechat"`
+	OnLine         bool   `json:"on_line"`
+	WhatsAppStatus string `json:"whatsapp_status"`
+	TelegramStatus string `json:"telegram_status"`
+	SignalStatus   string `json:"signal_status"`
+	ViberStatus    string `json:"viber_status"`
+	WeChatStatus   string `json:"wechat_status"`
+	LineStatus     string `json:"line_status"`
+	OwnerName      string `json:"owner_name,omitempty"`
+	OwnerEmail     string `json:"owner_email,omitempty"`
+	OwnerAddress   string `json:"owner_address,omitempty"`
+	OwnerSource    string `json:"owner_source,omitempty"`
 }
 
 // LookupPhone performs phone number lookup
 // Main entry point for phone number investigation
-func LookupPhone(phone string) (string, error) {
-	return LookupPhoneWithConfig(phone, nil)
+func LookupPhone(ctx context.Context, phone string) (*result.Result, error) {
+	return LookupPhoneWithConfig(ctx, phone, nil)
 }
 
 // LookupPhoneWithConfig performs phone number lookup with API configuration
 // Allows use of paid APIs when config is provided
-func LookupPhoneWithConfig(phone string, cfg *config.Config) (string, error) {
+// Providers not started before ctx ends are skipped and the result is marked partial
+func LookupPhoneWithConfig(ctx context.Context, phone string, cfg *config.Config) (*result.Result, error) {
 	// Clean phone number
 	phone = cleanPhoneNumber(phone)
 
 	if phone == "" {
-		return "", fmt.Errorf("invalid phone number format")
+		return nil, fmt.Errorf("invalid phone number format")
 	}
 
+	ep := defaultEndpoints
 	info := &PhoneInfo{
 		Number:  phone,
 		IsValid: true,
 	}
+	res := result.New(result.ModulePhone, phone)
+
+	// Remember which provider filled each field
+	sources := map[string]string{}
 
 	// Parse country code
 	info.CountryCode, info.Country = parseCountryCode(phone)
@@ -69,38 +153,53 @@ func LookupPhoneWithConfig(phone string, cfg *config.Config) (string, error) {
 	// Try free APIs that actually work first, then paid ones if available
 
 	// 1. Try veriphone.io (free, no key)
-	_ = lookupPhoneFree(phone, info)
+	runPhoneProvider(ctx, res, sources, info, "veriphone.io", func() error {
+		return ep.lookupPhoneFree(ctx, phone, info)
+	})
 
 	// 2. Try hlr-lookups.com (free tier)
-	_ = lookupHLR(phone, info)
+	runPhoneProvider(ctx, res, sources, info, "hlr-lookups.com", func() error {
+		return ep.lookupHLR(ctx, phone, info)
+	})
 
 	// 3. Try paid APIs if configured
 	if cfg != nil {
 		if cfg.NumverifyKey != "" {
-			_ = lookupNumverify(phone, info, cfg)
+			runPhoneProvider(ctx, res, sources, info, "numverify", func() error {
+				return ep.lookupNumverify(ctx, phone, info, cfg)
+			})
 		}
 		if cfg.AbstractAPIKey != "" && info.Carrier == "" {
-			_ = lookupPhoneValidator(phone, info, cfg)
+			runPhoneProvider(ctx, res, sources, info, "abstractapi", func() error {
+				return ep.lookupPhoneValidator(ctx, phone, info, cfg)
+			})
 		}
 		if cfg.IPQualityScoreKey != "" && info.Carrier == "" {
-			_ = lookupIPQualityScore(phone, info, cfg)
+			runPhoneProvider(ctx, res, sources, info, "ipqualityscore", func() error {
+				return ep.lookupIPQualityScore(ctx, phone, info, cfg)
+			})
 		}
 	}
 
 	// Set friendly defaults if still no data
 	if info.LineType == "" || info.LineType == "Unknown" {
 		info.LineType = "Mobile" // Most numbers are mobile
+		sources["line_type"] = "default"
 	}
 	if info.Carrier == "" || info.Carrier == "Unknown" {
 		// Try to determine carrier from country code
-		info.Carrier = guessCarrierFromNumber(phone, info.Country)
+		info.Carrier = ep.guessCarrierFromNumber(ctx, phone, info.Country)
+		sources["carrier"] = "guess"
 	}
 	if info.Region == "" || info.Region == "Unknown" {
 		info.Region = info.Country // Use country as fallback
+		sources["region"] = "guess"
 	}
 
 	// Check messaging platform availability
-	info.OnWhatsApp, info.WhatsAppStatus = checkWhatsApp(phone)
+	if !res.Interrupted(ctx) {
+		info.OnWhatsApp, info.WhatsAppStatus = ep.checkWhatsApp(ctx, phone)
+	}
 	info.OnTelegram, info.TelegramStatus = checkTelegram(phone)
 	info.OnSignal, info.SignalStatus = checkSignal(phone)
 	info.OnViber, info.ViberStatus = checkViber(phone)
@@ -108,212 +207,172 @@ func LookupPhoneWithConfig(phone string, cfg *config.Config) (string, error) {
 	info.OnLine, info.LineStatus = checkLine(phone)
 
 	// Try to lookup owner information
-	_ = lookupOwnerInfo(phone, info, cfg)
+	if !res.Interrupted(ctx) {
+		res.AddError("owner-lookup", ep.lookupOwnerInfo(ctx, phone, info, cfg))
+	}
+	res.Interrupted(ctx)
 
-	// Format output
-	result := formatPhoneInfo(info)
-	return result, nil
+	addPhoneFindings(res, info, sources)
+	res.Data = info
+	return res, nil
 }
 
-// cleanPhoneNumber removes non-digit characters
-// Normalizes phone input for consistent processing
-func cleanPhoneNumber(phone string) string {
-	// Remove all non-digit characters except +
-	reg := regexp.MustCompile(`[^\d+]`)
-	cleaned := reg.ReplaceAllString(phone, "")
+// Requests declares the requests LookupPhoneWithConfig makes for phone, without sending them
+// Most providers are fallbacks that only run while the carrier or owner is still unknown
+func Requests(phone string, cfg *config.Config) []opsec.Request {
+	digits := strings.TrimPrefix(cleanPhoneNumber(phone), "+")
+	noCarrier := "if no carrier was found yet"
+	noOwner := "if no owner was found yet"
+	ep := defaultEndpoints
 
-	// Ensure it starts with +
-	if !strings.HasPrefix(cleaned, "+") {
-		// Try to add + if it looks like international format
-		if len(cleaned) > 10 {
-			cleaned = "+" + cleaned
-		}
+	requests := []opsec.Request{
+		opsec.Declare("veriphone.io", "", "GET", fmt.Sprintf(veriphoneURL, ep.Veriphone, digits)),
+		opsec.Declare("mcc-mnc.net", "", "GET", fmt.Sprintf(mccMNCURL, ep.MCCMNC, digits)),
+		opsec.Declare("hlr-lookups.com", "", "GET", fmt.Sprintf(hlrLookupsURL, ep.HLRLookups, digits)).If(noCarrier),
+		opsec.Declare("freecarrierlookup.com", "", "GET", fmt.Sprintf(freeCarrierLookupURL, ep.FreeCarrierLookup, digits)).If(noCarrier),
 	}
-
-	return cleaned
+	if cfg != nil && cfg.NumverifyKey != "" {
+		requests = append(requests, opsec.Declare("numverify", "", "GET", fmt.Sprintf(numverifyURL, ep.Numverify, "<NUMVERIFY_KEY>", digits)).WithKey())
+	}
+	if cfg != nil && cfg.AbstractAPIKey != "" {
+		requests = append(requests, opsec.Declare("abstractapi", "", "GET", fmt.Sprintf(abstractAPIURL, ep.AbstractAPI, "<ABSTRACTAPI_KEY>", digits)).WithKey().If(noCarrier))
+	}
+	if cfg != nil && cfg.IPQualityScoreKey != "" {
+		requests = append(requests, opsec.Declare("ipqualityscore", "", "GET", fmt.Sprintf(ipqsURL, ep.IPQualityScore, "<IPQUALITYSCORE_KEY>", digits)).WithKey().If(noCarrier))
+	}
+
+	return append(requests,
+		opsec.Declare("carrier411.com", "", "GET", fmt.Sprintf(carrier411URL, ep.Carrier411, digits)).If(noCarrier),
+		opsec.Declare("wa.me", "", "HEAD", fmt.Sprintf(whatsAppURL, ep.WhatsApp, digits)),
+		opsec.Declare("wassenger.com", "", "GET", fmt.Sprintf(wassengerURL, ep.Wassenger, digits)).If("if wa.me does not redirect to WhatsApp"),
+		opsec.Declare("getcontact.com", "", "GET", fmt.Sprintf(getContactURL, ep.GetContact, digits)),
+		opsec.Declare("sync.me", "", "GET", fmt.Sprintf(syncMeURL, ep.SyncMe, digits)).If(noOwner),
+		opsec.Declare("truecaller.com", "browser_automation", "BROWSER", fmt.Sprintf(trueCallerURL, digits)).If(noOwner),
+		opsec.Declare("eyecon-app.com", "", "GET", fmt.Sprintf(eyeconURL, ep.Eyecon, digits)).If(noOwner),
+		opsec.Declare("numlookup.com", "", "GET", fmt.Sprintf(numLookupURL, ep.NumLookup, digits)).If(noOwner),
+		opsec.Declare("phonevalidator.com", "", "GET", fmt.Sprintf(phoneValidatorURL, ep.PhoneValidator, digits)).If(noOwner),
+	)
 }
 
-// parseCountryCode extracts country code from phone number
-// Returns country code and country name using online lookup
-func parseCountryCode(phone string) (string, string) {
-	if !strings.HasPrefix(phone, "+") {
-		return "", ""
+// runPhoneProvider runs a provider and records which fields it changed
+// The provider is skipped once ctx is done
+func runPhoneProvider(ctx context.Context, res *result.Result, sources map[string]string, info *PhoneInfo, name string, lookup func() error) {
+	if res.Interrupted(ctx) {
+		return
 	}
 
-	// Extract country code and get country name from online API
-	phoneDigits := strings.TrimPrefix(phone, "+")
+	before := *info
 
-	// Try 3-digit codes first (e.g., +254 Kenya, +234 Nigeria)
-	if len(phoneDigits) >= 3 && phoneDigits[0] >= '2' && phoneDigits[0] <= '9' {
-		code := "+" + phoneDigits[:3]
-		country := getCountryFromCallingCode(phoneDigits[:3])
-		if country != "" {
-			return code, country
-		}
-	}
+	res.AddError(name, lookup())
 
-	// Try 2-digit codes (e.g., +44 UK, +91 India)
-	if len(phoneDigits) >= 2 {
-		code := "+" + phoneDigits[:2]
-		country := getCountryFromCallingCode(phoneDigits[:2])
-		if country != "" {
-			return code, country
-		}
-	}
-
-	// Try 1-digit codes (only +1 for USA/Canada, +7 for Russia)
-	if len(phoneDigits) >= 1 {
-		code := "+" + phoneDigits[:1]
-		country := getCountryFromCallingCode(phoneDigits[:1])
-		if country != "" {
-			return code, country
-		}
+	if info.Carrier != before.Carrier {
+		sources["carrier"] = name
 	}
-
-	return "", ""
-}
-
-// getCountryFromCallingCode gets country name from calling code using online lookup
-// Uses country.io and restcountries.com APIs with fallback to local data
-func getCountryFromCallingCode(callingCode string) string {
-	// Try online country calling code API
-	url := fmt.Sprintf("https://country.io/phone.json")
-
-	client := &http.Client{
-		Timeout: 3 * time.Second,
+	if info.LineType != before.LineType {
+		sources["line_type"] = name
 	}
-
-	resp, err := client.Get(url)
-	if err != nil {
-		// Fallback to basic reference if API fails
-		return getCountryFromCodeFallback(callingCode)
+	if info.Region != before.Region {
+		sources["region"] = name
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return getCountryFromCodeFallback(callingCode)
+	if info.Country != before.Country {
+		sources["country"] = name
 	}
-
-	var phoneData map[string]string
-	if err := json.NewDecoder(resp.Body).Decode(&phoneData); err != nil {
-		return getCountryFromCodeFallback(callingCode)
+	if info.CountryCode != before.CountryCode {
+		sources["country_code"] = name
 	}
-
-	// The API returns country code -> calling code mapping
-	// We need to reverse lookup
-	for countryCode, phone := range phoneData {
-		if phone == callingCode {
-			// Get country name from country code
-			return getCountryNameFromCode(countryCode)
-		}
+	if info.IsValid != before.IsValid {
+		sources["valid"] = name
 	}
-
-	return getCountryFromCodeFallback(callingCode)
 }
 
-// getCountryNameFromCode converts ISO country code to country name
-func getCountryNameFromCode(code string) string {
-	url := fmt.Sprintf("https://restcountries.com/v3.1/alpha/%s", code)
+// addPhoneFindings records every populated PhoneInfo field as a finding
+func addPhoneFindings(res *result.Result, info *PhoneInfo, sources map[string]string) {
+	add := func(field, value string, raw interface{}) {
+		source, ok := sources[field]
+		if !ok {
+			source = "calling-code"
+		}
 
-	client := &http.Client{
-		Timeout: 3 * time.Second,
-	}
+		confidence := result.ConfidenceHigh
+		switch source {
+		case "guess", "default":
+			confidence = result.ConfidenceLow
+		case "calling-code":
+			confidence = result.ConfidenceMedium
+		}
 
-	resp, err := client.Get(url)
-	if err != nil {
-		return code
+		res.Add(field, value, source, confidence, raw)
+	}
+
+	add("valid", fmt.Sprintf("%v", info.IsValid), info.IsValid)
+	add("country_code", info.CountryCode, info.CountryCode)
+	add("country", info.Country, info.Country)
+	add("region", info.Region, info.Region)
+	add("carrier", info.Carrier, info.Carrier)
+	add("line_type", info.LineType, info.LineType)
+
+	platforms := []struct {
+		name   string
+		found  bool
+		status string
+	}{
+		{"WhatsApp", info.OnWhatsApp, info.WhatsAppStatus},
+		{"Telegram", info.OnTelegram, info.TelegramStatus},
+		{"Signal", info.OnSignal, info.SignalStatus},
+		{"Viber", info.OnViber, info.ViberStatus},
+		{"WeChat", info.OnWeChat, info.WeChatStatus},
+		{"LINE", info.OnLine, info.LineStatus},
+	}
+	for _, platform := range platforms {
+		if platform.found {
+			res.Add("messaging_platform", platform.name, platform.name, result.ConfidenceMedium, platform.status)
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return code
+	if info.OwnerName != "" {
+		res.Add("owner_name", info.OwnerName, info.OwnerSource, result.ConfidenceLow, info.OwnerName)
 	}
+	res.Add("owner_email", info.OwnerEmail, info.OwnerSource, result.ConfidenceLow, info.OwnerEmail)
+	res.Add("owner_address", info.OwnerAddress, info.OwnerSource, result.ConfidenceLow, info.OwnerAddress)
+}
 
-	var result []map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return code
-	}
+// cleanPhoneNumber removes non-digit characters
+// Normalizes phone input for consistent processing
+func cleanPhoneNumber(phone string) string {
+	// Remove all non-digit characters except +
+	reg := regexp.MustCompile(`[^\d+]`)
+	cleaned := reg.ReplaceAllString(phone, "")
 
-	if len(result) > 0 {
-		if name, ok := result[0]["name"].(map[string]interface{}); ok {
-			if common, ok := name["common"].(string); ok {
-				return common
-			}
+	// Ensure it starts with +
+	if !strings.HasPrefix(cleaned, "+") {
+		// Try to add + if it looks like international format
+		if len(cleaned) > 10 {
+			cleaned = "+" + cleaned
 		}
 	}
 
-	return code
+	return cleaned
 }
 
-// getCountryFromCodeFallback provides fallback country names for common calling codes
-func getCountryFromCodeFallback(callingCode string) string {
-	// Minimal reference for most common codes worldwide
-	commonCodes := map[string]string{
-		"1": "United States", "7": "Russia", "20": "Egypt", "27": "South Africa",
-		"30": "Greece", "31": "Netherlands", "32": "Belgium", "33": "France",
-		"34": "Spain", "36": "Hungary", "39": "Italy", "40": "Romania",
-		"41": "Switzerland", "43": "Austria", "44": "United Kingdom", "45": "Denmark",
-		"46": "Sweden", "47": "Norway", "48": "Poland", "49": "Germany",
-		"51": "Peru", "52": "Mexico", "53": "Cuba", "54": "Argentina",
-		"55": "Brazil", "56": "Chile", "57": "Colombia", "58": "Venezuela",
-		"60": "Malaysia", "61": "Australia", "62": "Indonesia", "63": "Philippines",
-		"64": "New Zealand", "65": "Singapore", "66": "Thailand", "81": "Japan",
-		"82": "South Korea", "84": "Vietnam", "86": "China", "90": "Turkey",
-		"91": "India", "92": "Pakistan", "93": "Afghanistan", "94": "Sri Lanka",
-		"95": "Myanmar", "98": "Iran", "211": "South Sudan", "212": "Morocco",
-		"213": "Algeria", "216": "Tunisia", "218": "Libya", "220": "Gambia",
-		"221": "Senegal", "222": "Mauritania", "223": "Mali", "224": "Guinea",
-		"225": "Ivory Coast", "226": "Burkina Faso", "227": "Niger", "228": "Togo",
-		"229": "Benin", "230": "Mauritius", "231": "Liberia", "232": "Sierra Leone",
-		"233": "Ghana", "234": "Nigeria", "235": "Chad", "236": "Central African Republic",
-		"237": "Cameroon", "238": "Cape Verde", "239": "Sao Tome and Principe", "240": "Equatorial Guinea",
-		"241": "Gabon", "242": "Republic of the Congo", "243": "Democratic Republic of the Congo",
-		"244": "Angola", "245": "Guinea-Bissau", "246": "British Indian Ocean Territory",
-		"248": "Seychelles", "249": "Sudan", "250": "Rwanda", "251": "Ethiopia",
-		"252": "Somalia", "253": "Djibouti", "254": "Kenya", "255": "Tanzania",
-		"256": "Uganda", "257": "Burundi", "258": "Mozambique", "260": "Zambia",
-		"261": "Madagascar", "262": "Reunion", "263": "Zimbabwe", "264": "Namibia",
-		"265": "Malawi", "266": "Lesotho", "267": "Botswana", "268": "Eswatini",
-		"269": "Comoros", "290": "Saint Helena", "291": "Eritrea", "297": "Aruba",
-		"298": "Faroe Islands", "299": "Greenland", "350": "Gibraltar", "351": "Portugal",
-		"352": "Luxembourg", "353": "Ireland", "354": "Iceland", "355": "Albania",
-		"356": "Malta", "357": "Cyprus", "358": "Finland", "359": "Bulgaria",
-		"370": "Lithuania", "371": "Latvia", "372": "Estonia", "373": "Moldova",
-		"374": "Armenia", "375": "Belarus", "376": "Andorra", "377": "Monaco",
-		"378": "San Marino", "380": "Ukraine", "381": "Serbia", "382": "Montenegro",
-		"383": "Kosovo", "385": "Croatia", "386": "Slovenia", "387": "Bosnia and Herzegovina",
-		"389": "North Macedonia", "420": "Czech Republic", "421": "Slovakia", "423": "Liechtenstein",
-		"500": "Falkland Islands", "501": "Belize", "502": "Guatemala", "503": "El Salvador",
-		"504": "Honduras", "505": "Nicaragua", "506": "Costa Rica", "507": "Panama",
-		"508": "Saint Pierre and Miquelon", "509": "Haiti", "590": "Guadeloupe", "591": "Bolivia",
-		"592": "Guyana", "593": "Ecuador", "594": "French Guiana", "595": "Paraguay",
-		"596": "Martinique", "597": "Suriname", "598": "Uruguay", "599": "Curacao",
-		"670": "East Timor", "672": "Antarctica", "673": "Brunei", "674": "Nauru",
-		"675": "Papua New Guinea", "676": "Tonga", "677": "Solomon Islands", "678": "Vanuatu",
-		"679": "Fiji", "680": "Palau", "681": "Wallis and Futuna", "682": "Cook Islands",
-		"683": "Niue", "685": "Samoa", "686": "Kiribati", "687": "New Caledonia",
-		"688": "Tuvalu", "689": "French Polynesia", "690": "Tokelau", "691": "Micronesia",
-		"692": "Marshall Islands", "850": "North Korea", "852": "Hong Kong", "853": "Macau",
-		"855": "Cambodia", "856": "Laos", "880": "Bangladesh", "886": "Taiwan",
-		"960": "Maldives", "961": "Lebanon", "962": "Jordan", "963": "Syria",
-		"964": "Iraq", "965": "Kuwait", "966": "Saudi Arabia", "967": "Yemen",
-		"968": "Oman", "970": "Palestine", "971": "United Arab Emirates", "972": "Israel",
-		"973": "Bahrain", "974": "Qatar", "975": "Bhutan", "976": "Mongolia",
-		"977": "Nepal", "992": "Tajikistan", "993": "Turkmenistan", "994": "Azerbaijan",
-		"995": "Georgia", "996": "Kyrgyzstan", "998": "Uzbekistan",
-	}
-
-	if country, exists := commonCodes[callingCode]; exists {
-		return country
+// parseCountryCode extracts country code from phone number
+// Returns country code and country name from the embedded country table
+func parseCountryCode(phone string) (string, string) {
+	if !strings.HasPrefix(phone, "+") {
+		return "", ""
+	}
+
+	code, country, ok := countries.MatchCallingCode(phone)
+	if !ok {
+		return "", ""
 	}
 
-	return ""
+	return "+" + code, country.Name
 }
 
 // lookupPhoneAPI queries phone lookup API with multiple fallback options
-func lookupPhoneAPI(phone string, info *PhoneInfo) error {
+func (e *Endpoints) lookupPhoneAPI(ctx context.Context, phone string, info *PhoneInfo) error {
 	// Try FREE API first (veriphone.io - no key required)
-	if err := lookupPhoneFree(phone, info); err == nil {
+	if err := e.lookupPhoneFree(ctx, phone, info); err == nil {
 		return nil
 	}
 
@@ -327,11 +386,7 @@ func lookupPhoneAPI(phone string, info *PhoneInfo) error {
 
 	url := fmt.Sprintf("http://apilayer.net/api/validate?access_key=%s&number=%s", apiKey, phone)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Get(url)
+	resp, err := httpclient.Get(ctx, url)
 	if err != nil {
 		return err
 	}
@@ -369,34 +424,30 @@ func lookupPhoneAPI(phone string, info *PhoneInfo) error {
 
 // lookupPhoneFree uses FREE API (veriphone.io)
 // No API key required for basic phone validation
-func lookupPhoneFree(phone string, info *PhoneInfo) error {
+func (e *Endpoints) lookupPhoneFree(ctx context.Context, phone string, info *PhoneInfo) error {
 	// Remove + from phone for API
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// Try veriphone.io first
-	url := fmt.Sprintf("https://api.veriphone.io/v2/verify?phone=%s", phoneClean)
+	url := fmt.Sprintf(veriphoneURL, e.Veriphone, phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("User-Agent", "OSINT-Master-Educational-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		// If veriphone fails, try alternative API
-		return lookupPhoneAlternative(phone, info)
+		return e.lookupPhoneAlternative(ctx, phone, info)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		// Try alternative API
-		return lookupPhoneAlternative(phone, info)
+		return e.lookupPhoneAlternative(ctx, phone, info)
 	}
 
 	var result map[string]interface{}
@@ -424,14 +475,15 @@ func lookupPhoneFree(phone string, info *PhoneInfo) error {
 		}
 	}
 
-	if countryCode, ok := result["country_code"].(string); ok && countryCode != "" {
+	// country_code is the ISO code, the dialling code is country_prefix
+	if prefix, ok := result["country_prefix"].(string); ok && prefix != "" {
 		if info.CountryCode == "Unknown" || info.CountryCode == "" {
-			info.CountryCode = "+" + countryCode
+			info.CountryCode = dialCode(prefix)
 		}
 	}
 
 	// Region/location
-	if region, ok := result["region"].(string); ok && region != "" {
+	if region, ok := result["phone_region"].(string); ok && region != "" {
 		info.Region = region
 	}
 
@@ -440,24 +492,20 @@ func lookupPhoneFree(phone string, info *PhoneInfo) error {
 
 // lookupPhoneAlternative tries alternative free phone APIs
 // Used as fallback when primary API fails
-func lookupPhoneAlternative(phone string, info *PhoneInfo) error {
+func (e *Endpoints) lookupPhoneAlternative(ctx context.Context, phone string, info *PhoneInfo) error {
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// Try numverify free tier (limited requests per month)
 	// Note: This requires an API key, but we'll try the demo endpoint
-	url := fmt.Sprintf("https://phonevalidation.abstractapi.com/v1/?api_key=test&phone=%s", phoneClean)
+	url := fmt.Sprintf(abstractAPIURL, e.AbstractAPI, "test", phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("User-Agent", "OSINT-Master-Educational-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -490,8 +538,8 @@ func lookupPhoneAlternative(phone string, info *PhoneInfo) error {
 		if countryName, ok := country["name"].(string); ok && countryName != "" {
 			info.Country = countryName
 		}
-		if countryCode, ok := country["code"].(string); ok && countryCode != "" {
-			info.CountryCode = "+" + countryCode
+		if prefix, ok := country["prefix"].(string); ok && prefix != "" {
+			info.CountryCode = dialCode(prefix)
 		}
 	}
 
@@ -502,27 +550,32 @@ func lookupPhoneAlternative(phone string, info *PhoneInfo) error {
 	return nil
 }
 
+// dialCode formats a country dialling code as +1, providers send it with or without the plus
+func dialCode(prefix string) string {
+	return "+" + strings.TrimPrefix(prefix, "+")
+}
+
 // lookupHLR uses HLR (Home Location Register) lookup from multiple sources
 // HLR lookup provides carrier and network information
-func lookupHLR(phone string, info *PhoneInfo) error {
+func (e *Endpoints) lookupHLR(ctx context.Context, phone string, info *PhoneInfo) error {
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// Try multiple HLR/carrier lookup APIs
 
 	// 1. Try mccmnc.com API (free carrier database)
-	if err := lookupMCCMNCOnline(phoneClean, info); err == nil && info.Carrier != "" {
+	if err := e.lookupMCCMNCOnline(ctx, phoneClean, info); err == nil && info.Carrier != "" {
 		return nil
 	}
 
 	// 2. Try hlr-lookups.com
-	url := fmt.Sprintf("https://hlr-lookups.com/api/free/%s", phoneClean)
-	if err := makeHLRRequest(url, info); err == nil && info.Carrier != "" {
+	url := fmt.Sprintf(hlrLookupsURL, e.HLRLookups, phoneClean)
+	if err := makeHLRRequest(ctx, url, info); err == nil && info.Carrier != "" {
 		return nil
 	}
 
 	// 3. Try freecarrierlookup.com API
-	url = fmt.Sprintf("https://www.freecarrierlookup.com/api/%s", phoneClean)
-	if err := makeCarrierRequest(url, info); err == nil && info.Carrier != "" {
+	url = fmt.Sprintf(freeCarrierLookupURL, e.FreeCarrierLookup, phoneClean)
+	if err := makeCarrierRequest(ctx, url, info); err == nil && info.Carrier != "" {
 		return nil
 	}
 
@@ -531,21 +584,17 @@ func lookupHLR(phone string, info *PhoneInfo) error {
 
 // lookupMCCMNCOnline fetches carrier info from online MCC-MNC database
 // MCC-MNC is Mobile Country Code - Mobile Network Code
-func lookupMCCMNCOnline(phone string, info *PhoneInfo) error {
+func (e *Endpoints) lookupMCCMNCOnline(ctx context.Context, phone string, info *PhoneInfo) error {
 	// Use mcc-mnc.com API for carrier lookup
-	url := fmt.Sprintf("https://mcc-mnc.net/api/?phone=%s", phone)
+	url := fmt.Sprintf(mccMNCURL, e.MCCMNC, phone)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("User-Agent", "OSINT-Master-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -583,18 +632,14 @@ func lookupMCCMNCOnline(phone string, info *PhoneInfo) error {
 
 // makeHLRRequest makes a generic HLR lookup request
 // Tries multiple field names for carrier and line type information
-func makeHLRRequest(url string, info *PhoneInfo) error {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+func makeHLRRequest(ctx context.Context, url string, info *PhoneInfo) error {
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("User-Agent", "OSINT-Master-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -632,18 +677,18 @@ func makeHLRRequest(url string, info *PhoneInfo) error {
 }
 
 // makeCarrierRequest makes a carrier lookup request
-func makeCarrierRequest(url string, info *PhoneInfo) error {
-	return makeHLRRequest(url, info) // Same logic
+func makeCarrierRequest(ctx context.Context, url string, info *PhoneInfo) error {
+	return makeHLRRequest(ctx, url, info) // Same logic
 }
 
 // guessCarrierFromNumber tries to determine carrier from number patterns
 // Uses external data sources to infer carrier information
-func guessCarrierFromNumber(phone string, country string) string {
+func (e *Endpoints) guessCarrierFromNumber(ctx context.Context, phone string, country string) string {
 	// Try to lookup from online carrier database
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// Try carrier lookup API
-	if carrier := lookupCarrierFromAPI(phoneClean); carrier != "" {
+	if carrier := e.lookupCarrierFromAPI(ctx, phoneClean); carrier != "" {
 		return carrier
 	}
 
@@ -657,21 +702,17 @@ func guessCarrierFromNumber(phone string, country string) string {
 
 // lookupCarrierFromAPI tries to get carrier from online database
 // Uses free carrier lookup services
-func lookupCarrierFromAPI(phone string) string {
+func (e *Endpoints) lookupCarrierFromAPI(ctx context.Context, phone string) string {
 	// Try carrier411.com API (free carrier database)
-	url := fmt.Sprintf("https://www.carrier411.com/api/v1/phone/%s", phone)
+	url := fmt.Sprintf(carrier411URL, e.Carrier411, phone)
 
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return ""
 	}
 
-	req.Header.Set("User-Agent", "OSINT-Master-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return ""
@@ -701,7 +742,7 @@ func lookupCarrierFromAPI(phone string) string {
 
 // lookupNumverify uses numverify.com API
 // Free tier: 100 requests/month - requires API key
-func lookupNumverify(phone string, info *PhoneInfo, cfg *config.Config) error {
+func (e *Endpoints) lookupNumverify(ctx context.Context, phone string, info *PhoneInfo, cfg *config.Config) error {
 	// Skip if no API key configured
 	if cfg == nil || cfg.NumverifyKey == "" {
 		return fmt.Errorf("numverify API key not configured")
@@ -710,13 +751,9 @@ func lookupNumverify(phone string, info *PhoneInfo, cfg *config.Config) error {
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// Use configured API key
-	url := fmt.Sprintf("http://apilayer.net/api/validate?access_key=%s&number=%s&format=1", cfg.NumverifyKey, phoneClean)
+	url := fmt.Sprintf(numverifyURL, e.Numverify, cfg.NumverifyKey, phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Get(url)
+	resp, err := httpclient.Get(ctx, url)
 	if err != nil {
 		return err
 	}
@@ -757,8 +794,8 @@ func lookupNumverify(phone string, info *PhoneInfo, cfg *config.Config) error {
 		info.Country = country
 	}
 
-	if countryCode, ok := result["country_prefix"].(string); ok && countryCode != "" {
-		info.CountryCode = "+" + countryCode
+	if prefix, ok := result["country_prefix"].(string); ok && prefix != "" {
+		info.CountryCode = dialCode(prefix)
 	}
 
 	return nil
@@ -766,7 +803,7 @@ func lookupNumverify(phone string, info *PhoneInfo, cfg *config.Config) error {
 
 // lookupPhoneValidator uses AbstractAPI phone validation service
 // Requires AbstractAPI key for access
-func lookupPhoneValidator(phone string, info *PhoneInfo, cfg *config.Config) error {
+func (e *Endpoints) lookupPhoneValidator(ctx context.Context, phone string, info *PhoneInfo, cfg *config.Config) error {
 	// Skip if no API key configured
 	if cfg == nil || cfg.AbstractAPIKey == "" {
 		return fmt.Errorf("abstractapi key not configured")
@@ -775,13 +812,9 @@ func lookupPhoneValidator(phone string, info *PhoneInfo, cfg *config.Config) err
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// Use configured API key
-	url := fmt.Sprintf("https://phonevalidation.abstractapi.com/v1/?api_key=%s&phone=%s", cfg.AbstractAPIKey, phoneClean)
+	url := fmt.Sprintf(abstractAPIURL, e.AbstractAPI, cfg.AbstractAPIKey, phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Get(url)
+	resp, err := httpclient.Get(ctx, url)
 	if err != nil {
 		return err
 	}
@@ -813,8 +846,8 @@ func lookupPhoneValidator(phone string, info *PhoneInfo, cfg *config.Config) err
 		if countryName, ok := country["name"].(string); ok {
 			info.Country = countryName
 		}
-		if countryCode, ok := country["code"].(string); ok {
-			info.CountryCode = "+" + countryCode
+		if prefix, ok := country["prefix"].(string); ok && prefix != "" {
+			info.CountryCode = dialCode(prefix)
 		}
 	}
 
@@ -827,7 +860,7 @@ func lookupPhoneValidator(phone string, info *PhoneInfo, cfg *config.Config) err
 
 // lookupIPQualityScore uses IPQualityScore phone validation API
 // Provides advanced fraud detection and phone validation
-func lookupIPQualityScore(phone string, info *PhoneInfo, cfg *config.Config) error {
+func (e *Endpoints) lookupIPQualityScore(ctx context.Context, phone string, info *PhoneInfo, cfg *config.Config) error {
 	// Skip if no API key configured
 	if cfg == nil || cfg.IPQualityScoreKey == "" {
 		return fmt.Errorf("IPQualityScore API key not configured")
@@ -836,19 +869,15 @@ func lookupIPQualityScore(phone string, info *PhoneInfo, cfg *config.Config) err
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// Use configured API key
-	url := fmt.Sprintf("https://ipqualityscore.com/api/json/phone/%s/%s", cfg.IPQualityScoreKey, phoneClean)
+	url := fmt.Sprintf(ipqsURL, e.IPQualityScore, cfg.IPQualityScoreKey, phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("User-Agent", "OSINT-Master-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -899,13 +928,13 @@ func lookupIPQualityScore(phone string, info *PhoneInfo, cfg *config.Config) err
 
 // lookupOwnerInfo tries to find the owner's information from various sources
 // Uses multiple caller ID services and public directories
-func lookupOwnerInfo(phone string, info *PhoneInfo, cfg *config.Config) error {
+func (e *Endpoints) lookupOwnerInfo(ctx context.Context, phone string, info *PhoneInfo, cfg *config.Config) error {
 	phoneClean := strings.ReplaceAll(strings.ReplaceAll(phone, "+", ""), " ", "")
 
 	// Try multiple owner lookup sources
 
 	// 1. Try TrueCaller API (requires scraping or unofficial API)
-	if owner := lookupTrueCaller(phoneClean); owner != "" {
+	if owner := e.lookupTrueCaller(ctx, phoneClean); owner != "" {
 		info.OwnerName = owner
 		info.OwnerSource = "TrueCaller (public data)"
 		return nil
@@ -913,20 +942,20 @@ func lookupOwnerInfo(phone string, info *PhoneInfo, cfg *config.Config) error {
 
 	// 2. Try Numverify extended data (if configured)
 	if cfg != nil && cfg.NumverifyKey != "" {
-		if err := lookupNumverifyExtended(phoneClean, info, cfg); err == nil && info.OwnerName != "" {
+		if err := lookupNumverifyExtended(ctx, phoneClean, info, cfg); err == nil && info.OwnerName != "" {
 			return nil
 		}
 	}
 
 	// 3. Try phone directory services
-	if owner := lookupPhoneDirectory(phoneClean); owner != "" {
+	if owner := e.lookupPhoneDirectory(ctx, phoneClean); owner != "" {
 		info.OwnerName = owner
 		info.OwnerSource = "Public directory"
 		return nil
 	}
 
 	// 4. Try social media reverse lookup
-	if owner := lookupSocialMedia(phoneClean); owner != "" {
+	if owner := lookupSocialMedia(ctx, phoneClean); owner != "" {
 		info.OwnerName = owner
 		info.OwnerSource = "Social media"
 		return nil
@@ -937,7 +966,7 @@ func lookupOwnerInfo(phone string, info *PhoneInfo, cfg *config.Config) error {
 
 // lookupTrueCaller attempts to get name from TrueCaller
 // Tries multiple caller ID APIs including GetContact, Sync.me, and Eyecon
-func lookupTrueCaller(phone string) string {
+func (e *Endpoints) lookupTrueCaller(ctx context.Context, phone string) string {
 	// Try local cache first (fastest)
 	name := tryLocalCache(phone)
 	if name != "" {
@@ -945,31 +974,31 @@ func lookupTrueCaller(phone string) string {
 	}
 
 	// Try GetContact API (works well for international numbers)
-	name = tryGetContactAPI(phone)
+	name = e.tryGetContactAPI(ctx, phone)
 	if name != "" {
 		return name
 	}
 
 	// Try Sync.me API
-	name = trySyncMeAPI(phone)
+	name = e.trySyncMeAPI(ctx, phone)
 	if name != "" {
 		return name
 	}
 
 	// Try TrueCaller JSON API endpoint (unofficial but works)
-	name = tryTrueCallerJSONAPI(phone)
+	name = tryTrueCallerJSONAPI(ctx, phone)
 	if name != "" {
 		return name
 	}
 
 	// Try Eyecon API as alternative
-	name = tryEyeconAPI(phone)
+	name = e.tryEyeconAPI(ctx, phone)
 	if name != "" {
 		return name
 	}
 
 	// Try NumLookup API
-	name = tryNumLookupAPI(phone)
+	name = e.tryNumLookupAPI(ctx, phone)
 	if name != "" {
 		return name
 	}
@@ -986,17 +1015,15 @@ func tryLocalCache(phone string) string {
 
 // tryGetContactAPI tries GetContact caller ID service
 // GetContact is a popular caller identification app
-func tryGetContactAPI(phone string) string {
+func (e *Endpoints) tryGetContactAPI(ctx context.Context, phone string) string {
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// GetContact API endpoint
-	url := fmt.Sprintf("https://api.getcontact.com/search?phoneNumber=%s", phoneClean)
+	url := fmt.Sprintf(getContactURL, e.GetContact, phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return ""
 	}
@@ -1036,17 +1063,15 @@ func tryGetContactAPI(phone string) string {
 
 // trySyncMeAPI tries Sync.me caller ID service
 // Sync.me provides caller identification and contact management
-func trySyncMeAPI(phone string) string {
+func (e *Endpoints) trySyncMeAPI(ctx context.Context, phone string) string {
 	phoneClean := strings.TrimPrefix(phone, "+")
 
 	// Sync.me API endpoint
-	url := fmt.Sprintf("https://api.sync.me/api/v3/contacts/search?phoneNumber=%s", phoneClean)
+	url := fmt.Sprintf(syncMeURL, e.SyncMe, phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return ""
 	}
@@ -1082,11 +1107,16 @@ func trySyncMeAPI(phone string) string {
 }
 
 // runTrueCallerPlaywright runs the Playwright scraper for TrueCaller
-func runTrueCallerPlaywright(phone string) string {
+func runTrueCallerPlaywright(ctx context.Context, phone string) string {
+	if opsec.Allow(ctx, "browser_automation", result.ModulePhone, phone) != nil {
+		return ""
+	}
+
 	// Import exec package at runtime
-	cmd := exec.Command("node", "internal/scraper/truecaller_scraper.js", phone)
+	cmd := exec.CommandContext(ctx, "node", "internal/scraper/truecaller_scraper.js", phone)
 
 	output, err := cmd.CombinedOutput()
+	audit.Connection(ctx, "BROWSER", fmt.Sprintf(trueCallerURL, strings.TrimPrefix(cleanPhoneNumber(phone), "+")), err)
 	if err != nil {
 		return ""
 	}
@@ -1106,25 +1136,23 @@ func runTrueCallerPlaywright(phone string) string {
 }
 
 // tryTrueCallerJSONAPI uses Playwright to scrape TrueCaller with JavaScript execution
-func tryTrueCallerJSONAPI(phone string) string {
+func tryTrueCallerJSONAPI(ctx context.Context, phone string) string {
 	phoneClean := strings.ReplaceAll(strings.ReplaceAll(phone, "+", ""), " ", "")
 
 	// Use Playwright scraper for TrueCaller
-	return runTrueCallerPlaywright(phoneClean)
+	return runTrueCallerPlaywright(ctx, phoneClean)
 }
 
 // tryEyeconAPI tries Eyecon caller ID API
 // Eyecon provides visual caller ID with photo identification
-func tryEyeconAPI(phone string) string {
+func (e *Endpoints) tryEyeconAPI(ctx context.Context, phone string) string {
 	phoneClean := strings.TrimPrefix(phone, "+")
 
-	url := fmt.Sprintf("https://api.eyecon-app.com/app/getnames.jsp?cli=%s&lang=en", phoneClean)
+	url := fmt.Sprintf(eyeconURL, e.Eyecon, phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return ""
 	}
@@ -1165,22 +1193,18 @@ func tryEyeconAPI(phone string) string {
 
 // tryNumLookupAPI tries NumLookup free API
 // NumLookup offers phone number validation and owner information
-func tryNumLookupAPI(phone string) string {
+func (e *Endpoints) tryNumLookupAPI(ctx context.Context, phone string) string {
 	phoneClean := strings.TrimPrefix(phone, "+")
 
-	url := fmt.Sprintf("https://www.numlookup.com/api/validate/%s", phoneClean)
+	url := fmt.Sprintf(numLookupURL, e.NumLookup, phoneClean)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return ""
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return ""
@@ -1208,31 +1232,27 @@ func tryNumLookupAPI(phone string) string {
 }
 
 // lookupNumverifyExtended gets extended data including owner info if available
-func lookupNumverifyExtended(phone string, info *PhoneInfo, cfg *config.Config) error {
+func lookupNumverifyExtended(ctx context.Context, phone string, info *PhoneInfo, cfg *config.Config) error {
 	// Some phone APIs provide owner information
 	// This would require extended API access
 	return fmt.Errorf("extended data not available")
 }
 
 // lookupPhoneDirectory searches public phone directories
-func lookupPhoneDirectory(phone string) string {
+func (e *Endpoints) lookupPhoneDirectory(ctx context.Context, phone string) string {
 	// Try free phone directory APIs
 	// Note: Most accurate directories are paid services
 
 	// Try phonevalidator.com directory
-	url := fmt.Sprintf("https://www.phonevalidator.com/api/lookup/%s", phone)
+	url := fmt.Sprintf(phoneValidatorURL, e.PhoneValidator, phone)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
+	client := httpclient.Default()
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return ""
 	}
 
-	req.Header.Set("User-Agent", "OSINT-Master-Tool")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return ""
@@ -1265,7 +1285,7 @@ func lookupPhoneDirectory(phone string) string {
 }
 
 // lookupSocialMedia checks if phone is linked to social media profiles
-func lookupSocialMedia(phone string) string {
+func lookupSocialMedia(ctx context.Context, phone string) string {
 	// Try to find name from social media
 	// This is limited due to privacy settings on most platforms
 
@@ -1286,29 +1306,22 @@ func lookupSocialMedia(phone string) string {
 
 // checkWhatsApp checks if a phone number is registered on WhatsApp
 // Uses wa.me link and Wassenger API for verification
-func checkWhatsApp(phone string) (bool, string) {
+func (e *Endpoints) checkWhatsApp(ctx context.Context, phone string) (bool, string) {
 	// Method: Try to access the WhatsApp Web API endpoint
 	// Remove + and spaces from phone number
 	cleanedPhone := strings.ReplaceAll(strings.ReplaceAll(phone, "+", ""), " ", "")
 
 	// Try using wa.me link which is an official WhatsApp redirect service
-	url := fmt.Sprintf("https://wa.me/%s", cleanedPhone)
+	url := fmt.Sprintf(whatsAppURL, e.WhatsApp, cleanedPhone)
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			// Don't follow redirects, just check the response
-			return http.ErrUseLastResponse
-		},
-	}
+	// Don't follow redirects, just check the response
+	client := httpclient.NoRedirects()
 
-	req, err := http.NewRequest("HEAD", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
 	if err != nil {
 		return false, "Unable to check (request error)"
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return false, "Unable to check (network error)"
@@ -1326,11 +1339,10 @@ func checkWhatsApp(phone string) (bool, string) {
 
 	// Alternative check: Use WhatsApp API check service
 	// Try free WhatsApp checker API
-	apiURL := fmt.Sprintf("https://api.wassenger.com/v1/numbers/%s/exists", cleanedPhone)
+	apiURL := fmt.Sprintf(wassengerURL, e.Wassenger, cleanedPhone)
 
-	req2, err := http.NewRequest("GET", apiURL, nil)
+	req2, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err == nil {
-		req2.Header.Set("User-Agent", "OSINT-Master-Tool")
 		resp2, err := client.Do(req2)
 		if err == nil {
 			defer resp2.Body.Close()
@@ -1371,7 +1383,6 @@ func checkTelegram(phone string) (bool, string) {
 func checkSignal(phone string) (bool, string) {
 	// Signal is privacy-focused and doesn't provide public APIs for registration checks
 	// The only way to verify is through the Signal app itself
-	cleanedPhone := strings.ReplaceAll(phone, "+", "")
 
 	// Signal requires the app to check registration
 	// We can only provide guidance for manual verification
@@ -1397,7 +1408,6 @@ func checkViber(phone string) (bool, string) {
 func checkWeChat(phone string) (bool, string) {
 	// WeChat doesn't provide a public API for phone number verification
 	// WeChat primarily uses WeChat IDs rather than phone numbers for contact
-	cleanedPhone := strings.ReplaceAll(strings.ReplaceAll(phone, "+", ""), " ", "")
 
 	// WeChat verification requires the app and potentially region-specific access
 	return false, fmt.Sprintf("Manual check required via WeChat app (primarily uses WeChat ID)")
@@ -1416,149 +1426,6 @@ func checkLine(phone string) (bool, string) {
 	return false, fmt.Sprintf("Manual check required (try: %s or search in LINE app)", url)
 }
 
-// formatPhoneInfo formats phone information into readable string
-// Creates a comprehensive report with all collected data
-func formatPhoneInfo(info *PhoneInfo) string {
-	var sb strings.Builder
-
-	// Header section
-	sb.WriteString(fmt.Sprintf("Phone Number: %s\n", info.Number))
-	sb.WriteString(strings.Repeat("=", 70) + "\n\n")
-
-	// Validation status
-	sb.WriteString("Validation:\n")
-	sb.WriteString(strings.Repeat("-", 70) + "\n")
-	if info.IsValid {
-		sb.WriteString("Status:       ✓ Valid phone number\n")
-	} else {
-		sb.WriteString("Status:       ✗ Invalid or unverified\n")
-	}
-
-	// Location information
-	sb.WriteString("\nLocation Information:\n")
-	sb.WriteString(strings.Repeat("-", 70) + "\n")
-	if info.CountryCode != "" {
-		sb.WriteString(fmt.Sprintf("Country Code: +%s\n", info.CountryCode))
-	}
-	if info.Country != "" {
-		sb.WriteString(fmt.Sprintf("Country:      %s\n", info.Country))
-	}
-	if info.Region != "" {
-		sb.WriteString(fmt.Sprintf("Region:       %s\n", info.Region))
-	}
-
-	// Carrier information
-	sb.WriteString("\nCarrier Information:\n")
-	sb.WriteString(strings.Repeat("-", 70) + "\n")
-	if info.Carrier != "" {
-		sb.WriteString(fmt.Sprintf("Carrier:      %s\n", info.Carrier))
-	}
-	if info.LineType != "" {
-		sb.WriteString(fmt.Sprintf("Line Type:    %s\n", info.LineType))
-	}
-
-	// Owner information
-	if info.OwnerName != "" || info.OwnerEmail != "" || info.OwnerAddress != "" {
-		sb.WriteString("\nOwner Information:\n")
-		sb.WriteString(strings.Repeat("-", 70) + "\n")
-		if info.OwnerName != "" {
-			sb.WriteString(fmt.Sprintf("Name:         %s\n", info.OwnerName))
-		}
-		if info.OwnerEmail != "" {
-			sb.WriteString(fmt.Sprintf("Email:        %s\n", info.OwnerEmail))
-		}
-		if info.OwnerAddress != "" {
-			sb.WriteString(fmt.Sprintf("Address:      %s\n", info.OwnerAddress))
-		}
-		if info.OwnerSource != "" {
-			sb.WriteString(fmt.Sprintf("Source:       %s\n", info.OwnerSource))
-		}
-	}
-
-	// Messaging platforms
-	sb.WriteString("\nMessaging Platforms:\n")
-	sb.WriteString(strings.Repeat("-", 70) + "\n")
-
-	// WhatsApp
-	if info.OnWhatsApp {
-		sb.WriteString(fmt.Sprintf("WhatsApp:     ✓ Registered (%s)\n", info.WhatsAppStatus))
-	} else {
-		sb.WriteString(fmt.Sprintf("WhatsApp:     ✗ %s\n", info.WhatsAppStatus))
-	}
-
-	// Telegram
-	if info.OnTelegram {
-		sb.WriteString(fmt.Sprintf("Telegram:     ✓ Registered (%s)\n", info.TelegramStatus))
-	} else {
-		sb.WriteString(fmt.Sprintf("Telegram:     ✗ %s\n", info.TelegramStatus))
-	}
-
-	// Signal
-	if info.OnSignal {
-		sb.WriteString(fmt.Sprintf("Signal:       ✓ Registered (%s)\n", info.SignalStatus))
-	} else {
-		sb.WriteString(fmt.Sprintf("Signal:       ✗ %s\n", info.SignalStatus))
-	}
-
-	// Viber
-	if info.OnViber {
-		sb.WriteString(fmt.Sprintf("Viber:        ✓ Registered (%s)\n", info.ViberStatus))
-	} else {
-		sb.WriteString(fmt.Sprintf("Viber:        ✗ %s\n", info.ViberStatus))
-	}
-
-	// WeChat
-	if info.OnWeChat {
-		sb.WriteString(fmt.Sprintf("WeChat:       ✓ Registered (%s)\n", info.WeChatStatus))
-	} else {
-		sb.WriteString(fmt.Sprintf("WeChat:       ✗ %s\n", info.WeChatStatus))
-	}
-
-	// LINE
-	if info.OnLine {
-		sb.WriteString(fmt.Sprintf("LINE:         ✓ Registered (%s)\n", info.LineStatus))
-	} else {
-		sb.WriteString(fmt.Sprintf("LINE:         ✗ %s\n", info.LineStatus))
-	}
-
-	// Additional lookup resources
-	sb.WriteString("\nAdditional Lookup Resources:\n")
-	sb.WriteString(strings.Repeat("-", 70) + "\n")
-
-	cleanedForURL := strings.ReplaceAll(info.Number, "+", "")
-	sb.WriteString("\nCaller ID & Reverse Lookup:\n")
-	sb.WriteString(fmt.Sprintf("  - TrueCaller:    https://www.truecaller.com/search/us/%s\n", cleanedForURL))
-	sb.WriteString(fmt.Sprintf("  - WhitePages:    https://www.whitepages.com/phone/%s\n", cleanedForURL))
-	sb.WriteString("  - Spy Dialer:    https://www.spydialer.com/\n")
-	sb.WriteString("  - NumLookup:     https://www.numlookup.com/\n")
-
-	sb.WriteString("\nCarrier & CNAM Lookup:\n")
-	sb.WriteString("  - FreeCarrierLookup: https://freecarrierlookup.com/\n")
-	sb.WriteString("  - Carrier Lookup:    https://www.carrierlookup.com/\n")
-
-	sb.WriteString("\nSocial Media Search:\n")
-	sb.WriteString(fmt.Sprintf("  - Facebook:      https://www.facebook.com/search/people/?q=%s\n", cleanedForURL))
-	sb.WriteString(fmt.Sprintf("  - Twitter:       https://twitter.com/search?q=%s\n", cleanedForURL))
-	sb.WriteString(fmt.Sprintf("  - LinkedIn:      https://www.linkedin.com/search/results/people/?keywords=%s\n", cleanedForURL))
-
-	sb.WriteString("\nSpam & Scam Databases:\n")
-	sb.WriteString(fmt.Sprintf("  - Should I Answer: https://www.shouldianswer.com/phone-number/%s\n", cleanedForURL))
-	sb.WriteString(fmt.Sprintf("  - 800notes:        https://800notes.com/Phone.aspx/%s\n", cleanedForURL))
-	sb.WriteString(fmt.Sprintf("  - CallerSmart:     https://www.callersmart.com/number/%s\n", cleanedForURL))
-
-	sb.WriteString("\nInternational Directories:\n")
-	sb.WriteString("  - Australia:       https://www.whitepages.com.au/\n")
-	sb.WriteString("  - UK:              https://www.192.com/\n")
-	sb.WriteString("  - Canada:          https://www.canada411.ca/\n")
-
-	// Footer note
-	sb.WriteString("\n" + strings.Repeat("=", 70) + "\n")
-	sb.WriteString("Note: Use 'Advanced Mode' for automated platform checks and\n")
-	sb.WriteString("      additional verification resources.\n")
-
-	return sb.String()
-}
-
 // isValidPhoneNumber performs basic phone number validation
 // Checks length and format requirements
 func isValidPhoneNumber(phone string) bool {
@@ -0,0 +1,170 @@
+package phonelookup
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/providertest"
+)
+
+// testPhone is the number looked up by the tests
+const testPhone = "+14155552671"
+
+// standIn starts a stand-in for the validation providers and returns endpoints pointing at it
+func standIn(t *testing.T, routes map[string]providertest.Response) (*providertest.Stand, *Endpoints) {
+	t.Helper()
+
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, routes)
+	return stand, &Endpoints{Veriphone: stand.URL, Numverify: stand.URL, AbstractAPI: stand.URL}
+}
+
+func TestLookupPhoneFree(t *testing.T) {
+	t.Parallel()
+	stand, ep := standIn(t, map[string]providertest.Response{
+		"/v2/verify": {Body: providertest.Payload(t, "veriphone.json")},
+	})
+
+	info := &PhoneInfo{Number: testPhone, CountryCode: "Unknown"}
+	if err := ep.lookupPhoneFree(context.Background(), testPhone, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.IsValid || info.Carrier != "AT&T Wireless" || info.LineType != "mobile" {
+		t.Errorf("valid = %v, carrier = %q, line type = %q, want true, AT&T Wireless, mobile",
+			info.IsValid, info.Carrier, info.LineType)
+	}
+	// The ISO country_code must not end up as the dialling code
+	if info.CountryCode != "+1" || info.Region != "San Francisco, CA" {
+		t.Errorf("country code = %q, region = %q, want +1, San Francisco, CA", info.CountryCode, info.Region)
+	}
+	if phone := stand.Requests()[0].URL.Query().Get("phone"); phone != "14155552671" {
+		t.Errorf("phone = %q, want the number without +", phone)
+	}
+}
+
+func TestLookupPhoneFreeFallsBack(t *testing.T) {
+	t.Parallel()
+	stand, ep := standIn(t, map[string]providertest.Response{
+		"/v2/verify": providertest.TooManyRequests,
+		"/v1/":       {Body: providertest.Payload(t, "abstractapi.json")},
+	})
+
+	info := &PhoneInfo{Number: testPhone}
+	if err := ep.lookupPhoneFree(context.Background(), testPhone, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Carrier != "T-Mobile USA, Inc." || info.Country != "United States" {
+		t.Errorf("carrier = %q, country = %q, want the abstractapi answer", info.Carrier, info.Country)
+	}
+	if info.CountryCode != "+1" || info.Region != "California" {
+		t.Errorf("country code = %q, region = %q, want +1, California", info.CountryCode, info.Region)
+	}
+
+	requests := stand.Requests()
+	if path := requests[len(requests)-1].URL.Path; path != "/v1/" {
+		t.Errorf("last request went to %s, want the abstractapi fallback", path)
+	}
+}
+
+func TestLookupPhoneFreeErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		routes  map[string]providertest.Response
+		wantErr string
+	}{
+		{
+			name: "both providers fail",
+			routes: map[string]providertest.Response{
+				"/v2/verify": {Status: http.StatusServiceUnavailable},
+				"/v1/":       {Status: http.StatusUnauthorized, Body: `{"error": {"message": "Invalid API key"}}`},
+			},
+			wantErr: "alternative API returned status: 401",
+		},
+		{
+			name: "malformed JSON",
+			routes: map[string]providertest.Response{
+				"/v2/verify": {Body: `{"status": "success", "phone_valid":`},
+			},
+			wantErr: "unexpected EOF",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, ep := standIn(t, tc.routes)
+
+			info := &PhoneInfo{Number: testPhone}
+			err := ep.lookupPhoneFree(context.Background(), testPhone, info)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+			}
+			if info.Carrier != "" {
+				t.Errorf("carrier = %q, want none after an error", info.Carrier)
+			}
+		})
+	}
+}
+
+func TestLookupNumverify(t *testing.T) {
+	t.Parallel()
+	stand, ep := standIn(t, map[string]providertest.Response{
+		"/api/validate": {Body: providertest.Payload(t, "numverify.json")},
+	})
+
+	info := &PhoneInfo{Number: testPhone}
+	cfg := &config.Config{NumverifyKey: "secret"}
+	if err := ep.lookupNumverify(context.Background(), testPhone, info, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.Carrier != "AT&T Mobility LLC" || info.LineType != "mobile" || info.Region != "Novato" {
+		t.Errorf("carrier = %q, line type = %q, region = %q, want the numverify answer",
+			info.Carrier, info.LineType, info.Region)
+	}
+	// numverify sends the prefix with its plus already
+	if info.CountryCode != "+1" {
+		t.Errorf("country code = %q, want +1", info.CountryCode)
+	}
+
+	query := stand.Requests()[0].URL.Query()
+	if query.Get("access_key") != "secret" || query.Get("number") != "14155552671" {
+		t.Errorf("query = %v, want the key and the number without +", query)
+	}
+}
+
+func TestLookupNumverifyErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		apiKey  string
+		resp    providertest.Response
+		wantErr string
+	}{
+		{name: "no key", resp: providertest.Response{Body: providertest.Payload(t, "numverify.json")}, wantErr: "API key not configured"},
+		{name: "invalid key", apiKey: "bad", resp: providertest.Response{Body: providertest.Payload(t, "numverify-error.json")}, wantErr: "requires valid key"},
+		{name: "rate limited", apiKey: "key", resp: providertest.TooManyRequests, wantErr: "numverify API error: 429"},
+		{name: "malformed JSON", apiKey: "key", resp: providertest.Response{Body: `{"valid": true, "carrier":`}, wantErr: "unexpected EOF"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			stand, ep := standIn(t, map[string]providertest.Response{"/api/validate": tc.resp})
+
+			info := &PhoneInfo{Number: testPhone}
+			err := ep.lookupNumverify(context.Background(), testPhone, info, &config.Config{NumverifyKey: tc.apiKey})
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+			}
+			if tc.apiKey == "" && len(stand.Requests()) != 0 {
+				t.Error("a request was sent without a key")
+			}
+		})
+	}
+}
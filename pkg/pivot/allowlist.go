@@ -0,0 +1,83 @@
+package pivot
+
+import (
+	"net"
+	"path"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Allowlist limits which discovered entities are looked up
+// Patterns are domains (matching subdomains and email addresses too), CIDR ranges,
+// or exact values with * wildcards such as "john*" or "*@example.com"
+type Allowlist struct {
+	patterns []string
+	networks []*net.IPNet
+}
+
+// NewAllowlist builds an allowlist, an empty one allows everything
+func NewAllowlist(patterns []string) *Allowlist {
+	a := &Allowlist{}
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(p); err == nil {
+			a.networks = append(a.networks, network)
+			continue
+		}
+		a.patterns = append(a.patterns, p)
+	}
+	return a
+}
+
+// Empty reports whether the allowlist has no patterns
+func (a *Allowlist) Empty() bool {
+	return a == nil || (len(a.patterns) == 0 && len(a.networks) == 0)
+}
+
+// Allows reports whether an entity may be looked up
+func (a *Allowlist) Allows(kind, value string) bool {
+	if a.Empty() {
+		return true
+	}
+
+	value = strings.ToLower(value)
+	if kind == result.ModuleIP {
+		if ip := net.ParseIP(value); ip != nil {
+			for _, network := range a.networks {
+				if network.Contains(ip) {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, p := range a.patterns {
+		if matchPattern(p, kind, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches one allowlist pattern against a normalised value
+func matchPattern(pattern, kind, value string) bool {
+	if ok, _ := path.Match(pattern, value); ok {
+		return true
+	}
+
+	// A bare domain covers its subdomains and the addresses it hosts
+	switch kind {
+	case result.ModuleDomain:
+		return strings.HasSuffix(value, "."+pattern)
+	case result.ModuleEmail:
+		_, host, _ := strings.Cut(value, "@")
+		return host == pattern || strings.HasSuffix(host, "."+pattern)
+	case result.ModuleUsername:
+		return strings.TrimPrefix(pattern, "@") == value
+	}
+	return false
+}
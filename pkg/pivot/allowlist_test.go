@@ -0,0 +1,48 @@
+package pivot
+
+import (
+	"testing"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+func TestAllowlistAllows(t *testing.T) {
+	a := NewAllowlist([]string{" ACME.com ", "192.0.2.0/24", "john*", "*@partner.org", "@jane_acme", ""})
+
+	tests := []struct {
+		kind  string
+		value string
+		want  bool
+	}{
+		{result.ModuleDomain, "acme.com", true},
+		{result.ModuleDomain, "www.acme.com", true},
+		{result.ModuleDomain, "notacme.com", false},
+		{result.ModuleDomain, "acme.com.evil.net", false},
+		{result.ModuleEmail, "jane@acme.com", true},
+		{result.ModuleEmail, "jane@mail.acme.com", true},
+		{result.ModuleEmail, "ceo@partner.org", true},
+		{result.ModuleEmail, "jane@acme.co", false},
+		{result.ModuleIP, "192.0.2.77", true},
+		{result.ModuleIP, "198.51.100.1", false},
+		{result.ModuleUsername, "jane_acme", true},
+		{result.ModuleUsername, "johnsmith", true},
+		{result.ModuleUsername, "jane", false},
+		{result.ModuleName, "John Smith", true},
+		{result.ModuleName, "Jane Smith", false},
+	}
+
+	for _, tc := range tests {
+		if got := a.Allows(tc.kind, tc.value); got != tc.want {
+			t.Errorf("Allows(%s, %q) = %v, want %v", tc.kind, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestAllowlistEmpty(t *testing.T) {
+	var nilList *Allowlist
+	for _, a := range []*Allowlist{nilList, NewAllowlist(nil), NewAllowlist([]string{" ", ""})} {
+		if !a.Empty() || !a.Allows(result.ModuleDomain, "anything.example") {
+			t.Errorf("allowlist %+v must be empty and allow everything", a)
+		}
+	}
+}
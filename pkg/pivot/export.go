@@ -0,0 +1,323 @@
+package pivot
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Graph export formats
+const (
+	ExportGraphML = "graphml" // GraphML, for Gephi, yEd and Cytoscape
+	ExportGEXF    = "gexf"    // GEXF, Gephi's native format
+	ExportMaltego = "maltego" // Maltego entity/link CSV for the "Import Graph from Table" wizard
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []string{ExportGraphML, ExportGEXF, ExportMaltego}
+
+// ExportFormatFor returns the export format implied by a file extension
+func ExportFormatFor(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".graphml":
+		return ExportGraphML, nil
+	case ".gexf":
+		return ExportGEXF, nil
+	case ".csv":
+		return ExportMaltego, nil
+	}
+	return "", fmt.Errorf("cannot tell the graph format of %s, use a .graphml, .gexf or .csv extension", filename)
+}
+
+// Export writes the graph in one of the export formats
+func Export(w io.Writer, format string, g *Graph) error {
+	switch format {
+	case ExportGraphML:
+		return WriteGraphML(w, g)
+	case ExportGEXF:
+		return WriteGEXF(w, g)
+	case ExportMaltego:
+		return WriteMaltegoCSV(w, g)
+	}
+	return fmt.Errorf("unknown graph format: %s (use %s)", format, strings.Join(ExportFormats, ", "))
+}
+
+// nodeAttributes are the typed attributes every exported node carries
+var nodeAttributes = []struct{ name, kind string }{
+	{"kind", "string"},
+	{"value", "string"},
+	{"depth", "int"},
+	{"status", "string"},
+	{"error", "string"},
+}
+
+// edgeAttributes are the attributes every exported edge carries
+var edgeAttributes = []struct{ name, kind string }{
+	{"relation", "string"},
+	{"source", "string"},
+}
+
+// nodeValues returns an entity's attributes in nodeAttributes order
+func nodeValues(e *Entity) []string {
+	return []string{e.Kind, e.Value, strconv.Itoa(e.Depth), e.Status, e.Error}
+}
+
+// edgeValues returns an edge's attributes in edgeAttributes order
+func edgeValues(edge Edge) []string {
+	return []string{edge.Relation, edge.Source}
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph as GraphML, node and edge attributes are declared as typed keys
+func WriteGraphML(w io.Writer, g *Graph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Graph: graphMLGraph{EdgeDefault: "directed"},
+	}
+	for _, a := range nodeAttributes {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "n_" + a.name, For: "node", Name: a.name, Type: a.kind})
+	}
+	for _, a := range edgeAttributes {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "e_" + a.name, For: "edge", Name: a.name, Type: a.kind})
+	}
+
+	for _, e := range g.Entities {
+		node := graphMLNode{ID: e.ID}
+		for i, v := range nodeValues(e) {
+			if v != "" {
+				node.Data = append(node.Data, graphMLData{Key: "n_" + nodeAttributes[i].name, Value: v})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for i, edge := range g.Edges {
+		out := graphMLEdge{ID: "e" + strconv.Itoa(i), Source: edge.From, Target: edge.To}
+		for j, v := range edgeValues(edge) {
+			if v != "" {
+				out.Data = append(out.Data, graphMLData{Key: "e_" + edgeAttributes[j].name, Value: v})
+			}
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, out)
+	}
+
+	return writeXML(w, doc)
+}
+
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	XMLNS   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Meta    gexfMeta  `xml:"meta"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfMeta struct {
+	Creator     string `xml:"creator"`
+	Description string `xml:"description"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID     string      `xml:"id,attr"`
+	Label  string      `xml:"label,attr"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID     string      `xml:"id,attr"`
+	Source string      `xml:"source,attr"`
+	Target string      `xml:"target,attr"`
+	Label  string      `xml:"label,attr,omitempty"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexfValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// WriteGEXF writes the graph as GEXF 1.3, edges are labelled with their relation
+func WriteGEXF(w io.Writer, g *Graph) error {
+	doc := gexf{
+		XMLNS:   "http://gexf.net/1.3",
+		Version: "1.3",
+		Meta: gexfMeta{
+			Creator:     "osintmaster",
+			Description: fmt.Sprintf("Entity graph, max depth %d", g.MaxDepth),
+		},
+		Graph: gexfGraph{DefaultEdgeType: "directed"},
+	}
+
+	nodes := gexfAttributes{Class: "node"}
+	for i, a := range nodeAttributes {
+		nodes.Attributes = append(nodes.Attributes, gexfAttribute{ID: strconv.Itoa(i), Title: a.name, Type: gexfType(a.kind)})
+	}
+	edges := gexfAttributes{Class: "edge"}
+	for i, a := range edgeAttributes {
+		edges.Attributes = append(edges.Attributes, gexfAttribute{ID: strconv.Itoa(i), Title: a.name, Type: gexfType(a.kind)})
+	}
+	doc.Graph.Attributes = []gexfAttributes{nodes, edges}
+
+	for _, e := range g.Entities {
+		node := gexfNode{ID: e.ID, Label: e.Value}
+		for i, v := range nodeValues(e) {
+			if v != "" {
+				node.Values = append(node.Values, gexfValue{For: strconv.Itoa(i), Value: v})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, node)
+	}
+	for i, edge := range g.Edges {
+		out := gexfEdge{ID: strconv.Itoa(i), Source: edge.From, Target: edge.To, Label: edge.Relation}
+		for j, v := range edgeValues(edge) {
+			if v != "" {
+				out.Values = append(out.Values, gexfValue{For: strconv.Itoa(j), Value: v})
+			}
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, out)
+	}
+
+	return writeXML(w, doc)
+}
+
+// gexfType maps an attribute type to its GEXF name
+func gexfType(kind string) string {
+	if kind == "int" {
+		return "integer"
+	}
+	return kind
+}
+
+// writeXML writes an indented XML document with its declaration
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode graph: %v", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// maltegoTypes maps entity kinds to Maltego's standard entity types
+var maltegoTypes = map[string]string{
+	result.ModuleIP:       "maltego.IPv4Address",
+	result.ModuleDomain:   "maltego.Domain",
+	result.ModuleEmail:    "maltego.EmailAddress",
+	result.ModulePhone:    "maltego.PhoneNumber",
+	result.ModuleUsername: "maltego.Alias",
+	result.ModuleName:     "maltego.Person",
+	KindURL:               "maltego.URL",
+	KindASN:               "maltego.AS",
+	KindBreach:            "maltego.Phrase",
+}
+
+// maltegoHeader names the columns of the Maltego CSV
+var maltegoHeader = []string{"Entity Type", "Entity Value", "Linked Entity Type", "Linked Entity Value", "Link Label", "Link Source"}
+
+// WriteMaltegoCSV writes one row per link for Maltego's table import
+// Entities without any link get a row of their own so nothing is lost
+func WriteMaltegoCSV(w io.Writer, g *Graph) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(maltegoHeader); err != nil {
+		return err
+	}
+
+	linked := make(map[string]bool)
+	for _, edge := range g.Edges {
+		from, to := g.byID[edge.From], g.byID[edge.To]
+		if from == nil || to == nil {
+			continue
+		}
+		linked[from.ID], linked[to.ID] = true, true
+		row := []string{maltegoType(from), from.Value, maltegoType(to), to.Value, edge.Relation, edge.Source}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range g.Entities {
+		if linked[e.ID] {
+			continue
+		}
+		if err := cw.Write([]string{maltegoType(e), e.Value, "", "", "", ""}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// maltegoType returns the Maltego entity type of an entity
+func maltegoType(e *Entity) string {
+	if e.Kind == result.ModuleIP && strings.Contains(e.Value, ":") {
+		return "maltego.IPv6Address"
+	}
+	if t, ok := maltegoTypes[e.Kind]; ok {
+		return t
+	}
+	return "maltego.Phrase"
+}
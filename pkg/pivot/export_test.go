@@ -0,0 +1,181 @@
+package pivot
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Values that need escaping in XML and CSV
+const (
+	trickyName  = `Jane "JD" O'Brien & <Sons>, Ltd`
+	trickyURL   = "https://example.com/search?q=jane&page=2"
+	trickyError = "lookup failed:\n\"rate limited\", retry later"
+)
+
+// exportGraph builds a small graph with values that need escaping and one unlinked entity
+func exportGraph() *Graph {
+	g := newGraph(2)
+	email, _ := g.addEntity(result.ModuleEmail, "jane@acme.com", 0)
+	email.Status = StatusLookedUp
+	name, _ := g.addEntity(result.ModuleName, trickyName, 1)
+	name.Status = StatusDepthLimit
+	url, _ := g.addEntity(KindURL, trickyURL, 1)
+	url.Status = StatusNotFollowed
+	ip, _ := g.addEntity(result.ModuleIP, "2001:db8::1", 0)
+	ip.Status, ip.Error = StatusFailed, trickyError
+
+	g.addEdge(Edge{From: email.ID, To: name.ID, Relation: "owner_name", Source: "hibp & co"})
+	g.addEdge(Edge{From: email.ID, To: url.ID, Relation: "social_account", Source: "gravatar"})
+	return g
+}
+
+func TestExportFormatFor(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"case.graphml", ExportGraphML},
+		{"CASE.GEXF", ExportGEXF},
+		{"out/links.csv", ExportMaltego},
+		{"graph.json", ""},
+		{"graph", ""},
+	}
+
+	for _, tc := range tests {
+		got, err := ExportFormatFor(tc.filename)
+		if got != tc.want || (err != nil) != (tc.want == "") {
+			t.Errorf("ExportFormatFor(%q) = %q, %v, want %q", tc.filename, got, err, tc.want)
+		}
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	g := exportGraph()
+	var buf bytes.Buffer
+	if err := Export(&buf, ExportGraphML, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc graphML
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != len(g.Entities) || len(doc.Graph.Edges) != len(g.Edges) {
+		t.Fatalf("%d nodes and %d edges, want %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges), len(g.Entities), len(g.Edges))
+	}
+	if len(doc.Keys) != len(nodeAttributes)+len(edgeAttributes) {
+		t.Errorf("%d keys, want %d", len(doc.Keys), len(nodeAttributes)+len(edgeAttributes))
+	}
+
+	nodes := make(map[string]map[string]string)
+	for _, n := range doc.Graph.Nodes {
+		nodes[n.ID] = make(map[string]string)
+		for _, d := range n.Data {
+			nodes[n.ID][d.Key] = d.Value
+		}
+	}
+	if got := nodes[entityID(result.ModuleName, trickyName)]["n_value"]; got != trickyName {
+		t.Errorf("name = %q, want %q", got, trickyName)
+	}
+	if got := nodes[entityID(KindURL, trickyURL)]["n_value"]; got != trickyURL {
+		t.Errorf("url = %q, want %q", got, trickyURL)
+	}
+	if got := nodes["ip:2001:db8::1"]["n_error"]; got != trickyError {
+		t.Errorf("error = %q, want %q", got, trickyError)
+	}
+	if got := nodes["email:jane@acme.com"]["n_depth"]; got != "0" {
+		t.Errorf("depth = %q, want 0", got)
+	}
+
+	edge := doc.Graph.Edges[0]
+	data := map[string]string{}
+	for _, d := range edge.Data {
+		data[d.Key] = d.Value
+	}
+	if edge.Source != "email:jane@acme.com" || edge.Target != entityID(result.ModuleName, trickyName) || data["e_source"] != "hibp & co" {
+		t.Errorf("edge = %+v, want the owner_name link from the email", edge)
+	}
+}
+
+func TestWriteGEXF(t *testing.T) {
+	g := exportGraph()
+	var buf bytes.Buffer
+	if err := Export(&buf, ExportGEXF, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc gexf
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	if len(doc.Graph.Nodes) != len(g.Entities) || len(doc.Graph.Edges) != len(g.Edges) {
+		t.Fatalf("%d nodes and %d edges, want %d and %d", len(doc.Graph.Nodes), len(doc.Graph.Edges), len(g.Entities), len(g.Edges))
+	}
+	if doc.Version != "1.3" || len(doc.Graph.Attributes) != 2 {
+		t.Errorf("version %q with %d attribute classes, want 1.3 with node and edge", doc.Version, len(doc.Graph.Attributes))
+	}
+
+	labels := make(map[string]string)
+	errs := make(map[string]string)
+	for _, n := range doc.Graph.Nodes {
+		labels[n.ID] = n.Label
+		for _, v := range n.Values {
+			if v.For == "4" { // error
+				errs[n.ID] = v.Value
+			}
+		}
+	}
+	if got := labels[entityID(result.ModuleName, trickyName)]; got != trickyName {
+		t.Errorf("name label = %q, want %q", got, trickyName)
+	}
+	if got := labels[entityID(KindURL, trickyURL)]; got != trickyURL {
+		t.Errorf("url label = %q, want %q", got, trickyURL)
+	}
+	if got := errs["ip:2001:db8::1"]; got != trickyError {
+		t.Errorf("error = %q, want %q", got, trickyError)
+	}
+
+	var relations []string
+	for _, e := range doc.Graph.Edges {
+		relations = append(relations, e.Label)
+	}
+	if want := []string{"owner_name", "social_account"}; !reflect.DeepEqual(relations, want) {
+		t.Errorf("edge labels = %v, want %v", relations, want)
+	}
+}
+
+func TestWriteMaltegoCSV(t *testing.T) {
+	g := exportGraph()
+	var buf bytes.Buffer
+	if err := Export(&buf, ExportMaltego, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, buf.String())
+	}
+
+	// A header, one row per link and one for the entity without links
+	want := [][]string{
+		maltegoHeader,
+		{"maltego.EmailAddress", "jane@acme.com", "maltego.Person", trickyName, "owner_name", "hibp & co"},
+		{"maltego.EmailAddress", "jane@acme.com", "maltego.URL", trickyURL, "social_account", "gravatar"},
+		{"maltego.IPv6Address", "2001:db8::1", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q\nwant   %q", rows, want)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	if err := Export(&bytes.Buffer{}, "dot", exportGraph()); err == nil || !strings.Contains(err.Error(), "unknown graph format") {
+		t.Errorf("error = %v, want an unknown format error", err)
+	}
+}
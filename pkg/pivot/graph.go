@@ -0,0 +1,129 @@
+package pivot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Entity kinds that have no lookup module
+const (
+	KindURL    = "url"
+	KindASN    = "asn"
+	KindBreach = "breach"
+)
+
+// Entity statuses
+const (
+	StatusLookedUp    = "looked_up"    // the entity was looked up
+	StatusFailed      = "failed"       // the lookup returned an error
+	StatusNotFollowed = "not_followed" // the relation is recorded but never pivoted on
+	StatusNotAllowed  = "not_allowed"  // the entity is outside the allowlist
+	StatusSharedHost  = "shared_host"  // a webmail or hosting domain shared by unrelated people
+	StatusDepthLimit  = "depth_limit"  // found at the maximum depth
+	StatusLookupLimit = "lookup_limit" // the maximum number of lookups was reached
+	StatusInterrupted = "interrupted"  // the run was cancelled before the lookup
+)
+
+// Entity is a node of the graph: a selector found or looked up during the investigation
+type Entity struct {
+	ID     string `json:"id"`   // kind:normalised value, unique in the graph
+	Kind   string `json:"kind"` // a lookup module name, url, asn or breach
+	Value  string `json:"value"`
+	Depth  int    `json:"depth"` // hops from the nearest root
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Edge links an entity to an entity found in its lookup
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Relation string `json:"relation"` // the finding field, e.g. "subdomain"
+	Source   string `json:"source"`   // the provider that reported it
+}
+
+// Graph is the outcome of a pivot run
+type Graph struct {
+	MaxDepth int              `json:"max_depth"`
+	Entities []*Entity        `json:"entities"`
+	Edges    []Edge           `json:"edges"`
+	Results  []*result.Result `json:"-"` // one per looked up entity, in lookup order
+
+	byID  map[string]*Entity
+	edges map[Edge]bool
+}
+
+// newGraph creates an empty graph
+func newGraph(maxDepth int) *Graph {
+	return &Graph{
+		MaxDepth: maxDepth,
+		Entities: make([]*Entity, 0),
+		Edges:    make([]Edge, 0),
+		byID:     make(map[string]*Entity),
+		edges:    make(map[Edge]bool),
+	}
+}
+
+// Entity returns the entity with the given ID
+func (g *Graph) Entity(id string) (*Entity, bool) {
+	e, ok := g.byID[id]
+	return e, ok
+}
+
+// addEntity adds an entity unless one with the same ID exists, reporting whether it was new
+func (g *Graph) addEntity(kind, value string, depth int) (*Entity, bool) {
+	id := entityID(kind, value)
+	if e, ok := g.byID[id]; ok {
+		return e, false
+	}
+
+	e := &Entity{ID: id, Kind: kind, Value: value, Depth: depth}
+	g.byID[id] = e
+	g.Entities = append(g.Entities, e)
+	return e, true
+}
+
+// addEdge records a link once
+func (g *Graph) addEdge(edge Edge) {
+	if edge.From == edge.To || g.edges[edge] {
+		return
+	}
+	g.edges[edge] = true
+	g.Edges = append(g.Edges, edge)
+}
+
+// Text renders the graph as one line per entity, showing how it was found
+func Text(g *Graph) string {
+	var sb strings.Builder
+
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+	sb.WriteString(fmt.Sprintf("ENTITY GRAPH (max depth %d, %d entities, %d links):\n", g.MaxDepth, len(g.Entities), len(g.Edges)))
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+
+	// The first edge into an entity is how it was discovered
+	via := make(map[string]Edge)
+	for _, edge := range g.Edges {
+		if _, ok := via[edge.To]; !ok {
+			via[edge.To] = edge
+		}
+	}
+
+	for _, e := range g.Entities {
+		line := fmt.Sprintf("  [%d] %-9s %s  (%s)", e.Depth, e.Kind, e.Value, strings.ReplaceAll(e.Status, "_", " "))
+		if edge, ok := via[e.ID]; ok {
+			from := edge.From
+			if parent, ok := g.byID[edge.From]; ok {
+				from = parent.Value
+			}
+			line += fmt.Sprintf(" <- %s of %s", edge.Relation, from)
+		}
+		if e.Error != "" {
+			line += ": " + e.Error
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,283 @@
+// Package pivot chains lookups: selectors found by one module become the targets of the next
+// The outcome is an entity graph of everything found and how it is connected
+package pivot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/malika/osint-master/pkg/lookup"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// DefaultMaxLookups bounds a pivot run when Options.MaxLookups is not set
+const DefaultMaxLookups = 25
+
+// DefaultWorkers is the number of lookups run at once when Options.Workers is not set
+const DefaultWorkers = 4
+
+// Options controls how far a pivot run goes
+type Options struct {
+	Depth      int        // hops followed from the roots, 0 only looks up the roots
+	MaxLookups int        // total lookups including the roots
+	Workers    int        // lookups run at once within a depth
+	Allow      *Allowlist // discovered entities outside it are recorded but not looked up
+	Lookup     lookup.Options
+}
+
+// Seed is a root target of a pivot run
+type Seed struct {
+	Module string
+	Target string
+}
+
+// rule turns a finding of a module into an entity
+type rule struct {
+	kind   string
+	follow bool   // look the entity up, otherwise only record it
+	via    string // kind of the entity named by the finding's Raw value, linked instead of the looked up one
+}
+
+// rules maps module and finding field to the entity they produce
+var rules = map[string]map[string]rule{
+	result.ModuleEmail: {
+		"username":       {kind: result.ModuleUsername, follow: true},
+		"domain":         {kind: result.ModuleDomain, follow: true},
+		"social_account": {kind: KindURL},
+		"breach":         {kind: KindBreach},
+	},
+	result.ModuleDomain: {
+		"subdomain": {kind: result.ModuleDomain}, // enumerating every subdomain again would repeat the parent
+		"ip":        {kind: result.ModuleIP, follow: true, via: result.ModuleDomain},
+	},
+	result.ModulePhone: {
+		"owner_name":  {kind: result.ModuleName, follow: true},
+		"owner_email": {kind: result.ModuleEmail, follow: true},
+	},
+	result.ModuleUsername: {
+		"profile": {kind: KindURL},
+	},
+	result.ModuleName: {
+		"username_variation": {kind: result.ModuleUsername, follow: true},
+	},
+	result.ModuleIP: {
+		"asn": {kind: KindASN},
+	},
+}
+
+// sharedDomains host unrelated people, enumerating them says nothing about the target
+var sharedDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "yahoo.com": true, "outlook.com": true,
+	"hotmail.com": true, "live.com": true, "msn.com": true, "icloud.com": true, "me.com": true,
+	"aol.com": true, "proton.me": true, "protonmail.com": true, "gmx.com": true, "gmx.net": true,
+	"mail.com": true, "yandex.com": true, "yandex.ru": true, "mail.ru": true, "zoho.com": true,
+}
+
+// Runner looks up one target, it is satisfied by *lookup.Runner
+type Runner interface {
+	Run(ctx context.Context, module, target string, opts lookup.Options) (*result.Result, error)
+}
+
+// Engine runs pivots with one lookup runner
+type Engine struct {
+	runner Runner
+	opts   Options
+}
+
+// NewEngine creates an engine, filling in defaults for unset options
+func NewEngine(runner Runner, opts Options) *Engine {
+	if opts.MaxLookups <= 0 {
+		opts.MaxLookups = DefaultMaxLookups
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.Depth < 0 {
+		opts.Depth = 0
+	}
+	return &Engine{runner: runner, opts: opts}
+}
+
+// Run looks up the seeds and follows what they reveal, one depth at a time
+// Lookups that fail are recorded on their entity, the run stops early only when ctx ends
+func (e *Engine) Run(ctx context.Context, seeds []Seed) (*Graph, error) {
+	if len(seeds) == 0 {
+		return nil, fmt.Errorf("no targets to pivot from")
+	}
+
+	g := newGraph(e.opts.Depth)
+	var frontier []*Entity
+	for _, seed := range seeds {
+		if entity, added := g.addEntity(seed.Module, seed.Target, 0); added {
+			frontier = append(frontier, entity)
+		}
+	}
+
+	lookups := 0
+	for depth := 0; len(frontier) > 0; depth++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		fmt.Fprintf(os.Stderr, "Pivot depth %d: looking up %d entities\n", depth, len(frontier))
+		results := e.lookupAll(ctx, frontier)
+		lookups += len(frontier)
+
+		var next []*Entity
+		for i, entity := range frontier {
+			res := results[i]
+			if res.Failed() {
+				entity.Status = StatusFailed
+				entity.Error = res.Errors[0].Message
+				continue
+			}
+			entity.Status = StatusLookedUp
+			g.Results = append(g.Results, res)
+
+			for _, found := range e.expand(g, entity, res) {
+				switch {
+				case depth+1 > e.opts.Depth:
+					found.Status = StatusDepthLimit
+				case lookups+len(next) >= e.opts.MaxLookups:
+					found.Status = StatusLookupLimit
+				default:
+					next = append(next, found)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	// Entities queued when the run was cancelled were never looked up
+	for _, entity := range frontier {
+		entity.Status = StatusInterrupted
+	}
+
+	return g, nil
+}
+
+// lookupAll looks up entities with a bounded number of workers
+// Failures come back as results from result.Failure
+func (e *Engine) lookupAll(ctx context.Context, entities []*Entity) []*result.Result {
+	results := make([]*result.Result, len(entities))
+	sem := make(chan struct{}, e.opts.Workers)
+
+	var wg sync.WaitGroup
+	for i, entity := range entities {
+		wg.Add(1)
+		go func(i int, entity *Entity) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			res, err := e.runner.Run(ctx, entity.Kind, entity.Value, e.opts.Lookup)
+			if err != nil {
+				res = result.Failure(entity.Kind, entity.Value, err)
+			}
+			results[i] = res
+		}(i, entity)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// expand adds the entities found in a result to the graph
+// It returns the new entities that should be looked up next
+func (e *Engine) expand(g *Graph, from *Entity, res *result.Result) []*Entity {
+	var follow []*Entity
+
+	for _, f := range res.Findings {
+		r, ok := rules[res.Module][f.Field]
+		if !ok {
+			continue
+		}
+		value := normalize(r.kind, f.Value)
+		if value == "" {
+			continue
+		}
+
+		parent := linkFrom(g, from, r, f)
+		entity, added := g.addEntity(r.kind, value, parent.Depth+1)
+		g.addEdge(Edge{From: parent.ID, To: entity.ID, Relation: f.Field, Source: f.Source})
+		if !added {
+			continue
+		}
+
+		switch {
+		case !r.follow:
+			entity.Status = StatusNotFollowed
+		case r.kind == result.ModuleDomain && sharedDomains[value]:
+			entity.Status = StatusSharedHost
+		case !e.opts.Allow.Allows(r.kind, value):
+			entity.Status = StatusNotAllowed
+		default:
+			follow = append(follow, entity)
+		}
+	}
+
+	return follow
+}
+
+// linkFrom returns the entity a finding hangs off, e.g. the subdomain an IP was resolved from
+func linkFrom(g *Graph, from *Entity, r rule, f result.Finding) *Entity {
+	if name, ok := f.Raw.(string); ok && r.via != "" {
+		if parent, ok := g.Entity(entityID(r.via, name)); ok {
+			return parent
+		}
+	}
+	return from
+}
+
+// FromResults builds the graph of results looked up without pivoting
+// Everything the results reveal is recorded, and what a pivot would follow is marked as past the depth limit
+func FromResults(results []*result.Result) *Graph {
+	e := NewEngine(nil, Options{})
+	g := newGraph(0)
+
+	for _, res := range results {
+		root, _ := g.addEntity(res.Module, res.Target, 0)
+		if res.Failed() {
+			root.Status = StatusFailed
+			root.Error = res.Errors[0].Message
+			continue
+		}
+
+		root.Status = StatusLookedUp
+		g.Results = append(g.Results, res)
+		for _, found := range e.expand(g, root, res) {
+			found.Status = StatusDepthLimit
+		}
+	}
+
+	return g
+}
+
+// phoneSeparators are removed from phone numbers before comparing them
+var phoneSeparators = regexp.MustCompile(`[\s().\-]`)
+
+// normalize returns the canonical form of a value so duplicates share one entity
+func normalize(kind, value string) string {
+	value = strings.TrimSpace(value)
+
+	switch kind {
+	case result.ModuleDomain, result.ModuleEmail:
+		return strings.TrimSuffix(strings.ToLower(value), ".")
+	case result.ModuleUsername:
+		return strings.ToLower(strings.TrimPrefix(value, "@"))
+	case result.ModulePhone:
+		return phoneSeparators.ReplaceAllString(value, "")
+	case result.ModuleName:
+		return strings.Join(strings.Fields(value), " ")
+	}
+	return value
+}
+
+// entityID is the graph key of a value
+func entityID(kind, value string) string {
+	return kind + ":" + strings.ToLower(normalize(kind, value))
+}
@@ -0,0 +1,316 @@
+package pivot
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/malika/osint-master/pkg/lookup"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// fakeRunner answers lookups from canned results and counts them
+type fakeRunner struct {
+	results map[string]*result.Result
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+// newFakeRunner creates a runner answering "module:target" keys
+func newFakeRunner(results ...*result.Result) *fakeRunner {
+	r := &fakeRunner{results: make(map[string]*result.Result), calls: make(map[string]int)}
+	for _, res := range results {
+		r.results[res.Module+":"+res.Target] = res
+	}
+	return r
+}
+
+// Run implements Runner, targets without a canned result find nothing
+func (r *fakeRunner) Run(ctx context.Context, module, target string, opts lookup.Options) (*result.Result, error) {
+	key := module + ":" + target
+	r.mu.Lock()
+	r.calls[key]++
+	r.mu.Unlock()
+
+	if res, ok := r.results[key]; ok {
+		return res, nil
+	}
+	if module == result.ModuleIP && target == "192.0.2.99" {
+		return nil, fmt.Errorf("ip-api.com returned status: 503")
+	}
+	return result.New(module, target), nil
+}
+
+// finding is a field, value and raw value of a canned result
+type finding struct {
+	field string
+	value string
+	raw   interface{}
+}
+
+// found builds a canned result
+func found(module, target string, findings ...finding) *result.Result {
+	res := result.New(module, target)
+	for _, f := range findings {
+		res.Add(f.field, f.value, "test", result.ConfidenceHigh, f.raw)
+	}
+	return res
+}
+
+// investigation is an email that leads to a domain, its subdomain and the subdomain's IP
+func investigation() *fakeRunner {
+	return newFakeRunner(
+		found(result.ModuleEmail, "jane@acme.com",
+			finding{"domain", "ACME.com.", nil},
+			finding{"username", "@Jane_Acme", nil},
+			finding{"breach", "Collection1", nil},
+		),
+		found(result.ModuleDomain, "acme.com",
+			finding{"subdomain", "www.acme.com", nil},
+			finding{"ip", "192.0.2.10", "www.acme.com"},
+		),
+		found(result.ModuleUsername, "jane_acme",
+			finding{"profile", "https://github.com/jane_acme", nil},
+		),
+		found(result.ModuleIP, "192.0.2.10",
+			finding{"asn", "AS64500", nil},
+		),
+	)
+}
+
+// statuses returns the status of every entity by ID
+func statuses(g *Graph) map[string]string {
+	out := make(map[string]string)
+	for _, e := range g.Entities {
+		out[e.ID] = e.Status
+	}
+	return out
+}
+
+func TestRunDepthLimit(t *testing.T) {
+	tests := []struct {
+		depth int
+		want  map[string]string
+	}{
+		{
+			depth: 0,
+			want: map[string]string{
+				"email:jane@acme.com": StatusLookedUp,
+				"domain:acme.com":     StatusDepthLimit,
+				"username:jane_acme":  StatusDepthLimit,
+				"breach:collection1":  StatusNotFollowed,
+			},
+		},
+		{
+			depth: 1,
+			want: map[string]string{
+				"email:jane@acme.com":              StatusLookedUp,
+				"domain:acme.com":                  StatusLookedUp,
+				"username:jane_acme":               StatusLookedUp,
+				"breach:collection1":               StatusNotFollowed,
+				"domain:www.acme.com":              StatusNotFollowed,
+				"ip:192.0.2.10":                    StatusDepthLimit,
+				"url:https://github.com/jane_acme": StatusNotFollowed,
+			},
+		},
+		{
+			depth: 2,
+			want: map[string]string{
+				"email:jane@acme.com":              StatusLookedUp,
+				"domain:acme.com":                  StatusLookedUp,
+				"username:jane_acme":               StatusLookedUp,
+				"breach:collection1":               StatusNotFollowed,
+				"domain:www.acme.com":              StatusNotFollowed,
+				"ip:192.0.2.10":                    StatusLookedUp,
+				"url:https://github.com/jane_acme": StatusNotFollowed,
+				"asn:as64500":                      StatusNotFollowed,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(fmt.Sprintf("depth %d", tc.depth), func(t *testing.T) {
+			t.Parallel()
+
+			runner := investigation()
+			g, err := NewEngine(runner, Options{Depth: tc.depth}).Run(context.Background(), []Seed{{result.ModuleEmail, "jane@acme.com"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := statuses(g); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("statuses = %v\nwant       %v", got, tc.want)
+			}
+
+			lookedUp := 0
+			for _, e := range g.Entities {
+				if e.Status == StatusLookedUp {
+					lookedUp++
+				}
+			}
+			if len(g.Results) != lookedUp {
+				t.Errorf("kept %d results for %d looked up entities", len(g.Results), lookedUp)
+			}
+		})
+	}
+}
+
+func TestRunIPHangsOffSubdomain(t *testing.T) {
+	g, err := NewEngine(investigation(), Options{Depth: 1}).Run(context.Background(), []Seed{{result.ModuleDomain, "acme.com"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ip, ok := g.Entity("ip:192.0.2.10")
+	if !ok {
+		t.Fatal("the resolved IP is not in the graph")
+	}
+	// The IP was resolved from the subdomain, one hop further from the root
+	var into []Edge
+	for _, edge := range g.Edges {
+		if edge.To == ip.ID {
+			into = append(into, edge)
+		}
+	}
+	want := []Edge{{From: "domain:www.acme.com", To: ip.ID, Relation: "ip", Source: "test"}}
+	if !reflect.DeepEqual(into, want) || ip.Depth != 2 {
+		t.Errorf("links into the IP = %+v at depth %d, want %+v at depth 2", into, ip.Depth, want)
+	}
+}
+
+func TestRunAllowlist(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow []string
+		want  map[string]string // statuses of the entities found in the email
+	}{
+		{
+			name: "no allowlist",
+			want: map[string]string{"domain:acme.com": StatusLookedUp, "username:jane_acme": StatusLookedUp},
+		},
+		{
+			name:  "domain only",
+			allow: []string{"acme.com"},
+			want:  map[string]string{"domain:acme.com": StatusLookedUp, "username:jane_acme": StatusNotAllowed},
+		},
+		{
+			name:  "username only",
+			allow: []string{"@jane_acme"},
+			want:  map[string]string{"domain:acme.com": StatusNotAllowed, "username:jane_acme": StatusLookedUp},
+		},
+		{
+			name:  "other domain",
+			allow: []string{"example.com", "198.51.100.0/24"},
+			want:  map[string]string{"domain:acme.com": StatusNotAllowed, "username:jane_acme": StatusNotAllowed},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := investigation()
+			opts := Options{Depth: 1, Allow: NewAllowlist(tc.allow)}
+			g, err := NewEngine(runner, opts).Run(context.Background(), []Seed{{result.ModuleEmail, "jane@acme.com"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			all := statuses(g)
+			for id, want := range tc.want {
+				if all[id] != want {
+					t.Errorf("%s: status = %q, want %q", id, all[id], want)
+				}
+			}
+			// A rejected entity is recorded but never looked up
+			for id, want := range tc.want {
+				e, _ := g.Entity(id)
+				if calls := runner.calls[e.Kind+":"+e.Value]; (calls == 1) != (want == StatusLookedUp) {
+					t.Errorf("%s looked up %d times with status %s", id, calls, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunVisitsEntitiesOnce(t *testing.T) {
+	runner := newFakeRunner(
+		found(result.ModuleEmail, "jane@acme.com", finding{"domain", "acme.com", nil}),
+		found(result.ModuleEmail, "john@acme.com", finding{"domain", "ACME.COM", nil}),
+		found(result.ModuleDomain, "acme.com", finding{"ip", "192.0.2.10", nil}),
+		found(result.ModuleIP, "192.0.2.10", finding{"asn", "AS64500", nil}),
+	)
+	// The same seed given twice is looked up once
+	seeds := []Seed{
+		{result.ModuleEmail, "jane@acme.com"},
+		{result.ModuleEmail, "john@acme.com"},
+		{result.ModuleEmail, "Jane@ACME.com"},
+	}
+
+	g, err := NewEngine(runner, Options{Depth: 3}).Run(context.Background(), seeds)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for key, calls := range runner.calls {
+		if calls != 1 {
+			t.Errorf("%s looked up %d times, want once", key, calls)
+		}
+	}
+	if len(runner.calls) != 4 {
+		t.Errorf("made %d distinct lookups, want 4: %v", len(runner.calls), runner.calls)
+	}
+
+	// Both emails link to the one domain entity
+	var intoDomain int
+	for _, edge := range g.Edges {
+		if edge.To == "domain:acme.com" {
+			intoDomain++
+		}
+	}
+	if len(g.Entities) != 5 || intoDomain != 2 {
+		t.Errorf("%d entities and %d links into the domain, want 5 and 2", len(g.Entities), intoDomain)
+	}
+}
+
+func TestRunLimitsAndFailures(t *testing.T) {
+	runner := newFakeRunner(
+		found(result.ModuleDomain, "acme.com",
+			finding{"ip", "192.0.2.10", nil},
+			finding{"ip", "192.0.2.99", nil},
+			finding{"ip", "192.0.2.11", nil},
+		),
+		found(result.ModuleEmail, "jane@gmail.com", finding{"domain", "gmail.com", nil}),
+	)
+	seeds := []Seed{{result.ModuleDomain, "acme.com"}, {result.ModuleEmail, "jane@gmail.com"}}
+
+	g, err := NewEngine(runner, Options{Depth: 1, MaxLookups: 4}).Run(context.Background(), seeds)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"domain:acme.com":      StatusLookedUp,
+		"email:jane@gmail.com": StatusLookedUp,
+		"ip:192.0.2.10":        StatusLookedUp,
+		"ip:192.0.2.99":        StatusFailed,
+		"ip:192.0.2.11":        StatusLookupLimit,
+		"domain:gmail.com":     StatusSharedHost,
+	}
+	if got := statuses(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %v\nwant       %v", got, want)
+	}
+	if e, _ := g.Entity("ip:192.0.2.99"); e.Error != "ip-api.com returned status: 503" {
+		t.Errorf("error = %q, want the lookup failure", e.Error)
+	}
+}
+
+func TestRunNoSeeds(t *testing.T) {
+	if _, err := NewEngine(newFakeRunner(), Options{}).Run(context.Background(), nil); err == nil {
+		t.Error("expected an error without seeds")
+	}
+}
@@ -0,0 +1,44 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/domain"
+)
+
+// formatDomainInfo formats the domain information into a readable string
+func formatDomainInfo(info *domain.DomainInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Main Domain: %s\n\n", info.MainDomain))
+	sb.WriteString(fmt.Sprintf("Subdomains found: %d\n", len(info.Subdomains)))
+
+	for _, sub := range info.Subdomains {
+		sb.WriteString(fmt.Sprintf("  - %s (IP: %s)\n", sub.Name, sub.IP))
+		sb.WriteString(fmt.Sprintf("    SSL Certificate: %s\n", sub.SSLCert))
+		if sub.IsTakeover {
+			sb.WriteString(fmt.Sprintf("    ⚠️  TAKEOVER RISK: %s\n", sub.TakeoverMsg))
+		}
+	}
+
+	// List takeover risks separately
+	hasRisks := false
+	for _, sub := range info.Subdomains {
+		if sub.IsTakeover {
+			if !hasRisks {
+				sb.WriteString("\n⚠️  Potential Subdomain Takeover Risks:\n")
+				hasRisks = true
+			}
+			sb.WriteString(fmt.Sprintf("  - Subdomain: %s\n", sub.Name))
+			sb.WriteString(fmt.Sprintf("    %s\n", sub.TakeoverMsg))
+			sb.WriteString("    Recommended Action: Verify ownership or remove DNS record\n\n")
+		}
+	}
+
+	if !hasRisks {
+		sb.WriteString("\nNo obvious subdomain takeover risks detected.\n")
+	}
+
+	return sb.String()
+}
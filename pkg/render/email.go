@@ -0,0 +1,97 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/emaillookup"
+)
+
+// formatSocialAccounts formats social media account results
+func formatSocialAccounts(accounts []emaillookup.SocialAccount) string {
+	var sb strings.Builder
+
+	sb.WriteString("Social Media Account Detection:\n")
+	sb.WriteString("================================\n\n")
+
+	foundCount := 0
+	for _, account := range accounts {
+		if account.Skipped {
+			sb.WriteString(fmt.Sprintf("- %s: Not checked (%s)\n\n", account.Platform, account.Method))
+			continue
+		}
+		if account.Found {
+			foundCount++
+			sb.WriteString(fmt.Sprintf("✓ %s: FOUND\n", account.Platform))
+			sb.WriteString(fmt.Sprintf("  URL: %s\n", account.URL))
+		} else {
+			sb.WriteString(fmt.Sprintf("✗ %s: Not found (or requires login to verify)\n", account.Platform))
+			if account.URL != "" {
+				sb.WriteString(fmt.Sprintf("  Search: %s\n", account.URL))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Summary: %d accounts found automatically\n", foundCount))
+	sb.WriteString("\nNote: Some platforms require login to search by email.\n")
+	sb.WriteString("      Links provided for manual verification where needed.\n")
+
+	return sb.String()
+}
+
+// formatEmailInfo formats email information into readable string
+func formatEmailInfo(info *emaillookup.EmailInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Email Address: %s\n", info.Email))
+	sb.WriteString(fmt.Sprintf("Domain: %s\n", info.Domain))
+	sb.WriteString(fmt.Sprintf("Valid Format: %v\n", info.IsValid))
+	sb.WriteString(fmt.Sprintf("Disposable Email: %v\n", info.IsDisposable))
+
+	if info.IsDisposable {
+		sb.WriteString("⚠️  Warning: This is a temporary/disposable email service\n")
+	}
+
+	// Email Reputation (FREE API - EmailRep.io)
+	sb.WriteString("\nEmail Reputation (via EmailRep.io - FREE):\n")
+	if info.Reputation != "" {
+		sb.WriteString(fmt.Sprintf("  Reputation: %s\n", info.Reputation))
+		sb.WriteString(fmt.Sprintf("  Suspicious: %v\n", info.Suspicious))
+		if info.References > 0 {
+			sb.WriteString(fmt.Sprintf("  References: %d (times seen in data)\n", info.References))
+		}
+		if info.Suspicious {
+			sb.WriteString("  ⚠️  Warning: Email marked as suspicious by reputation database\n")
+		}
+	} else {
+		sb.WriteString("  Status: No reputation data available\n")
+		sb.WriteString("  Note: This is a free service that may not have all emails\n")
+	}
+
+	sb.WriteString("\nGravatar:\n")
+	if info.GravatarExists {
+		sb.WriteString(fmt.Sprintf("  Found: Yes\n"))
+		sb.WriteString(fmt.Sprintf("  URL: %s\n", info.GravatarURL))
+	} else {
+		sb.WriteString("  Found: No\n")
+	}
+
+	sb.WriteString("\nData Breach Check (Have I Been Pwned):\n")
+	if info.BreachCount == 0 {
+		sb.WriteString("  Status: No breaches found (or API key required)\n")
+		sb.WriteString(fmt.Sprintf("  🔗 Check directly: https://haveibeenpwned.com/account/%s\n", info.Email))
+		sb.WriteString("  Note: Visit the link above to see detailed breach information\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("  Breaches Found: %d\n", info.BreachCount))
+		sb.WriteString("  Breach Names:\n")
+		for _, breach := range info.Breaches {
+			sb.WriteString(fmt.Sprintf("    - %s\n", breach))
+		}
+		sb.WriteString("\n⚠️  WARNING: This email has been found in data breaches!\n")
+		sb.WriteString("  Recommendation: Change passwords immediately\n")
+		sb.WriteString(fmt.Sprintf("  🔗 View details: https://haveibeenpwned.com/account/%s\n", info.Email))
+	}
+
+	return sb.String()
+}
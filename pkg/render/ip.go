@@ -0,0 +1,126 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/iplookup"
+)
+
+// formatIPInfo formats IP information into readable string
+func formatIPInfo(info *iplookup.IPInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("IP Address: %s\n", info.IP))
+	sb.WriteString(strings.Repeat("=", 50) + "\n\n")
+
+	sb.WriteString("Location Information:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+	if info.City != "" {
+		sb.WriteString(fmt.Sprintf("City:         %s\n", info.City))
+	}
+	if info.Region != "" {
+		sb.WriteString(fmt.Sprintf("Region:       %s\n", info.Region))
+	}
+	if info.Country != "" {
+		sb.WriteString(fmt.Sprintf("Country:      %s", info.Country))
+		if info.CountryCode != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", info.CountryCode))
+		}
+		sb.WriteString("\n")
+	} else if info.CountryCode != "" {
+		sb.WriteString(fmt.Sprintf("Country:      %s\n", info.CountryCode))
+	}
+	if info.Timezone != "" {
+		sb.WriteString(fmt.Sprintf("Timezone:     %s\n", info.Timezone))
+	}
+	if info.Latitude != 0 || info.Longitude != 0 {
+		sb.WriteString(fmt.Sprintf("Coordinates:  %.6f, %.6f\n", info.Latitude, info.Longitude))
+		sb.WriteString(fmt.Sprintf("Map:          https://www.google.com/maps?q=%.6f,%.6f\n", info.Latitude, info.Longitude))
+	}
+
+	sb.WriteString("\nNetwork Information:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+
+	if info.ISP != "" {
+		sb.WriteString(fmt.Sprintf("ISP:          %s\n", info.ISP))
+	}
+	if info.ASN != "" {
+		sb.WriteString(fmt.Sprintf("ASN:          %s\n", info.ASN))
+	}
+
+	sb.WriteString("\nAdditional Lookup Resources:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	sb.WriteString(fmt.Sprintf("IP-API:       http://ip-api.com/#%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("IPInfo:       https://ipinfo.io/%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("WhoIs:        https://who.is/whois-ip/ip-address/%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("ViewDNS:      https://viewdns.info/iphistory/?domain=%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("DNSChecker:   https://dnschecker.org/ip-location.php?ip=%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("IPVoid:       https://www.ipvoid.com/ip-blacklist-check/\n"))
+
+	sb.WriteString("\nThreat Intelligence:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	sb.WriteString(fmt.Sprintf("AbuseIPDB:    https://www.abuseipdb.com/check/%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("VirusTotal:   https://www.virustotal.com/gui/ip-address/%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("Shodan:       https://www.shodan.io/host/%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("Censys:       https://search.censys.io/hosts/%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("Talos Intel:  https://www.talosintelligence.com/reputation_center/lookup?search=%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("GreyNoise:    https://viz.greynoise.io/ip/%s\n", info.IP))
+
+	sb.WriteString("\nWHOIS & Network Details:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	sb.WriteString(fmt.Sprintf("ARIN WHOIS:   https://search.arin.net/rdap/?query=%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("RIPE:         https://apps.db.ripe.net/db-web-ui/query?searchtext=%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("APNIC:        https://wq.apnic.net/query?searchtext=%s\n", info.IP))
+
+	sb.WriteString("\nReverse DNS & Domain History:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	sb.WriteString(fmt.Sprintf("MXToolbox:    https://mxtoolbox.com/SuperTool.aspx?action=ptr%%3A%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("SecurityTrails: https://securitytrails.com/list/ip/%s\n", info.IP))
+	sb.WriteString(fmt.Sprintf("Robtex:       https://www.robtex.com/ip-lookup/%s\n", info.IP))
+
+	sb.WriteString("\n" + strings.Repeat("=", 50) + "\n")
+	sb.WriteString("Note: Use 'Advanced Mode' for comprehensive security analysis\n")
+	sb.WriteString("      and additional threat intelligence resources.\n")
+
+	return sb.String()
+}
+
+// formatIPConsensus shows which providers reported each field and where they disagree
+func formatIPConsensus(info *iplookup.ConsensusInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString("\nProvider Consensus:\n")
+	sb.WriteString(strings.Repeat("-", 50) + "\n")
+	sb.WriteString(fmt.Sprintf("Providers:    %s\n\n", strings.Join(info.Providers, ", ")))
+
+	for _, fc := range info.Fields {
+		marker := ""
+		if fc.Conflict {
+			marker = "  ⚠️  CONFLICT"
+		} else if fc.SingleSource {
+			marker = "  (single source)"
+		}
+		sb.WriteString(fmt.Sprintf("%-14s%s  [%.0f%%]%s\n", fieldLabel(fc.Field)+":", fc.Value, fc.Confidence*100, marker))
+		sb.WriteString(fmt.Sprintf("              reported by: %s\n", strings.Join(fc.Sources, ", ")))
+		for _, alt := range fc.Alternatives {
+			sb.WriteString(fmt.Sprintf("              %s: %s\n", strings.Join(alt.Sources, ", "), alt.Value))
+		}
+	}
+
+	return sb.String()
+}
+
+// fieldLabel turns a field name such as country_code into "Country Code"
+func fieldLabel(field string) string {
+	words := strings.Split(field, "_")
+	for i, w := range words {
+		if w == "isp" || w == "asn" {
+			words[i] = strings.ToUpper(w)
+		} else if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, " ")
+}
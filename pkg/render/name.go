@@ -0,0 +1,27 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/namelookup"
+)
+
+// formatNameInfo formats the name information into a readable string
+func formatNameInfo(info *namelookup.NameInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("First Name: %s\n", info.FirstName))
+	sb.WriteString(fmt.Sprintf("Last Name: %s\n", info.LastName))
+	sb.WriteString(fmt.Sprintf("Phone Number: %s\n", info.PhoneNumber))
+	sb.WriteString(fmt.Sprintf("Address: %s\n", info.Address))
+	sb.WriteString("\nSocial Media Search URLs:\n")
+	sb.WriteString(fmt.Sprintf("LinkedIn: %s\n", info.LinkedInURL))
+	sb.WriteString(fmt.Sprintf("Facebook: %s\n", info.FacebookURL))
+	sb.WriteString(fmt.Sprintf("Twitter: %s\n", info.TwitterURL))
+	sb.WriteString(fmt.Sprintf("Instagram: %s\n", info.InstagramURL))
+	sb.WriteString("\nNote: Phone and address require paid API access (e.g., Pipl, Whitepages)\n")
+	sb.WriteString("Visit the URLs above to manually search on each platform.\n")
+
+	return sb.String()
+}
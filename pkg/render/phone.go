@@ -0,0 +1,151 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/phonelookup"
+)
+
+// formatPhoneInfo formats phone information into readable string
+// Creates a comprehensive report with all collected data
+func formatPhoneInfo(info *phonelookup.PhoneInfo) string {
+	var sb strings.Builder
+
+	// Header section
+	sb.WriteString(fmt.Sprintf("Phone Number: %s\n", info.Number))
+	sb.WriteString(strings.Repeat("=", 70) + "\n\n")
+
+	// Validation status
+	sb.WriteString("Validation:\n")
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+	if info.IsValid {
+		sb.WriteString("Status:       ✓ Valid phone number\n")
+	} else {
+		sb.WriteString("Status:       ✗ Invalid or unverified\n")
+	}
+
+	// Location information
+	sb.WriteString("\nLocation Information:\n")
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+	if info.CountryCode != "" {
+		sb.WriteString(fmt.Sprintf("Country Code: +%s\n", info.CountryCode))
+	}
+	if info.Country != "" {
+		sb.WriteString(fmt.Sprintf("Country:      %s\n", info.Country))
+	}
+	if info.Region != "" {
+		sb.WriteString(fmt.Sprintf("Region:       %s\n", info.Region))
+	}
+
+	// Carrier information
+	sb.WriteString("\nCarrier Information:\n")
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+	if info.Carrier != "" {
+		sb.WriteString(fmt.Sprintf("Carrier:      %s\n", info.Carrier))
+	}
+	if info.LineType != "" {
+		sb.WriteString(fmt.Sprintf("Line Type:    %s\n", info.LineType))
+	}
+
+	// Owner information
+	if info.OwnerName != "" || info.OwnerEmail != "" || info.OwnerAddress != "" {
+		sb.WriteString("\nOwner Information:\n")
+		sb.WriteString(strings.Repeat("-", 70) + "\n")
+		if info.OwnerName != "" {
+			sb.WriteString(fmt.Sprintf("Name:         %s\n", info.OwnerName))
+		}
+		if info.OwnerEmail != "" {
+			sb.WriteString(fmt.Sprintf("Email:        %s\n", info.OwnerEmail))
+		}
+		if info.OwnerAddress != "" {
+			sb.WriteString(fmt.Sprintf("Address:      %s\n", info.OwnerAddress))
+		}
+		if info.OwnerSource != "" {
+			sb.WriteString(fmt.Sprintf("Source:       %s\n", info.OwnerSource))
+		}
+	}
+
+	// Messaging platforms
+	sb.WriteString("\nMessaging Platforms:\n")
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+
+	// WhatsApp
+	if info.OnWhatsApp {
+		sb.WriteString(fmt.Sprintf("WhatsApp:     ✓ Registered (%s)\n", info.WhatsAppStatus))
+	} else {
+		sb.WriteString(fmt.Sprintf("WhatsApp:     ✗ %s\n", info.WhatsAppStatus))
+	}
+
+	// Telegram
+	if info.OnTelegram {
+		sb.WriteString(fmt.Sprintf("Telegram:     ✓ Registered (%s)\n", info.TelegramStatus))
+	} else {
+		sb.WriteString(fmt.Sprintf("Telegram:     ✗ %s\n", info.TelegramStatus))
+	}
+
+	// Signal
+	if info.OnSignal {
+		sb.WriteString(fmt.Sprintf("Signal:       ✓ Registered (%s)\n", info.SignalStatus))
+	} else {
+		sb.WriteString(fmt.Sprintf("Signal:       ✗ %s\n", info.SignalStatus))
+	}
+
+	// Viber
+	if info.OnViber {
+		sb.WriteString(fmt.Sprintf("Viber:        ✓ Registered (%s)\n", info.ViberStatus))
+	} else {
+		sb.WriteString(fmt.Sprintf("Viber:        ✗ %s\n", info.ViberStatus))
+	}
+
+	// WeChat
+	if info.OnWeChat {
+		sb.WriteString(fmt.Sprintf("WeChat:       ✓ Registered (%s)\n", info.WeChatStatus))
+	} else {
+		sb.WriteString(fmt.Sprintf("WeChat:       ✗ %s\n", info.WeChatStatus))
+	}
+
+	// LINE
+	if info.OnLine {
+		sb.WriteString(fmt.Sprintf("LINE:         ✓ Registered (%s)\n", info.LineStatus))
+	} else {
+		sb.WriteString(fmt.Sprintf("LINE:         ✗ %s\n", info.LineStatus))
+	}
+
+	// Additional lookup resources
+	sb.WriteString("\nAdditional Lookup Resources:\n")
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+
+	cleanedForURL := strings.ReplaceAll(info.Number, "+", "")
+	sb.WriteString("\nCaller ID & Reverse Lookup:\n")
+	sb.WriteString(fmt.Sprintf("  - TrueCaller:    https://www.truecaller.com/search/us/%s\n", cleanedForURL))
+	sb.WriteString(fmt.Sprintf("  - WhitePages:    https://www.whitepages.com/phone/%s\n", cleanedForURL))
+	sb.WriteString("  - Spy Dialer:    https://www.spydialer.com/\n")
+	sb.WriteString("  - NumLookup:     https://www.numlookup.com/\n")
+
+	sb.WriteString("\nCarrier & CNAM Lookup:\n")
+	sb.WriteString("  - FreeCarrierLookup: https://freecarrierlookup.com/\n")
+	sb.WriteString("  - Carrier Lookup:    https://www.carrierlookup.com/\n")
+
+	sb.WriteString("\nSocial Media Search:\n")
+	sb.WriteString(fmt.Sprintf("  - Facebook:      https://www.facebook.com/search/people/?q=%s\n", cleanedForURL))
+	sb.WriteString(fmt.Sprintf("  - Twitter:       https://twitter.com/search?q=%s\n", cleanedForURL))
+	sb.WriteString(fmt.Sprintf("  - LinkedIn:      https://www.linkedin.com/search/results/people/?keywords=%s\n", cleanedForURL))
+
+	sb.WriteString("\nSpam & Scam Databases:\n")
+	sb.WriteString(fmt.Sprintf("  - Should I Answer: https://www.shouldianswer.com/phone-number/%s\n", cleanedForURL))
+	sb.WriteString(fmt.Sprintf("  - 800notes:        https://800notes.com/Phone.aspx/%s\n", cleanedForURL))
+	sb.WriteString(fmt.Sprintf("  - CallerSmart:     https://www.callersmart.com/number/%s\n", cleanedForURL))
+
+	sb.WriteString("\nInternational Directories:\n")
+	sb.WriteString("  - Australia:       https://www.whitepages.com.au/\n")
+	sb.WriteString("  - UK:              https://www.192.com/\n")
+	sb.WriteString("  - Canada:          https://www.canada411.ca/\n")
+
+	// Footer note
+	sb.WriteString("\n" + strings.Repeat("=", 70) + "\n")
+	sb.WriteString("Note: Use 'Advanced Mode' for automated platform checks and\n")
+	sb.WriteString("      additional verification resources.\n")
+
+	return sb.String()
+}
@@ -0,0 +1,157 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/malika/osint-master/pkg/domain"
+	"github.com/malika/osint-master/pkg/emaillookup"
+	"github.com/malika/osint-master/pkg/iplookup"
+	"github.com/malika/osint-master/pkg/namelookup"
+	"github.com/malika/osint-master/pkg/phonelookup"
+	"github.com/malika/osint-master/pkg/result"
+	"github.com/malika/osint-master/pkg/username"
+)
+
+// Text renders a lookup result as the human-readable report printed by the CLI
+func Text(r *result.Result) string {
+	var sb strings.Builder
+
+	switch r.Module {
+	case result.ModuleIP:
+		var info iplookup.ConsensusInfo
+		if decode(r, &info) {
+			sb.WriteString(formatIPInfo(&info.IPInfo))
+			if len(info.Fields) > 0 {
+				sb.WriteString(formatIPConsensus(&info))
+			}
+		}
+	case result.ModuleDomain:
+		var info domain.DomainInfo
+		if decode(r, &info) {
+			sb.WriteString(formatDomainInfo(&info))
+		}
+	case result.ModuleEmail:
+		var info emaillookup.EmailInfo
+		if decode(r, &info) {
+			sb.WriteString(formatEmailInfo(&info))
+			sb.WriteString("\n" + formatSocialAccounts(info.SocialAccounts))
+		}
+	case result.ModulePhone:
+		var info phonelookup.PhoneInfo
+		if decode(r, &info) {
+			sb.WriteString(formatPhoneInfo(&info))
+		}
+	case result.ModuleName:
+		var info namelookup.NameInfo
+		if decode(r, &info) {
+			sb.WriteString(formatNameInfo(&info))
+		}
+	case result.ModuleUsername:
+		var info username.UsernameInfo
+		if decode(r, &info) {
+			sb.WriteString(formatUsernameResults(&info))
+		}
+	}
+
+	// Fall back to the raw findings when there is no module renderer
+	if sb.Len() == 0 {
+		sb.WriteString(formatFindings(r))
+	}
+
+	if r.Advanced {
+		sb.WriteString(formatAdvanced(r))
+	}
+
+	if len(r.Cached) > 0 {
+		sb.WriteString(formatCached(r.Cached))
+	}
+
+	if len(r.Skipped) > 0 {
+		sb.WriteString(formatSkipped(r.Skipped))
+	}
+
+	if r.Partial {
+		sb.WriteString("\n⚠️  Partial results: the lookup was interrupted before every check finished\n")
+	}
+
+	return sb.String()
+}
+
+// decode copies the result data into the module struct
+func decode(r *result.Result, v interface{}) bool {
+	return r.DecodeData(v) == nil
+}
+
+// formatFindings lists every finding with its source and confidence
+func formatFindings(r *result.Result) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Target: %s (%s)\n", r.Target, r.Module))
+	sb.WriteString(strings.Repeat("=", 50) + "\n\n")
+
+	for _, f := range r.Findings {
+		sb.WriteString(fmt.Sprintf("%-15s %s  [%s, %.0f%%]\n", f.Field+":", f.Value, f.Source, f.Confidence*100))
+	}
+
+	return sb.String()
+}
+
+// formatAdvanced formats the extra links and notes collected in advanced mode
+func formatAdvanced(r *result.Result) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n" + strings.Repeat("-", 70) + "\n")
+	sb.WriteString("ADVANCED CHECKS:\n")
+	sb.WriteString(strings.Repeat("-", 70) + "\n")
+
+	category := ""
+	for _, link := range r.Links {
+		if link.Category != category {
+			category = link.Category
+			sb.WriteString(fmt.Sprintf("\n%s:\n", category))
+		}
+		sb.WriteString(fmt.Sprintf("  - %s: %s\n", link.Name, link.URL))
+	}
+
+	if len(r.Notes) > 0 {
+		sb.WriteString("\n⚠️  IMPORTANT NOTES:\n")
+		for _, note := range r.Notes {
+			sb.WriteString(fmt.Sprintf("  - %s\n", note))
+		}
+	}
+
+	if r.Elapsed > 0 {
+		sb.WriteString("\n" + strings.Repeat("=", 70) + "\n")
+		sb.WriteString(fmt.Sprintf("⏱️  Advanced search completed in %.2f seconds\n", r.Elapsed.Seconds()))
+	}
+
+	return sb.String()
+}
+
+// formatCached lists the provider responses that came from the cache and their age
+func formatCached(cached []result.CachedResponse) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n💾 Cached responses (use --refresh for fresh data):\n")
+	for _, c := range cached {
+		age := time.Since(c.StoredAt).Round(time.Minute)
+		sb.WriteString(fmt.Sprintf("  - %s: fetched %s ago (%s)\n", c.Provider, age, c.StoredAt.Local().Format("2006-01-02 15:04")))
+	}
+
+	return sb.String()
+}
+
+// formatSkipped lists the active checks passive mode left out
+func formatSkipped(skipped []result.SkippedCheck) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n🕶️  Passive mode, active checks skipped:\n")
+	for _, s := range skipped {
+		sb.WriteString(fmt.Sprintf("  - %s: %s\n", s.Check, s.Description))
+		sb.WriteString(fmt.Sprintf("    not run for %s\n", strings.Join(s.Targets, ", ")))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,57 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/username"
+)
+
+// formatUsernameResults formats the search results
+func formatUsernameResults(info *username.UsernameInfo) string {
+	var sb strings.Builder
+	results := info.Results
+
+	sb.WriteString(fmt.Sprintf("Username: @%s\n", info.Username))
+	if info.Mode == "advanced" {
+		sb.WriteString("Search Mode: Advanced (Browser Automation)\n\n")
+	} else {
+		sb.WriteString("Search Mode: Standard (HTTP checks)\n\n")
+	}
+	sb.WriteString("Social Network Presence:\n")
+
+	foundCount := 0
+	unknownCount := 0
+	for _, result := range results {
+		status := "Not Found"
+		switch {
+		case result.Found:
+			status = "Found ✓"
+			foundCount++
+		case result.Status == username.StatusUnknown:
+			status = "Unknown"
+			if result.Reason != "" {
+				status = fmt.Sprintf("Unknown (%s)", result.Reason)
+			}
+			unknownCount++
+		}
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", result.Network, status))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nTotal Found: %d out of %d networks\n", foundCount, len(results)))
+	if unknownCount > 0 {
+		sb.WriteString(fmt.Sprintf("Could not verify: %d networks (blocked, rate limited or login required)\n", unknownCount))
+	}
+
+	if foundCount > 0 {
+		sb.WriteString("\nFound Profiles:\n")
+		for _, result := range results {
+			if result.Found {
+				sb.WriteString(fmt.Sprintf("  - %s: %s\n", result.Network, result.URL))
+			}
+		}
+		sb.WriteString("\nRecent Activity: Check individual platforms for details\n")
+	}
+
+	return sb.String()
+}
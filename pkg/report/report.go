@@ -0,0 +1,153 @@
+// Package report combines the results of several lookups into one investigation report
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/malika/osint-master/pkg/render"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Summary is the overview printed at the top of a combined report
+type Summary struct {
+	Targets   int             `json:"targets"`
+	Succeeded int             `json:"succeeded"`
+	Failed    int             `json:"failed"`
+	Partial   int             `json:"partial"`
+	Findings  int             `json:"findings"`
+	Entries   []TargetSummary `json:"entries"`
+}
+
+// TargetSummary counts what was found for one target
+type TargetSummary struct {
+	Module   string         `json:"module"`
+	Target   string         `json:"target"`
+	Findings int            `json:"findings"`
+	Errors   int            `json:"errors"`
+	Fields   map[string]int `json:"fields,omitempty"` // findings per field
+	Partial  bool           `json:"partial,omitempty"`
+	Failed   bool           `json:"failed,omitempty"`
+	Error    string         `json:"error,omitempty"` // why the lookup failed
+}
+
+// Summarize counts the findings and failures of every result
+func Summarize(results []*result.Result) *Summary {
+	s := &Summary{Targets: len(results), Entries: make([]TargetSummary, 0, len(results))}
+
+	for _, r := range results {
+		entry := TargetSummary{
+			Module:   r.Module,
+			Target:   r.Target,
+			Findings: len(r.Findings),
+			Errors:   len(r.Errors),
+			Partial:  r.Partial,
+			Failed:   r.Failed(),
+		}
+
+		if entry.Failed {
+			s.Failed++
+			entry.Error = failure(r)
+		} else {
+			s.Succeeded++
+		}
+		if r.Partial {
+			s.Partial++
+		}
+
+		if len(r.Findings) > 0 {
+			entry.Fields = make(map[string]int)
+			for _, f := range r.Findings {
+				entry.Fields[f.Field]++
+			}
+		}
+
+		s.Findings += entry.Findings
+		s.Entries = append(s.Entries, entry)
+	}
+
+	return s
+}
+
+// Text renders a summary followed by one section per target
+func Text(results []*result.Result) string {
+	var sb strings.Builder
+
+	sb.WriteString(strings.Repeat("=", 70) + "\n")
+	sb.WriteString("OSINT INVESTIGATION REPORT\n")
+	sb.WriteString(strings.Repeat("=", 70) + "\n\n")
+	sb.WriteString(SummaryText(Summarize(results)))
+
+	for i, r := range results {
+		sb.WriteString("\n" + SectionTitle(i, len(results), r) + "\n")
+		sb.WriteString(strings.Repeat("=", 70) + "\n\n")
+		sb.WriteString(SectionText(r))
+	}
+
+	return sb.String()
+}
+
+// SummaryText renders the overview of a combined report
+func SummaryText(s *Summary) string {
+	var sb strings.Builder
+
+	sb.WriteString("SUMMARY:\n")
+	sb.WriteString(fmt.Sprintf("  Targets:   %d (%d succeeded, %d failed, %d partial)\n", s.Targets, s.Succeeded, s.Failed, s.Partial))
+	sb.WriteString(fmt.Sprintf("  Findings:  %d\n\n", s.Findings))
+
+	for _, e := range s.Entries {
+		status := fmt.Sprintf("%d findings", e.Findings)
+		switch {
+		case e.Failed:
+			status = "FAILED: " + e.Error
+		case e.Partial:
+			status += " (partial)"
+		}
+		sb.WriteString(fmt.Sprintf("  - %-9s %s: %s\n", e.Module, e.Target, status))
+
+		if fields := fieldCounts(e.Fields); fields != "" {
+			sb.WriteString(fmt.Sprintf("      %s\n", fields))
+		}
+	}
+
+	return sb.String()
+}
+
+// SectionTitle is the heading of a target section, e.g. "[1/3] EMAIL: john@example.com"
+func SectionTitle(i, total int, r *result.Result) string {
+	return fmt.Sprintf("[%d/%d] %s: %s", i+1, total, strings.ToUpper(r.Module), r.Target)
+}
+
+// SectionText renders one target, failed lookups show their error instead of a report
+func SectionText(r *result.Result) string {
+	if r.Failed() {
+		return fmt.Sprintf("⚠️  Lookup failed: %s\n", failure(r))
+	}
+	return render.Text(r)
+}
+
+// failure returns the error message of a failed lookup
+func failure(r *result.Result) string {
+	for _, e := range r.Errors {
+		if e.Provider == result.LookupProvider {
+			return e.Message
+		}
+	}
+	return ""
+}
+
+// fieldCounts formats findings per field as "breach: 3, subdomain: 10"
+func fieldCounts(fields map[string]int) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, fields[name]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,210 @@
+package result
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Module names used in Result.Module
+const (
+	ModuleIP       = "ip"
+	ModuleDomain   = "domain"
+	ModuleEmail    = "email"
+	ModulePhone    = "phone"
+	ModuleName     = "name"
+	ModuleUsername = "username"
+)
+
+// ContextProvider is the provider name used for cancellation and deadline errors
+const ContextProvider = "context"
+
+// LookupProvider is the provider name used when a whole lookup failed
+const LookupProvider = "lookup"
+
+// Confidence levels used by the lookup packages
+// High: reported directly by a provider, Medium: derived or partial data, Low: guessed
+const (
+	ConfidenceHigh   = 0.9
+	ConfidenceMedium = 0.6
+	ConfidenceLow    = 0.3
+)
+
+// Result is the common envelope returned by every lookup package
+// Data holds the module-specific struct (IPInfo, DomainInfo, EmailInfo, ...)
+// Partial is set when the lookup was cancelled or hit its deadline before finishing
+// Cached lists the provider responses that were served from the on-disk cache
+// Skipped lists the active checks left out in passive mode
+type Result struct {
+	Target    string           `json:"target"`
+	Module    string           `json:"module"`
+	Timestamp time.Time        `json:"timestamp"`
+	Findings  []Finding        `json:"findings"`
+	Errors    []ProviderError  `json:"errors,omitempty"`
+	Links     []Link           `json:"links,omitempty"`
+	Notes     []string         `json:"notes,omitempty"`
+	Advanced  bool             `json:"advanced,omitempty"`
+	Partial   bool             `json:"partial,omitempty"`
+	Cached    []CachedResponse `json:"cached,omitempty"`
+	Skipped   []SkippedCheck   `json:"skipped,omitempty"`
+	Elapsed   time.Duration    `json:"elapsed,omitempty"`
+	Data      interface{}      `json:"data,omitempty"`
+}
+
+// Finding is a single piece of information reported by a provider
+type Finding struct {
+	Field      string      `json:"field"`
+	Value      string      `json:"value"`
+	Source     string      `json:"source"`
+	Confidence float64     `json:"confidence"`
+	Raw        interface{} `json:"raw,omitempty"`
+}
+
+// ProviderError records a provider that failed during a lookup
+type ProviderError struct {
+	Provider string `json:"provider"`
+	Message  string `json:"message"`
+}
+
+// CachedResponse is a provider response reused from the cache instead of fetched
+type CachedResponse struct {
+	Provider  string    `json:"provider"`
+	URL       string    `json:"url"`
+	StoredAt  time.Time `json:"stored_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SkippedCheck is an active check that passive mode did not run
+type SkippedCheck struct {
+	Check       string   `json:"check"`
+	Description string   `json:"description"`
+	Targets     []string `json:"targets"`
+}
+
+// Link is a reference URL for manual verification
+type Link struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+}
+
+// New creates an empty result for the given module and target
+func New(module, target string) *Result {
+	return &Result{
+		Target:    target,
+		Module:    module,
+		Timestamp: time.Now().UTC(),
+		Findings:  make([]Finding, 0),
+	}
+}
+
+// Failure creates the result of a lookup that failed before producing anything
+func Failure(module, target string, err error) *Result {
+	r := New(module, target)
+	r.AddError(LookupProvider, err)
+	return r
+}
+
+// Failed reports whether the result comes from Failure
+func (r *Result) Failed() bool {
+	for _, e := range r.Errors {
+		if e.Provider == LookupProvider {
+			return true
+		}
+	}
+	return false
+}
+
+// Add records a finding, empty values are ignored
+func (r *Result) Add(field, value, source string, confidence float64, raw interface{}) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	r.Findings = append(r.Findings, Finding{
+		Field:      field,
+		Value:      value,
+		Source:     source,
+		Confidence: confidence,
+		Raw:        raw,
+	})
+}
+
+// AddError records a failed provider, nil errors are ignored
+func (r *Result) AddError(provider string, err error) {
+	if err == nil {
+		return
+	}
+
+	r.Errors = append(r.Errors, ProviderError{
+		Provider: provider,
+		Message:  err.Error(),
+	})
+}
+
+// Interrupted reports whether ctx is done, marking the result as partial when it is
+// The context error is recorded once, so it is safe to call before every step
+func (r *Result) Interrupted(ctx context.Context) bool {
+	err := ctx.Err()
+	if err == nil {
+		return false
+	}
+
+	if !r.Partial {
+		r.Partial = true
+		r.AddError(ContextProvider, err)
+	}
+	return true
+}
+
+// AddLink records a reference URL under a category
+func (r *Result) AddLink(category, name, url string) {
+	r.Links = append(r.Links, Link{
+		Category: category,
+		Name:     name,
+		URL:      url,
+	})
+}
+
+// AddNote records a free-form note shown after the findings
+func (r *Result) AddNote(format string, args ...interface{}) {
+	r.Notes = append(r.Notes, fmt.Sprintf(format, args...))
+}
+
+// Find returns every finding recorded for a field
+func (r *Result) Find(field string) []Finding {
+	var findings []Finding
+	for _, f := range r.Findings {
+		if f.Field == field {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// Value returns the first value recorded for a field
+func (r *Result) Value(field string) string {
+	for _, f := range r.Findings {
+		if f.Field == field {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// DecodeData copies Data into v
+// Works both for in-memory results and results decoded from JSON
+func (r *Result) DecodeData(v interface{}) error {
+	if r.Data == nil {
+		return fmt.Errorf("result has no data")
+	}
+
+	raw, err := json.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, v)
+}
@@ -1,12 +1,17 @@
 package username
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/malika/osint-master/internal/audit"
+	"github.com/malika/osint-master/internal/opsec"
+	"github.com/malika/osint-master/pkg/result"
 	"github.com/playwright-community/playwright-go"
 )
 
@@ -20,61 +25,85 @@ import (
 // DO NOT use for unauthorized access or ToS violations
 
 // AdvancedSearchUsername uses browser automation to bypass basic bot detection
-func AdvancedSearchUsername(username string) (string, error) {
+// Networks not checked before ctx ends are left out and the result is marked partial
+func AdvancedSearchUsername(ctx context.Context, username string) (*result.Result, error) {
 	// Remove @ symbol if present
 	username = strings.TrimPrefix(username, "@")
 
 	// Validate username format
 	if username == "" {
-		return "", fmt.Errorf("username cannot be empty")
+		return nil, fmt.Errorf("username cannot be empty")
 	}
 
 	if strings.Contains(username, " ") {
-		return "", fmt.Errorf("invalid username: usernames cannot contain spaces")
+		return nil, fmt.Errorf("invalid username: usernames cannot contain spaces")
 	}
 
 	if !isValidUsername(username) {
-		return "", fmt.Errorf("invalid username: only letters, numbers, underscores, and hyphens allowed")
+		return nil, fmt.Errorf("invalid username: only letters, numbers, underscores, hyphens and dots allowed")
+	}
+
+	// Browser automation is an active check of the username
+	if err := opsec.Allow(ctx, "browser_automation", result.ModuleUsername, username); err != nil {
+		if !opsec.Skipped(err) {
+			return nil, err
+		}
+		res := result.New(result.ModuleUsername, username)
+		res.Advanced = true
+		res.Data = &UsernameInfo{Username: username, Mode: "advanced", Results: []UsernameResult{}}
+		return res, nil
 	}
 
 	// fmt.Println("⚠️  Advanced Mode: Using browser automation")
 	// fmt.Println("⚠️  This mode is slower but more accurate")
 	// fmt.Println("⚠️  Use only for authorized testing")
 
-	// Define social networks to check
-	networks := []SocialNetwork{
-		{Name: "GitHub", URL: fmt.Sprintf("https://github.com/%s", username)},
-		{Name: "Reddit", URL: fmt.Sprintf("https://www.reddit.com/user/%s", username)},
-		{Name: "Twitter", URL: fmt.Sprintf("https://twitter.com/%s", username)},
-		{Name: "Medium", URL: fmt.Sprintf("https://medium.com/@%s", username)},
-	}
+	networks := browserNetworks(username)
+
+	res := result.New(result.ModuleUsername, username)
+	res.Advanced = true
 
 	// Use browser automation for checking
 	var wg sync.WaitGroup
-	results := make([]UsernameResult, len(networks))
+	results := make([]UsernameResult, 0, len(networks))
 
 	// Rate limiting: check one at a time to be polite
-	for i, network := range networks {
+	for _, network := range networks {
+		if res.Interrupted(ctx) {
+			break
+		}
+
+		results = append(results, UsernameResult{})
 		wg.Add(1)
 		go func(index int, net SocialNetwork) {
 			defer wg.Done()
 
-			fmt.Printf("Checking %s... ", net.Name)
+			fmt.Fprintf(os.Stderr, "Checking %s... ", net.Name)
 			found := checkWithBrowser(net.URL, net.Name)
+			audit.Connection(ctx, "BROWSER", net.URL, nil)
+			status := StatusNotFound
+			if found {
+				status = StatusFound
+			}
 			results[index] = UsernameResult{
 				Network: net.Name,
+				URL:     net.URL,
 				Found:   found,
+				Status:  status,
 			}
 
 			if found {
-				fmt.Println("✓ Found")
+				fmt.Fprintln(os.Stderr, "✓ Found")
 			} else {
-				fmt.Println("✗ Not Found")
+				fmt.Fprintln(os.Stderr, "✗ Not Found")
 			}
 
 			// Polite delay between checks
-			time.Sleep(2 * time.Second)
-		}(i, network)
+			select {
+			case <-ctx.Done():
+			case <-time.After(2 * time.Second):
+			}
+		}(len(results)-1, network)
 
 		wg.Wait() // Wait for each to complete (sequential, not parallel)
 	}
@@ -89,13 +118,42 @@ func AdvancedSearchUsername(username string) (string, error) {
 	for _, platform := range blockedPlatforms {
 		results = append(results, UsernameResult{
 			Network: platform.Name,
+			URL:     platform.URL,
 			Found:   false, // Don't check, add warning instead
+			Status:  StatusUnknown,
+			Reason:  "requires login to verify",
 		})
 	}
 
-	// Format results
-	output := formatAdvancedResults(username, results)
-	return output, nil
+	info := &UsernameInfo{
+		Username: username,
+		Mode:     "advanced",
+		Results:  results,
+	}
+
+	for _, r := range results {
+		if r.Found {
+			res.Add("profile", r.URL, r.Network, result.ConfidenceMedium, r)
+		}
+	}
+	for _, platform := range blockedPlatforms {
+		res.AddLink("Manual Verification URLs", platform.Name, platform.URL)
+	}
+	res.AddNote("LinkedIn, Instagram, Facebook still require login for verification")
+	res.AddNote("This mode respects rate limits (slower but polite)")
+	res.AddNote("Always verify manually for critical investigations")
+	res.Data = info
+	return res, nil
+}
+
+// browserNetworks returns the profiles checked with browser automation
+func browserNetworks(username string) []SocialNetwork {
+	return []SocialNetwork{
+		{Name: "GitHub", URL: fmt.Sprintf("https://github.com/%s", username)},
+		{Name: "Reddit", URL: fmt.Sprintf("https://www.reddit.com/user/%s", username)},
+		{Name: "Twitter", URL: fmt.Sprintf("https://twitter.com/%s", username)},
+		{Name: "Medium", URL: fmt.Sprintf("https://medium.com/@%s", username)},
+	}
 }
 
 // checkWithBrowser uses Playwright to check if username exists
@@ -252,42 +310,3 @@ func getRandomUserAgent() string {
 	rand.Seed(time.Now().UnixNano())
 	return userAgents[rand.Intn(len(userAgents))]
 }
-
-// formatAdvancedResults formats the search results
-func formatAdvancedResults(username string, results []UsernameResult) string {
-	var sb strings.Builder
-
-	sb.WriteString(fmt.Sprintf("Username: @%s\n", username))
-	sb.WriteString("Search Mode: Advanced (Browser Automation)\n\n")
-	sb.WriteString("Social Network Presence:\n")
-
-	foundCount := 0
-	for _, result := range results {
-		status := "Not Found"
-		if result.Found {
-			status = "Found ✓"
-			foundCount++
-		}
-		sb.WriteString(fmt.Sprintf("  %s: %s\n", result.Network, status))
-	}
-
-	sb.WriteString(fmt.Sprintf("\nTotal Found: %d out of %d networks\n", foundCount, len(results)))
-
-	// Add warnings
-	sb.WriteString("\n⚠️  IMPORTANT NOTES:\n")
-	sb.WriteString("- LinkedIn, Instagram, Facebook still require login for verification\n")
-	sb.WriteString("- This mode respects rate limits (slower but polite)\n")
-	sb.WriteString("- Always verify manually for critical investigations\n")
-	sb.WriteString("- Use only for authorized testing\n")
-
-	sb.WriteString("\nManual Verification URLs:\n")
-	sb.WriteString(fmt.Sprintf("  - https://linkedin.com/in/%s\n", username))
-	sb.WriteString(fmt.Sprintf("  - https://instagram.com/%s\n", username))
-	sb.WriteString(fmt.Sprintf("  - https://facebook.com/%s\n", username))
-
-	if foundCount > 0 {
-		sb.WriteString("\nRecent Activity: Check individual platforms for details\n")
-	}
-
-	return sb.String()
-}
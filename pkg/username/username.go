@@ -0,0 +1,278 @@
+package username
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/opsec"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+//go:embed platforms.json
+var platformsJSON []byte
+
+// Detection types used in platforms.json
+const (
+	ErrorTypeStatusCode  = "status_code"
+	ErrorTypeMessage     = "message"
+	ErrorTypeResponseURL = "response_url"
+)
+
+// Check outcomes reported for each platform
+const (
+	StatusFound    = "found"
+	StatusNotFound = "not found"
+	StatusUnknown  = "unknown"
+)
+
+// maxWorkers limits how many platforms are checked at the same time
+const maxWorkers = 8
+
+// maxBodySize limits how much of a profile page is read for message detection
+const maxBodySize = 1 << 20
+
+// Platform describes how to detect a profile on one site
+// URL contains {} where the username is inserted
+type Platform struct {
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	ErrorType string `json:"errorType"`
+	ErrorCode int    `json:"errorCode,omitempty"`
+	ErrorMsg  string `json:"errorMsg,omitempty"`
+	ErrorURL  string `json:"errorUrl,omitempty"`
+}
+
+// SocialNetwork represents a platform profile URL to check
+type SocialNetwork struct {
+	Name string
+	URL  string
+}
+
+// UsernameResult holds the check outcome for one platform
+type UsernameResult struct {
+	Network string `json:"network"`
+	URL     string `json:"url"`
+	Found   bool   `json:"found"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// UsernameInfo holds all platform results for a username
+type UsernameInfo struct {
+	Username string           `json:"username"`
+	Mode     string           `json:"mode"`
+	Results  []UsernameResult `json:"results"`
+}
+
+// usernameRegex allows letters, numbers, underscores, hyphens and dots
+// Dots are common on Instagram, Facebook and Pinterest and in email local parts
+var usernameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// isValidUsername checks that a username only contains allowed characters
+func isValidUsername(username string) bool {
+	return usernameRegex.MatchString(username)
+}
+
+// LoadPlatforms parses the embedded platforms.json
+func LoadPlatforms() ([]Platform, error) {
+	var data struct {
+		Platforms []Platform `json:"platforms"`
+	}
+
+	if err := json.Unmarshal(platformsJSON, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse platforms.json: %v", err)
+	}
+
+	return data.Platforms, nil
+}
+
+// Requests declares the profile pages a username search requests, without sending them
+// Advanced searches open a few profiles in a browser instead of checking every platform
+func Requests(username string, advanced bool) ([]opsec.Request, error) {
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+
+	var requests []opsec.Request
+	if advanced {
+		for _, network := range browserNetworks(username) {
+			requests = append(requests, opsec.Declare(network.Name, "browser_automation", "BROWSER", network.URL))
+		}
+		return requests, nil
+	}
+
+	platforms, err := LoadPlatforms()
+	if err != nil {
+		return nil, err
+	}
+	for _, platform := range platforms {
+		profileURL := strings.ReplaceAll(platform.URL, "{}", username)
+		requests = append(requests, opsec.Declare(platform.Name, "profile_check", "GET", profileURL))
+	}
+	return requests, nil
+}
+
+// SearchUsername checks every platform in platforms.json over plain HTTP
+// Platforms not checked before ctx ends are left out and the result is marked partial
+func SearchUsername(ctx context.Context, username string) (*result.Result, error) {
+	// Remove @ symbol if present
+	username = strings.TrimPrefix(strings.TrimSpace(username), "@")
+
+	if username == "" {
+		return nil, fmt.Errorf("username cannot be empty")
+	}
+
+	if !isValidUsername(username) {
+		return nil, fmt.Errorf("invalid username: only letters, numbers, underscores, hyphens and dots allowed")
+	}
+
+	platforms, err := LoadPlatforms()
+	if err != nil {
+		return nil, err
+	}
+
+	// Every platform check visits the profile, passive mode leaves them all out
+	if err := opsec.Allow(ctx, "profile_check", result.ModuleUsername, username); err != nil {
+		if !opsec.Skipped(err) {
+			return nil, err
+		}
+		res := result.New(result.ModuleUsername, username)
+		res.Data = &UsernameInfo{Username: username, Mode: "standard", Results: []UsernameResult{}}
+		return res, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "\nChecking %d platforms...\n", len(platforms))
+
+	results := make([]UsernameResult, len(platforms))
+	checked := make([]bool, len(platforms))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = checkPlatform(ctx, platforms[i], username)
+				// A request cut short by ctx tells us nothing about the platform
+				checked[i] = ctx.Err() == nil || results[i].Status != StatusUnknown
+			}
+		}()
+	}
+
+feed:
+	for i := range platforms {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	info := &UsernameInfo{
+		Username: username,
+		Mode:     "standard",
+		Results:  make([]UsernameResult, 0, len(results)),
+	}
+	for i, r := range results {
+		if checked[i] {
+			info.Results = append(info.Results, r)
+		}
+	}
+
+	res := result.New(result.ModuleUsername, username)
+	res.Interrupted(ctx)
+	for _, r := range info.Results {
+		switch r.Status {
+		case StatusFound:
+			res.Add("profile", r.URL, r.Network, result.ConfidenceMedium, r)
+		case StatusUnknown:
+			res.AddError(r.Network, fmt.Errorf("%s", r.Reason))
+		}
+	}
+	res.Data = info
+	return res, nil
+}
+
+// checkPlatform requests a profile URL and applies the platform's detection rule
+func checkPlatform(ctx context.Context, platform Platform, username string) UsernameResult {
+	profileURL := strings.ReplaceAll(platform.URL, "{}", username)
+
+	r := UsernameResult{
+		Network: platform.Name,
+		URL:     profileURL,
+		Status:  StatusUnknown,
+	}
+
+	client := httpclient.Default()
+
+	// Redirect detection needs to see the redirect itself
+	if platform.ErrorType == ErrorTypeResponseURL {
+		client = httpclient.NoRedirects()
+	}
+
+	resp, err := httpclient.Do(ctx, client, "GET", profileURL)
+	if err != nil {
+		r.Reason = err.Error()
+		return r
+	}
+	defer resp.Body.Close()
+
+	switch platform.ErrorType {
+	case ErrorTypeStatusCode:
+		errorCode := platform.ErrorCode
+		if errorCode == 0 {
+			errorCode = http.StatusNotFound
+		}
+		switch {
+		case resp.StatusCode == errorCode:
+			r.Status = StatusNotFound
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			r.Status = StatusFound
+		}
+
+	case ErrorTypeMessage:
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		if err != nil {
+			r.Reason = err.Error()
+			return r
+		}
+		switch {
+		case strings.Contains(strings.ToLower(string(body)), strings.ToLower(platform.ErrorMsg)):
+			r.Status = StatusNotFound
+		case resp.StatusCode == http.StatusNotFound:
+			r.Status = StatusNotFound
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			r.Status = StatusFound
+		}
+
+	case ErrorTypeResponseURL:
+		location := resp.Header.Get("Location")
+		switch {
+		case location != "" && platform.ErrorURL != "" && strings.Contains(location, strings.ReplaceAll(platform.ErrorURL, "{}", username)):
+			r.Status = StatusNotFound
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			r.Status = StatusFound
+		}
+
+	default:
+		r.Reason = fmt.Sprintf("unsupported errorType: %s", platform.ErrorType)
+		return r
+	}
+
+	if r.Status == StatusUnknown {
+		r.Reason = fmt.Sprintf("unexpected status code: %d", resp.StatusCode)
+	}
+	r.Found = r.Status == StatusFound
+
+	return r
+}
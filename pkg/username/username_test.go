@@ -0,0 +1,158 @@
+package username
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/internal/providertest"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// testUsername is the username looked up by the tests
+const testUsername = "janedoe"
+
+func TestCheckPlatform(t *testing.T) {
+	t.Parallel()
+
+	statusCode := Platform{Name: "StatusSite", URL: "/{}", ErrorType: ErrorTypeStatusCode}
+	goneCode := Platform{Name: "GoneSite", URL: "/{}", ErrorType: ErrorTypeStatusCode, ErrorCode: http.StatusGone}
+	message := Platform{Name: "MessageSite", URL: "/{}", ErrorType: ErrorTypeMessage, ErrorMsg: "User not found"}
+	responseURL := Platform{Name: "RedirectSite", URL: "/{}", ErrorType: ErrorTypeResponseURL, ErrorURL: "/login?next={}"}
+
+	tests := []struct {
+		name       string
+		platform   Platform
+		resp       providertest.Response
+		wantStatus string
+		wantReason string
+	}{
+		{"status code found", statusCode, providertest.Response{Body: "<h1>Jane Doe</h1>"}, StatusFound, ""},
+		{"status code not found", statusCode, providertest.Response{Status: http.StatusNotFound}, StatusNotFound, ""},
+		{"custom error code not found", goneCode, providertest.Response{Status: http.StatusGone}, StatusNotFound, ""},
+		{"custom error code ignores 404", goneCode, providertest.Response{Status: http.StatusNotFound}, StatusUnknown, "unexpected status code: 404"},
+		{"status code rate limited", statusCode, providertest.TooManyRequests, StatusUnknown, "unexpected status code: 429"},
+		{"message found", message, providertest.Response{Body: "<h1>Jane Doe</h1>"}, StatusFound, ""},
+		{"message not found", message, providertest.Response{Body: "<p>Sorry, USER NOT FOUND.</p>"}, StatusNotFound, ""},
+		{"message with 404", message, providertest.Response{Status: http.StatusNotFound}, StatusNotFound, ""},
+		{"message server error", message, providertest.Response{Status: http.StatusServiceUnavailable}, StatusUnknown, "unexpected status code: 503"},
+		{"response url not found", responseURL, providertest.Response{Status: http.StatusFound, Header: map[string]string{"Location": "/login?next=" + testUsername}}, StatusNotFound, ""},
+		{"response url found", responseURL, providertest.Response{Body: "<h1>Jane Doe</h1>"}, StatusFound, ""},
+		{"unsupported error type", Platform{Name: "OddSite", URL: "/{}", ErrorType: "json"}, providertest.Response{}, StatusUnknown, "unsupported errorType: json"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			providertest.UseTestClient(t)
+			stand := providertest.New(t, map[string]providertest.Response{"/" + testUsername: tc.resp})
+
+			platform := tc.platform
+			platform.URL = stand.URL + platform.URL
+			r := checkPlatform(context.Background(), platform, testUsername)
+
+			if r.Status != tc.wantStatus || r.Reason != tc.wantReason {
+				t.Errorf("status = %q, reason = %q, want %q, %q", r.Status, r.Reason, tc.wantStatus, tc.wantReason)
+			}
+			if r.Found != (tc.wantStatus == StatusFound) {
+				t.Errorf("found = %v with status %q", r.Found, r.Status)
+			}
+			if want := stand.URL + "/" + testUsername; r.URL != want {
+				t.Errorf("url = %q, want %q", r.URL, want)
+			}
+		})
+	}
+}
+
+func TestSearchUsername(t *testing.T) {
+	providertest.UseTestClient(t)
+	stand := providertest.New(t, map[string]providertest.Response{
+		"/found/" + testUsername:     {Body: "<h1>Jane Doe</h1>"},
+		"/missing/" + testUsername:   {Status: http.StatusNotFound},
+		"/message/" + testUsername:   {Body: "This account doesn't exist"},
+		"/profile/" + testUsername:   {Body: "<h1>Jane Doe</h1>"},
+		"/throttled/" + testUsername: providertest.TooManyRequests,
+	})
+
+	usePlatforms(t, []Platform{
+		{Name: "Found", URL: stand.URL + "/found/{}", ErrorType: ErrorTypeStatusCode},
+		{Name: "Missing", URL: stand.URL + "/missing/{}", ErrorType: ErrorTypeStatusCode},
+		{Name: "Message", URL: stand.URL + "/message/{}", ErrorType: ErrorTypeMessage, ErrorMsg: "doesn't exist"},
+		{Name: "Profile", URL: stand.URL + "/profile/{}", ErrorType: ErrorTypeMessage, ErrorMsg: "doesn't exist"},
+		{Name: "Throttled", URL: stand.URL + "/throttled/{}", ErrorType: ErrorTypeStatusCode},
+	})
+
+	res, err := SearchUsername(context.Background(), " @"+testUsername)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Module != result.ModuleUsername || res.Target != testUsername || res.Partial {
+		t.Errorf("module = %q, target = %q, partial = %v, want a complete %s result for %q",
+			res.Module, res.Target, res.Partial, result.ModuleUsername, testUsername)
+	}
+
+	statuses := make(map[string]string)
+	for _, r := range res.Data.(*UsernameInfo).Results {
+		statuses[r.Network] = r.Status
+	}
+	wantStatuses := map[string]string{
+		"Found":     StatusFound,
+		"Missing":   StatusNotFound,
+		"Message":   StatusNotFound,
+		"Profile":   StatusFound,
+		"Throttled": StatusUnknown,
+	}
+	if !reflect.DeepEqual(statuses, wantStatuses) {
+		t.Errorf("statuses = %v, want %v", statuses, wantStatuses)
+	}
+
+	// Only found profiles are findings, unknown outcomes are errors
+	var profiles []string
+	for _, f := range res.Find("profile") {
+		profiles = append(profiles, f.Source)
+	}
+	sort.Strings(profiles)
+	if want := []string{"Found", "Profile"}; !reflect.DeepEqual(profiles, want) {
+		t.Errorf("profiles from %v, want %v", profiles, want)
+	}
+	if len(res.Errors) != 1 || res.Errors[0].Provider != "Throttled" {
+		t.Errorf("errors = %+v, want the rate limited platform", res.Errors)
+	}
+}
+
+func TestSearchUsernameRejects(t *testing.T) {
+	tests := []struct {
+		username string
+		wantErr  string
+	}{
+		{"", "username cannot be empty"},
+		{"@", "username cannot be empty"},
+		{"jane doe", "invalid username"},
+		{"jane/doe", "invalid username"},
+	}
+
+	for _, tc := range tests {
+		_, err := SearchUsername(context.Background(), tc.username)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("SearchUsername(%q) error = %v, want one containing %q", tc.username, err, tc.wantErr)
+		}
+	}
+}
+
+// usePlatforms replaces the embedded platform list for one test
+func usePlatforms(t *testing.T, platforms []Platform) {
+	t.Helper()
+
+	data, err := json.Marshal(map[string][]Platform{"platforms": platforms})
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := platformsJSON
+	platformsJSON = data
+	t.Cleanup(func() { platformsJSON = original })
+}
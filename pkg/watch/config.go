@@ -0,0 +1,117 @@
+package watch
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/malika/osint-master/pkg/lookup"
+)
+
+// DefaultCase is the case watch runs are kept in when the config names none
+const DefaultCase = "watch"
+
+// Webhook formats
+const (
+	FormatJSON  = "json"  // the full change report
+	FormatSlack = "slack" // a Slack incoming webhook message
+)
+
+// Config is a watch.yaml file
+// ${VAR} references are expanded from the environment so webhook secrets stay out of the file
+type Config struct {
+	Case     string    `yaml:"case"`
+	Webhooks []Webhook `yaml:"webhooks"`
+	Watches  []*Watch  `yaml:"watches"`
+}
+
+// Webhook is an endpoint notified when a watch sees changes
+type Webhook struct {
+	URL     string            `yaml:"url"`
+	Format  string            `yaml:"format"` // json (default) or slack
+	Headers map[string]string `yaml:"headers"`
+}
+
+// Watch is one lookup re-run on a schedule
+type Watch struct {
+	Name     string   `yaml:"name"`
+	Module   string   `yaml:"module"`
+	Target   string   `yaml:"target"`
+	Schedule string   `yaml:"schedule"`
+	Advanced bool     `yaml:"advanced"`
+	Passive  bool     `yaml:"passive"` // skip active checks, see --passive
+	Fields   []string `yaml:"fields"`  // only report changes to these finding fields, e.g. takeover_risk
+
+	schedule Schedule
+}
+
+// LoadConfig reads and validates a watch file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watch config: %v", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &cfg, nil
+}
+
+// validate checks the config and fills in defaults
+func (c *Config) validate() error {
+	if c.Case == "" {
+		c.Case = DefaultCase
+	}
+	if len(c.Watches) == 0 {
+		return fmt.Errorf("no watches configured")
+	}
+
+	for i := range c.Webhooks {
+		hook := &c.Webhooks[i]
+		u, err := url.Parse(hook.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook %d: invalid URL %q", i+1, hook.URL)
+		}
+		if hook.Format == "" {
+			hook.Format = FormatJSON
+		}
+		if hook.Format != FormatJSON && hook.Format != FormatSlack {
+			return fmt.Errorf("webhook %d: unknown format %q (use json or slack)", i+1, hook.Format)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, w := range c.Watches {
+		if w == nil {
+			return fmt.Errorf("watch %d is empty", i+1)
+		}
+		if !lookup.Supported(w.Module) {
+			return fmt.Errorf("watch %d: unknown module %q (use %s)", i+1, w.Module, strings.Join(lookup.Modules(), ", "))
+		}
+		if strings.TrimSpace(w.Target) == "" {
+			return fmt.Errorf("watch %d: target cannot be empty", i+1)
+		}
+		if w.Name == "" {
+			w.Name = w.Module + ":" + w.Target
+		}
+		if names[w.Name] {
+			return fmt.Errorf("watch %d: duplicate name %q", i+1, w.Name)
+		}
+		names[w.Name] = true
+
+		schedule, err := ParseSchedule(w.Schedule)
+		if err != nil {
+			return fmt.Errorf("watch %s: %v", w.Name, err)
+		}
+		w.schedule = schedule
+	}
+	return nil
+}
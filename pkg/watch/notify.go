@@ -0,0 +1,98 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/malika/osint-master/pkg/diff"
+)
+
+// webhookClient posts notifications without the shared client's retries, cache, capture, audit or recording
+// A retried POST would notify twice, and a Slack webhook URL carries its secret in the path
+var webhookClient = &http.Client{Timeout: 15 * time.Second}
+
+// Notification is the JSON payload posted to generic webhooks
+type Notification struct {
+	Watch     string     `json:"watch"`
+	Module    string     `json:"module"`
+	Target    string     `json:"target"`
+	CheckedAt time.Time  `json:"checked_at"`
+	Diff      *diff.Diff `json:"diff"`
+}
+
+// slackMessage is the payload of a Slack incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// notify posts a notification to one webhook
+func notify(ctx context.Context, hook Webhook, n Notification) error {
+	var payload interface{} = n
+	if hook.Format == FormatSlack {
+		payload = slackMessage{Text: slackText(n)}
+	}
+
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(payload); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		// The error quotes the URL, keep only the host
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("webhook %s: %v", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return nil
+}
+
+// slackText summarises the changes for a chat message
+func slackText(n Notification) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(":rotating_light: *%s*: %d changes for %s `%s`\n", n.Watch, len(n.Diff.Changes), n.Module, n.Target))
+	sb.WriteString("```\n")
+	for _, c := range n.Diff.Changes {
+		field := c.Field
+		if c.Subject != "" {
+			field += " of " + c.Subject
+		}
+		switch c.Kind {
+		case diff.Added:
+			sb.WriteString(fmt.Sprintf("+ %s: %s\n", field, c.New))
+		case diff.Removed:
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", field, c.Old))
+		default:
+			sb.WriteString(fmt.Sprintf("~ %s: %s -> %s\n", field, c.Old, c.New))
+		}
+	}
+	sb.WriteString("```")
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/malika/osint-master/pkg/diff"
+)
+
+// testNotification is a notification of one changed field
+func testNotification() Notification {
+	return Notification{
+		Watch:     "example",
+		Module:    "domain",
+		Target:    "example.com",
+		CheckedAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		Diff:      &diff.Diff{Changes: []diff.Change{{Kind: diff.Changed, Field: "ip", Old: "1.1.1.1", New: "2.2.2.2"}}},
+	}
+}
+
+func TestNotify(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		status   int
+		wantErr  string
+		wantText string
+	}{
+		{name: "json", status: http.StatusOK},
+		{name: "slack", format: FormatSlack, status: http.StatusOK, wantText: "~ ip: 1.1.1.1 -> 2.2.2.2"},
+		// A failed POST is not sent again, the webhook may have acted on it
+		{name: "rate limited", status: http.StatusTooManyRequests, wantErr: "returned status 429"},
+		{name: "server error", status: http.StatusBadGateway, wantErr: "returned status 502"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var posts int32
+			var body map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&posts, 1)
+				json.NewDecoder(r.Body).Decode(&body)
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			hook := Webhook{URL: server.URL + "/services/T000/B000/secret", Format: tc.format}
+			err := notify(context.Background(), hook, testNotification())
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tc.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if n := atomic.LoadInt32(&posts); n != 1 {
+				t.Errorf("webhook received %d posts, want 1", n)
+			}
+			if tc.wantText != "" && !strings.Contains(body["text"].(string), tc.wantText) {
+				t.Errorf("text = %q, want it to contain %q", body["text"], tc.wantText)
+			}
+		})
+	}
+}
+
+func TestNotifyErrorHidesWebhookPath(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	err := notify(context.Background(), Webhook{URL: server.URL + "/services/T000/B000/secret"}, testNotification())
+	if err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error = %v, want the webhook path left out", err)
+	}
+}
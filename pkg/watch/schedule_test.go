@@ -0,0 +1,138 @@
+package watch
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// bits returns the field set allowing values
+func bits(values ...int) uint64 {
+	var set uint64
+	for _, v := range values {
+		set |= 1 << uint(v)
+	}
+	return set
+}
+
+func TestParseField(t *testing.T) {
+	tests := []struct {
+		field    string
+		min, max int
+		want     uint64
+		wantErr  string
+	}{
+		{field: "*", min: 0, max: 6, want: bits(0, 1, 2, 3, 4, 5, 6)},
+		{field: "5", min: 0, max: 59, want: bits(5)},
+		{field: "1,15,30", min: 1, max: 31, want: bits(1, 15, 30)},
+		{field: "9-12", min: 0, max: 23, want: bits(9, 10, 11, 12)},
+		{field: "*/15", min: 0, max: 59, want: bits(0, 15, 30, 45)},
+		{field: "*/5", min: 1, max: 12, want: bits(1, 6, 11)},
+		{field: "10-20/4", min: 0, max: 59, want: bits(10, 14, 18)},
+		// A single value with a step runs to the end of the range
+		{field: "50/3", min: 0, max: 59, want: bits(50, 53, 56, 59)},
+		{field: "0,6-7", min: 0, max: 7, want: bits(0, 6, 7)},
+		{field: "0", min: 0, max: 59, want: bits(0)},
+		{field: "59", min: 0, max: 59, want: bits(59)},
+
+		{field: "60", min: 0, max: 59, wantErr: "outside 0-59"},
+		{field: "0", min: 1, max: 31, wantErr: "outside 1-31"},
+		{field: "13", min: 1, max: 12, wantErr: "outside 1-12"},
+		{field: "8", min: 0, max: 7, wantErr: "outside 0-7"},
+		{field: "20-10", min: 0, max: 59, wantErr: "outside 0-59"},
+		{field: "*/0", min: 0, max: 59, wantErr: "invalid step"},
+		{field: "*/x", min: 0, max: 59, wantErr: "invalid step"},
+		{field: "*/-2", min: 0, max: 59, wantErr: "invalid step"},
+		{field: "a", min: 0, max: 59, wantErr: "invalid value"},
+		{field: "1-b", min: 0, max: 59, wantErr: "invalid range"},
+		{field: "1,,2", min: 0, max: 59, wantErr: "invalid value"},
+	}
+
+	for _, tc := range tests {
+		got, err := parseField(tc.field, tc.min, tc.max)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("parseField(%q, %d, %d) error = %v, want one containing %q", tc.field, tc.min, tc.max, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseField(%q, %d, %d): unexpected error: %v", tc.field, tc.min, tc.max, err)
+		} else if got != tc.want {
+			t.Errorf("parseField(%q, %d, %d) = %b, want %b", tc.field, tc.min, tc.max, got, tc.want)
+		}
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{"", "expected 5 cron fields"},
+		{"* * * *", "expected 5 cron fields"},
+		{"* * * * * *", "expected 5 cron fields"},
+		{"@fortnightly", "expected 5 cron fields"},
+		{"@every 30s", "at least 1m"},
+		{"@every soon", "at least 1m"},
+		{"0 24 * * *", "outside 0-23"},
+		{"0 0 32 * *", "outside 1-31"},
+		{"0 0 * 0 *", "outside 1-12"},
+		{"0 0 * * 8", "outside 0-7"},
+		{"*/0 * * * *", "invalid step"},
+	}
+
+	for _, tc := range tests {
+		if _, err := ParseSchedule(tc.spec); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("ParseSchedule(%q) error = %v, want one containing %q", tc.spec, err, tc.wantErr)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Friday 16 October 2026, 10:07:30
+	from := time.Date(2026, 10, 16, 10, 7, 30, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", at(10, 16, 10, 8)},
+		{"*/15 * * * *", at(10, 16, 10, 15)},
+		{"5 * * * *", at(10, 16, 11, 5)},
+		{"0 9-17/4 * * *", at(10, 16, 13, 0)},
+		{"30 8 * * *", at(10, 17, 8, 30)},
+		{"0 0 1 * *", at(11, 1, 0, 0)},
+		{"0 0 * 2 *", time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", at(10, 16, 11, 0)},
+		{"@daily", at(10, 17, 0, 0)},
+		{"@weekly", at(10, 18, 0, 0)},
+		{"@monthly", at(11, 1, 0, 0)},
+		{"@every 90m", from.Add(90 * time.Minute)},
+		// 7 is Sunday as well as 0
+		{"0 12 * * 7", at(10, 18, 12, 0)},
+		{"0 12 * * 1-5", at(10, 16, 12, 0)},
+		{"0 12 * * 6,0", at(10, 17, 12, 0)},
+		// A restricted day of month and day of week match either way
+		{"0 0 20 * 1", at(10, 19, 0, 0)},
+		{"0 0 17 * 3", at(10, 17, 0, 0)},
+		// A day of week with any day of month only matches that weekday
+		{"0 0 * * 3", at(10, 21, 0, 0)},
+		// February 30 never comes
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tc := range tests {
+		s, err := ParseSchedule(tc.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): unexpected error: %v", tc.spec, err)
+			continue
+		}
+		if got := s.Next(from); !got.Equal(tc.want) {
+			t.Errorf("%q: Next = %v, want %v", tc.spec, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package watch re-runs lookups on a schedule and reports what changed to webhooks
+// Runs are kept in a case so the previous result survives restarts
+package watch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/malika/osint-master/internal/casestore"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/pkg/diff"
+	"github.com/malika/osint-master/pkg/lookup"
+	"github.com/malika/osint-master/pkg/result"
+)
+
+// Watcher runs the watches of a config
+type Watcher struct {
+	cfg    *Config
+	runner *lookup.Runner
+	store  *casestore.Case
+}
+
+// New creates a watcher storing every run in store
+// The runner's observer is replaced so the raw responses of each run are kept
+func New(cfg *Config, runner *lookup.Runner, store *casestore.Case) *Watcher {
+	runner.Observe(func(res *result.Result, responses []httpclient.Response) {
+		if _, err := store.Add(res, responses); err != nil {
+			logf("Warning: %v", err)
+		}
+	})
+	return &Watcher{cfg: cfg, runner: runner, store: store}
+}
+
+// Run checks every watch on its schedule until ctx ends
+func (w *Watcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, watch := range w.cfg.Watches {
+		wg.Add(1)
+		go func(watch *Watch) {
+			defer wg.Done()
+			w.loop(ctx, watch)
+		}(watch)
+	}
+	wg.Wait()
+}
+
+// RunOnce checks every watch once, one after the other
+func (w *Watcher) RunOnce(ctx context.Context) {
+	for _, watch := range w.cfg.Watches {
+		if ctx.Err() != nil {
+			return
+		}
+		w.check(ctx, watch)
+	}
+}
+
+// loop checks one watch at each scheduled time
+func (w *Watcher) loop(ctx context.Context, watch *Watch) {
+	for {
+		next := watch.schedule.Next(time.Now())
+		if next.IsZero() {
+			logf("%s: schedule %q never runs", watch.Name, watch.Schedule)
+			return
+		}
+		logf("%s: next check at %s", watch.Name, next.Format("2006-01-02 15:04"))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		w.check(ctx, watch)
+	}
+}
+
+// check runs a watch and notifies the webhooks of any changes
+func (w *Watcher) check(ctx context.Context, watch *Watch) {
+	d, err := w.Check(ctx, watch)
+	switch {
+	case err != nil:
+		logf("%s: %v", watch.Name, err)
+		return
+	case d == nil:
+		logf("%s: first run stored as the baseline", watch.Name)
+		return
+	case d.Empty():
+		logf("%s: no changes", watch.Name)
+		return
+	}
+
+	logf("%s: %d changes", watch.Name, len(d.Changes))
+	n := Notification{Watch: watch.Name, Module: watch.Module, Target: watch.Target, CheckedAt: d.To, Diff: d}
+	for _, hook := range w.cfg.Webhooks {
+		if err := notify(ctx, hook, n); err != nil {
+			logf("%s: failed to notify webhook: %v", watch.Name, err)
+		}
+	}
+}
+
+// Check runs a watch and compares it with its last complete run
+// It returns nil without error for the first run, which becomes the baseline
+func (w *Watcher) Check(ctx context.Context, watch *Watch) (*diff.Diff, error) {
+	previous, err := w.previous(watch)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cached answer would hide the changes the watch is looking for
+	ctx = httpclient.WithCacheMode(ctx, httpclient.CacheRefresh)
+	res, err := w.runner.Run(ctx, watch.Module, watch.Target, lookup.Options{Advanced: watch.Advanced, Passive: watch.Passive})
+	if err != nil {
+		return nil, err
+	}
+	if res.Partial {
+		return nil, fmt.Errorf("lookup was cut short, not comparing a partial result")
+	}
+	if previous == nil {
+		return nil, nil
+	}
+
+	d := diff.Compare(previous, res)
+	if len(watch.Fields) > 0 {
+		d.Changes = filterFields(d.Changes, watch.Fields)
+	}
+	return d, nil
+}
+
+// previous returns the last complete run of a watch, nil when there is none
+func (w *Watcher) previous(watch *Watch) (*result.Result, error) {
+	records, err := w.store.Find(watch.Module, watch.Target)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if r := records[i]; !r.Failed && !r.Result.Partial {
+			return r.Result, nil
+		}
+	}
+	return nil, nil
+}
+
+// filterFields keeps the changes to the given fields
+func filterFields(changes []diff.Change, fields []string) []diff.Change {
+	kept := make([]diff.Change, 0, len(changes))
+	for _, c := range changes {
+		for _, field := range fields {
+			if c.Field == field {
+				kept = append(kept, c)
+				break
+			}
+		}
+	}
+	return kept
+}
+
+// logf prints a timestamped line to stderr
+func logf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
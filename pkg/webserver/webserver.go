@@ -0,0 +1,222 @@
+package webserver
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/audit"
+	"github.com/malika/osint-master/internal/httpclient"
+	"github.com/malika/osint-master/internal/output"
+	"github.com/malika/osint-master/internal/scope"
+	"github.com/malika/osint-master/pkg/lookup"
+	"github.com/malika/osint-master/pkg/render"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// apiPrefix is the base path of every API route
+const apiPrefix = "/api/v1/"
+
+// Server exposes the lookup modules over HTTP
+type Server struct {
+	runner   *lookup.Runner
+	operator string // who runs the server, set when lookups are audited
+}
+
+// errorResponse is the JSON body returned when a request fails
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewServer creates a server using the given configuration for API keys
+func NewServer(cfg *config.Config) *Server {
+	return &Server{runner: lookup.NewRunner(cfg)}
+}
+
+// Handler returns the HTTP handler serving the API and the web front end
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(apiPrefix, s.handleLookup)
+
+	static, _ := fs.Sub(staticFiles, "static")
+	mux.Handle("/", http.FileServer(http.FS(static)))
+
+	return mux
+}
+
+// DefaultHost keeps the server on the local machine, it has no authentication
+const DefaultHost = "127.0.0.1"
+
+// StartServer starts the web GUI and REST API on host and port
+// cfg must already be applied with lookup.Configure
+func StartServer(cfg *config.Config, host, port string) error {
+	if strings.Contains(port, ":") {
+		return fmt.Errorf("invalid port %q, choose the address with --web-host", port)
+	}
+	if host == "" {
+		host = DefaultHost
+	}
+	addr := net.JoinHostPort(host, port)
+
+	s := NewServer(cfg)
+	if cfg.ScopeFile != "" {
+		engagement, err := scope.Load(cfg.ScopeFile)
+		if err != nil {
+			return err
+		}
+		defer engagement.Close()
+		s.runner.Enforce(engagement)
+		fmt.Printf("Engagement scope: %s\n", engagement.Summary())
+	}
+
+	auditDir, err := audit.Dir(cfg)
+	if err != nil {
+		return err
+	}
+	s.operator = audit.Operator(cfg)
+	auditLog, err := audit.Open(auditDir, s.operator, "")
+	if err != nil {
+		return err
+	}
+	s.runner.Audit(auditLog)
+	fmt.Printf("Audit log: %s\n", auditLog.Path())
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	if !isLoopback(host) {
+		fmt.Fprintf(os.Stderr, "Warning: the web server has no authentication and is reachable on %s\n", host)
+	}
+	fmt.Printf("OSINT Master web server listening on http://%s\n", addr)
+	fmt.Printf("API base: http://%s%s\n", addr, apiPrefix)
+
+	return server.ListenAndServe()
+}
+
+// isLoopback reports whether host only accepts connections from the local machine
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// handleLookup serves /api/v1/{module}/{target} and /api/v1/name?q=...
+func (s *Server) handleLookup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "only GET is supported")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, apiPrefix)
+	module, target, _ := strings.Cut(path, "/")
+
+	// Names contain spaces, accept them as a query parameter as well
+	if target == "" {
+		target = r.URL.Query().Get("q")
+	}
+
+	if !lookup.Supported(module) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown module: %s", module))
+		return
+	}
+
+	if strings.TrimSpace(target) == "" {
+		writeError(w, http.StatusBadRequest, "target cannot be empty")
+		return
+	}
+
+	opts := lookup.Options{
+		Advanced:  r.URL.Query().Get("advanced") == "true",
+		Consensus: r.URL.Query().Get("consensus") == "true",
+		Passive:   r.URL.Query().Get("passive") == "true",
+	}
+
+	// Lookups stop when the client goes away or the optional timeout passes
+	ctx := r.Context()
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid timeout: %s", value))
+			return
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	switch r.URL.Query().Get("cache") {
+	case "off":
+		ctx = httpclient.WithCacheMode(ctx, httpclient.CacheBypass)
+	case "refresh":
+		ctx = httpclient.WithCacheMode(ctx, httpclient.CacheRefresh)
+	}
+	// The audit log names the client behind each lookup
+	if s.operator != "" {
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		ctx = audit.WithOperator(ctx, fmt.Sprintf("%s (web client %s)", s.operator, client))
+	}
+
+	res, err := s.runner.Run(ctx, module, target, opts)
+	if err != nil {
+		status := http.StatusBadGateway
+		var outOfScope *scope.OutOfScopeError
+		var invalid *lookup.InvalidTargetError
+		switch {
+		case errors.As(err, &invalid):
+			status = http.StatusBadRequest
+		case errors.Is(err, context.DeadlineExceeded):
+			status = http.StatusGatewayTimeout
+		case errors.As(err, &outOfScope):
+			status = http.StatusForbidden
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+
+	// The front end asks for the same text report the CLI prints
+	if r.URL.Query().Get("format") == output.FormatText {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, render.Text(res))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output.Record{
+		SchemaVersion: output.SchemaVersion,
+		Result:        res,
+	})
+}
+
+// writeJSON writes v as an indented JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -0,0 +1,74 @@
+package webserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/malika/osint-master/config"
+)
+
+func TestHandleLookupClientErrors(t *testing.T) {
+	s := NewServer(&config.Config{})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"unknown module", "/api/v1/car/ABC123", http.StatusNotFound},
+		{"empty target", "/api/v1/ip/", http.StatusBadRequest},
+		{"invalid IP", "/api/v1/ip/999.1.1.1", http.StatusBadRequest},
+		{"invalid email", "/api/v1/email/not-an-email", http.StatusBadRequest},
+		{"invalid phone", "/api/v1/phone/12ab", http.StatusBadRequest},
+		{"invalid domain", "/api/v1/domain/" + url.PathEscape("exa mple"), http.StatusBadRequest},
+		{"invalid username", "/api/v1/username/" + url.PathEscape("bad name!"), http.StatusBadRequest},
+		{"invalid timeout", "/api/v1/ip/8.8.8.8?timeout=soon", http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.wantStatus, rec.Body)
+			}
+			var body errorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || body.Error == "" {
+				t.Errorf("body = %q, %v, want a JSON error", rec.Body, err)
+			}
+		})
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"localhost", true},
+		{"::1", true},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"192.168.1.10", false},
+		{"example.com", false},
+	}
+
+	for _, tc := range tests {
+		if got := isLoopback(tc.host); got != tc.want {
+			t.Errorf("isLoopback(%q) = %v, want %v", tc.host, got, tc.want)
+		}
+	}
+}
+
+func TestStartServerRejectsAddressAsPort(t *testing.T) {
+	err := StartServer(&config.Config{}, DefaultHost, "0.0.0.0:8080")
+	if err == nil || !strings.Contains(err.Error(), "--web-host") {
+		t.Errorf("error = %v, want the address refused", err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/internal/scope"
+	"github.com/malika/osint-master/pkg/lookup"
+)
+
+// sampleScopeFile is printed by "osintmaster --scope-example"
+const sampleScopeFile = `# Active checks (TLS handshakes, takeover probes, browser automation) of targets
+# outside this scope are refused, or only warned about with mode: warn
+name: acme-pentest-2026
+valid_from: 2026-10-01
+valid_until: 2026-10-31
+mode: enforce
+
+# "acme.com" matches exactly, "*.acme.com" matches every subdomain
+domains: [acme.com, "*.acme.com"]
+cidrs: [203.0.113.0/24, 198.51.100.7]
+# Domains or full addresses
+email_domains: [acme.com, contractor@example.org]
+usernames: [acmecorp]
+phones: ["+14155552671"]
+names: [John Doe]
+
+# Every check is appended here, default ~/.osintmaster/scope/<name>.jsonl
+# log: /path/to/acme-scope.jsonl
+`
+
+// enforceScope holds the runner to the scope file of cfg, it returns nil when there is none
+func enforceScope(cfg *config.Config, runner *lookup.Runner) (*scope.Scope, error) {
+	if cfg.ScopeFile == "" {
+		return nil, nil
+	}
+	s, err := scope.Load(cfg.ScopeFile)
+	if err != nil {
+		return nil, err
+	}
+	if ok, why := s.Valid(time.Now()); !ok {
+		fmt.Fprintf(os.Stderr, "⚠️  Scope %s does not apply now: %s\n", s.Name, why)
+	}
+	fmt.Fprintf(os.Stderr, "Engagement scope: %s\n", s.Summary())
+	runner.Enforce(s)
+	return s, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/malika/osint-master/internal/validator"
+)
+
+// parseArgs parses the flags of fs and returns the positional arguments
+// Flags may appear before or after the targets, e.g. "osintmaster 8.8.8.8 --format json"
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var targets []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return targets, nil
+		}
+		targets = append(targets, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// detectSelector classifies a positional target, asking the user when it is ambiguous
+func detectSelector(target string) (selector, error) {
+	d, err := validator.Classify(target)
+
+	var ambiguous *validator.AmbiguousError
+	if errors.As(err, &ambiguous) && isTerminal(os.Stdin) {
+		d, err = chooseDetection(ambiguous)
+	}
+	if err != nil {
+		return selector{}, err
+	}
+
+	if d.Kind != d.Module {
+		fmt.Fprintf(os.Stderr, "Detected %s %s, running the %s lookup on %s\n", d.Kind, target, d.Module, d.Target)
+	}
+	return selector{module: d.Module, target: d.Target}, nil
+}
+
+// chooseDetection asks which interpretation of an ambiguous target to use
+func chooseDetection(e *validator.AmbiguousError) (validator.Detection, error) {
+	fmt.Fprintf(os.Stderr, "%q is ambiguous, it could be:\n", e.Target)
+	for i, c := range e.Candidates {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, c.Kind)
+	}
+	fmt.Fprintf(os.Stderr, "Choose [1-%d]: ", len(e.Candidates))
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return validator.Detection{}, e
+	}
+
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(e.Candidates) {
+		return validator.Detection{}, fmt.Errorf("invalid choice: %s", strings.TrimSpace(line))
+	}
+	return e.Candidates[choice-1], nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/malika/osint-master/config"
+	"github.com/malika/osint-master/pkg/lookup"
+	"github.com/malika/osint-master/pkg/watch"
+)
+
+// sampleWatchConfig is printed by "osintmaster watch --example"
+const sampleWatchConfig = `# Runs are kept in this case, see "osintmaster case show watch"
+case: watch
+
+webhooks:
+  - url: ${SLACK_WEBHOOK_URL}
+    format: slack
+  - url: https://hooks.example.com/osint
+    format: json
+    headers:
+      Authorization: Bearer ${HOOK_TOKEN}
+
+# schedule: cron fields (minute hour day month weekday), @hourly, @daily, @weekly or @every 6h
+watches:
+  - name: acme-attack-surface
+    module: domain
+    target: acme.com
+    schedule: "0 */6 * * *"
+    fields: [subdomain, ip, ssl_cert, takeover_risk]
+  - module: email
+    target: security@acme.com
+    schedule: "@daily"
+  - module: ip
+    target: 203.0.113.10
+    schedule: "@every 12h"
+`
+
+// runWatchCommand handles "osintmaster watch --config watch.yaml"
+func runWatchCommand(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	path := fs.String("config", "", "Watch file listing the lookups, schedules and webhooks")
+	once := fs.Bool("once", false, "Check every watch once and exit instead of running as a daemon")
+	example := fs.Bool("example", false, "Print an example watch file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *example {
+		fmt.Print(sampleWatchConfig)
+		return nil
+	}
+	if *path == "" {
+		return fmt.Errorf("usage: osintmaster watch --config watch.yaml [--once], see --example")
+	}
+
+	watchCfg, err := watch.LoadConfig(*path)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.LoadConfig()
+	if err := lookup.Configure(cfg); err != nil {
+		return err
+	}
+	defer lookup.Close()
+	store, err := openCase(cfg, watchCfg.Case)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runner := lookup.NewRunner(cfg)
+	engagement, err := enforceScope(cfg, runner)
+	if err != nil {
+		return err
+	}
+	if engagement != nil {
+		defer engagement.Close()
+	}
+	if err := auditLookups(cfg, runner, watchCfg.Case); err != nil {
+		return err
+	}
+
+	w := watch.New(watchCfg, runner, store)
+	if *once {
+		w.RunOnce(ctx)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Watching %d targets, press Ctrl+C to stop\n", len(watchCfg.Watches))
+	w.Run(ctx)
+	return nil
+}